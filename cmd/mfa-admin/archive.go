@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/micro/cli"
+	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const archivePassphraseEnv = "MFA_ARCHIVE_PASSPHRASE"
+
+// export writes the encrypted archive streamed by the service, the service
+// encrypts it with the passphrase or for the public key of the command.
+func (a *admin) export(c *cli.Context) (err error) {
+	if c.String("output") == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "output")
+	}
+
+	key, err := archiveKey(c, "")
+	if err != nil {
+		return err
+	}
+	req := &proto.MfaExportEnrollmentsDataRequest{
+		ProviderID: c.String("provider"),
+		Actor:      a.operator,
+		Passphrase: key.Passphrase,
+	}
+	if path := c.String("public_key"); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		req.PublicKey, req.Passphrase = string(data), ""
+	}
+
+	out := a.out
+	if c.String("output") != "-" {
+		f, err := os.OpenFile(c.String("output"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(c.String("output"))
+			}
+		}()
+		out = f
+	}
+
+	stream, err := a.client.ExportEnrollments(a.ctx, req)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	var written int
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err = out.Write(chunk.Data); err != nil {
+			return err
+		}
+		written += len(chunk.Data)
+	}
+
+	a.logger.Info("Export finished", zap.Int("bytes", written))
+
+	return nil
+}
+
+// restore reads the archive and sends its enrollments to the service in batches.
+func (a *admin) restore(c *cli.Context) error {
+	if c.String("file") == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "file")
+	}
+
+	key, err := archiveKey(c, c.String("private_key"))
+	if err != nil {
+		return err
+	}
+
+	in := io.Reader(os.Stdin)
+	if c.String("file") != "-" {
+		f, err := os.Open(c.String("file"))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	r, err := mfa.NewArchiveReader(in, key)
+	if err != nil {
+		return err
+	}
+
+	batch := c.Int("batch")
+	if batch <= 0 {
+		batch = defaultImportBatchSize
+	}
+
+	var offset int
+	var restored, skipped, failed int32
+	for done := false; !done; {
+		var enrollments []*proto.Enrollment
+		for len(enrollments) < batch {
+			e, err := r.Read()
+			if err == io.EOF {
+				done = true
+				break
+			}
+			if err != nil {
+				return err
+			}
+			enrollments = append(enrollments, e)
+		}
+		if len(enrollments) == 0 {
+			break
+		}
+
+		res, err := a.client.RestoreEnrollments(a.ctx, &proto.MfaRestoreEnrollmentsDataRequest{
+			Enrollments: enrollments,
+			Conflict:    c.String("conflict"),
+			Actor:       a.operator,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, result := range res.Results {
+			result.Index += int32(offset)
+			if err := a.print(result, ""); err != nil {
+				return err
+			}
+		}
+		offset += len(enrollments)
+		restored += res.Restored
+		skipped += res.Skipped
+		failed += res.Failed
+	}
+
+	a.logger.Info(
+		"Restore finished",
+		zap.Time("exportedAt", r.CreatedAt),
+		zap.Int32("restored", restored),
+		zap.Int32("skipped", skipped),
+		zap.Int32("failed", failed),
+	)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d enrollments failed to restore", failed, offset)
+	}
+
+	return nil
+}
+
+// archiveKey reads the passphrase of the archive and the RSA private key file.
+func archiveKey(c *cli.Context, privateKey string) (mfa.ArchiveKey, error) {
+	key := mfa.ArchiveKey{Passphrase: os.Getenv(archivePassphraseEnv)}
+
+	if path := c.String("passphrase_file"); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return key, err
+		}
+		key.Passphrase = strings.TrimRight(string(data), "\r\n")
+	}
+
+	if privateKey != "" {
+		data, err := ioutil.ReadFile(privateKey)
+		if err == nil {
+			key.PrivateKey, err = mfa.ParseArchivePrivateKey(data)
+		}
+		if err != nil {
+			return key, err
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/micro/go-micro/client"
+	"io"
+)
+
+// adminClient is the part of the MFA service used by the admin commands. It is
+// implemented by the service client and, in break-glass mode, by localClient.
+type adminClient interface {
+	GetUserStatus(ctx context.Context, in *proto.MfaGetUserStatusDataRequest, opts ...client.CallOption) (*proto.MfaGetUserStatusDataResponse, error)
+	ResetEnrollment(ctx context.Context, in *proto.MfaResetEnrollmentDataRequest, opts ...client.CallOption) (*proto.MfaResetEnrollmentDataResponse, error)
+	ClearLockout(ctx context.Context, in *proto.MfaClearLockoutDataRequest, opts ...client.CallOption) (*proto.MfaClearLockoutDataResponse, error)
+	QueryAuditEvents(ctx context.Context, in *proto.MfaQueryAuditEventsDataRequest, opts ...client.CallOption) (*proto.MfaQueryAuditEventsDataResponse, error)
+	IssueBypassCode(ctx context.Context, in *proto.MfaIssueBypassCodeDataRequest, opts ...client.CallOption) (*proto.MfaIssueBypassCodeDataResponse, error)
+	ImportEnrollment(ctx context.Context, in *proto.MfaImportEnrollmentDataRequest, opts ...client.CallOption) (*proto.MfaImportEnrollmentDataResponse, error)
+	ExportEnrollments(ctx context.Context, in *proto.MfaExportEnrollmentsDataRequest, opts ...client.CallOption) (proto.MfaService_ExportEnrollmentsService, error)
+	RestoreEnrollments(ctx context.Context, in *proto.MfaRestoreEnrollmentsDataRequest, opts ...client.CallOption) (*proto.MfaRestoreEnrollmentsDataResponse, error)
+}
+
+// localClient calls the handler in process, so commands work directly against
+// the storage while the service is unavailable.
+type localClient struct {
+	handler proto.MfaServiceHandler
+}
+
+func (c *localClient) GetUserStatus(ctx context.Context, in *proto.MfaGetUserStatusDataRequest, opts ...client.CallOption) (*proto.MfaGetUserStatusDataResponse, error) {
+	out := &proto.MfaGetUserStatusDataResponse{}
+	if err := c.handler.GetUserStatus(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localClient) ResetEnrollment(ctx context.Context, in *proto.MfaResetEnrollmentDataRequest, opts ...client.CallOption) (*proto.MfaResetEnrollmentDataResponse, error) {
+	out := &proto.MfaResetEnrollmentDataResponse{}
+	if err := c.handler.ResetEnrollment(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localClient) ClearLockout(ctx context.Context, in *proto.MfaClearLockoutDataRequest, opts ...client.CallOption) (*proto.MfaClearLockoutDataResponse, error) {
+	out := &proto.MfaClearLockoutDataResponse{}
+	if err := c.handler.ClearLockout(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localClient) QueryAuditEvents(ctx context.Context, in *proto.MfaQueryAuditEventsDataRequest, opts ...client.CallOption) (*proto.MfaQueryAuditEventsDataResponse, error) {
+	out := &proto.MfaQueryAuditEventsDataResponse{}
+	if err := c.handler.QueryAuditEvents(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localClient) IssueBypassCode(ctx context.Context, in *proto.MfaIssueBypassCodeDataRequest, opts ...client.CallOption) (*proto.MfaIssueBypassCodeDataResponse, error) {
+	out := &proto.MfaIssueBypassCodeDataResponse{}
+	if err := c.handler.IssueBypassCode(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localClient) ImportEnrollment(ctx context.Context, in *proto.MfaImportEnrollmentDataRequest, opts ...client.CallOption) (*proto.MfaImportEnrollmentDataResponse, error) {
+	out := &proto.MfaImportEnrollmentDataResponse{}
+	if err := c.handler.ImportEnrollment(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localClient) ExportEnrollments(ctx context.Context, in *proto.MfaExportEnrollmentsDataRequest, opts ...client.CallOption) (proto.MfaService_ExportEnrollmentsService, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream := &localExportStream{ctx: ctx, cancel: cancel, chunks: make(chan *proto.ArchiveChunk)}
+
+	go func() {
+		stream.err = c.handler.ExportEnrollments(ctx, in, stream)
+		close(stream.chunks)
+	}()
+
+	return stream, nil
+}
+
+func (c *localClient) RestoreEnrollments(ctx context.Context, in *proto.MfaRestoreEnrollmentsDataRequest, opts ...client.CallOption) (*proto.MfaRestoreEnrollmentsDataResponse, error) {
+	out := &proto.MfaRestoreEnrollmentsDataResponse{}
+	if err := c.handler.RestoreEnrollments(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// localExportStream passes the archive chunks sent by the handler running in a
+// goroutine to the command, it is both ends of the stream.
+type localExportStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	chunks chan *proto.ArchiveChunk
+	err    error
+}
+
+func (s *localExportStream) Send(c *proto.ArchiveChunk) error {
+	select {
+	case s.chunks <- c:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func (s *localExportStream) Recv() (*proto.ArchiveChunk, error) {
+	c, ok := <-s.chunks
+	if !ok {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	return c, nil
+}
+
+func (s *localExportStream) SendMsg(m interface{}) error {
+	return s.Send(m.(*proto.ArchiveChunk))
+}
+
+func (s *localExportStream) RecvMsg(m interface{}) error {
+	c, err := s.Recv()
+	if err != nil {
+		return err
+	}
+	protobuf.Merge(m.(protobuf.Message), c)
+	return nil
+}
+
+func (s *localExportStream) Close() error {
+	s.cancel()
+	return nil
+}
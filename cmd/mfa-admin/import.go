@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/micro/cli"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	importFormatURI  = "uri"
+	importFormatCSV  = "csv"
+	importFormatJSON = "json"
+
+	defaultImportBatchSize = 500
+)
+
+// importRecord is a record of a CSV or JSON import file, CSV files have a
+// header row with the same column names and recovery code hashes separated by
+// semicolons.
+type importRecord struct {
+	UserID             string   `json:"user_id"`
+	URI                string   `json:"uri"`
+	Secret             string   `json:"secret"`
+	Algorithm          string   `json:"algorithm"`
+	Digits             int32    `json:"digits"`
+	Period             int32    `json:"period"`
+	DeviceName         string   `json:"device_name"`
+	RecoveryCodeHashes []string `json:"recovery_code_hashes"`
+}
+
+// importEnrollment imports the file in batches and prints the result of every record.
+func (a *admin) importEnrollment(c *cli.Context) error {
+	if c.String("file") == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "file")
+	}
+
+	format := c.String("format")
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(c.String("file")), ".")
+	}
+
+	in := io.Reader(os.Stdin)
+	if c.String("file") != "-" {
+		f, err := os.Open(c.String("file"))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	records, err := readImportRecords(in, format)
+	if err != nil {
+		return err
+	}
+
+	batch := c.Int("batch")
+	if batch <= 0 {
+		batch = defaultImportBatchSize
+	}
+
+	var imported, failed int32
+	for offset := 0; offset < len(records); offset += batch {
+		end := offset + batch
+		if end > len(records) {
+			end = len(records)
+		}
+
+		res, err := a.client.ImportEnrollment(a.ctx, &proto.MfaImportEnrollmentDataRequest{
+			ProviderID: c.String("provider"),
+			Actor:      a.operator,
+			Records:    records[offset:end],
+		})
+		if err != nil {
+			return err
+		}
+		if res.Error != nil {
+			return errors.New(res.Error.Message)
+		}
+
+		for _, result := range res.Results {
+			result.Index += int32(offset)
+			if err := a.print(result, ""); err != nil {
+				return err
+			}
+		}
+		imported += res.Imported
+		failed += res.Failed
+	}
+
+	a.logger.Info("Import finished", zap.Int32("imported", imported), zap.Int32("failed", failed))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d records failed to import", failed, len(records))
+	}
+
+	return nil
+}
+
+func readImportRecords(in io.Reader, format string) ([]*proto.ImportRecord, error) {
+	var records []*importRecord
+
+	switch format {
+	case importFormatURI:
+		// Every line holds the user id and the otpauth URI separated by whitespace.
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("invalid line %q, expected user id and otpauth URI", line)
+			}
+			records = append(records, &importRecord{UserID: fields[0], URI: fields[1]})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	case importFormatCSV:
+		rows, err := csv.NewReader(in).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, nil
+		}
+
+		columns := map[string]int{}
+		for i, name := range rows[0] {
+			columns[strings.TrimSpace(name)] = i
+		}
+		if _, ok := columns["user_id"]; !ok {
+			return nil, errors.New("CSV header has no user_id column")
+		}
+
+		for line, row := range rows[1:] {
+			value := func(name string) string {
+				if i, ok := columns[name]; ok {
+					return strings.TrimSpace(row[i])
+				}
+				return ""
+			}
+
+			r := &importRecord{
+				UserID:     value("user_id"),
+				URI:        value("uri"),
+				Secret:     value("secret"),
+				Algorithm:  value("algorithm"),
+				DeviceName: value("device_name"),
+			}
+			for name, field := range map[string]*int32{"digits": &r.Digits, "period": &r.Period} {
+				if value(name) == "" {
+					continue
+				}
+				n, err := strconv.Atoi(value(name))
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s on line %d", name, line+2)
+				}
+				*field = int32(n)
+			}
+			if hashes := value("recovery_code_hashes"); hashes != "" {
+				r.RecoveryCodeHashes = strings.Split(hashes, ";")
+			}
+			records = append(records, r)
+		}
+	case importFormatJSON:
+		if err := json.NewDecoder(in).Decode(&records); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(mfa.ErrorRequestPropertyFormat, "format")
+	}
+
+	result := make([]*proto.ImportRecord, 0, len(records))
+	for _, r := range records {
+		result = append(result, &proto.ImportRecord{
+			UserID:             r.UserID,
+			URI:                r.URI,
+			Secret:             r.Secret,
+			Algorithm:          r.Algorithm,
+			Digits:             r.Digits,
+			Period:             r.Period,
+			DeviceName:         r.DeviceName,
+			RecoveryCodeHashes: r.RecoveryCodeHashes,
+		})
+	}
+
+	return result, nil
+}
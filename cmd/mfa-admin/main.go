@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"github.com/golang/protobuf/jsonpb"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/micro/cli"
+	"github.com/micro/go-micro"
+	"github.com/micro/go-micro/config/cmd"
+	"github.com/micro/go-micro/metadata"
+	"go.uber.org/zap"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	modeService    = "service"
+	modeBreakGlass = "break-glass"
+)
+
+// admin runs the commands against the service or, in break-glass mode, directly
+// against the storage. Every command is logged with the operator running it.
+type admin struct {
+	service  micro.Service
+	client   adminClient
+	ctx      context.Context
+	mode     string
+	operator string
+	logger   *zap.Logger
+	out      io.Writer
+}
+
+func main() {
+	logger, err := mfa.NewLogger(os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		log.Fatalf("Logger init failed with error: %s", err)
+	}
+	defer logger.Sync() // flushes buffer, if any
+
+	service := micro.NewService(
+		micro.Cmd(cmd.NewCmd(
+			cmd.Name("mfa-admin"),
+			cmd.Description("Support and operations tool of the MFA service"),
+		)),
+		micro.Flags(
+			cli.BoolFlag{
+				Name:   "break_glass",
+				EnvVar: "MFA_BREAK_GLASS",
+				Usage:  "Work directly with the storage when the service is unavailable",
+			},
+			cli.StringFlag{
+				Name:   "redis_addr",
+				EnvVar: "REDIS_ADDR",
+				Usage:  "Redis address used in break-glass mode",
+			},
+			cli.StringFlag{
+				Name:   "api_key",
+				EnvVar: "MFA_API_KEY",
+				Usage:  "API key passed to the service",
+			},
+			cli.StringFlag{
+				Name:   "operator",
+				EnvVar: "MFA_OPERATOR,USER",
+				Usage:  "Name of the operator recorded as a note in the audit log",
+			},
+		),
+		micro.Action(func(c *cli.Context) {
+			_ = cli.ShowAppHelp(c)
+		}),
+	)
+
+	a := &admin{service: service, logger: logger, out: os.Stdout}
+
+	service.Options().Cmd.App().Commands = a.commands()
+
+	service.Init()
+}
+
+func (a *admin) commands() []cli.Command {
+	userFlags := []cli.Flag{
+		cli.StringFlag{Name: "provider", Usage: "Provider id"},
+		cli.StringFlag{Name: "user", Usage: "User id"},
+	}
+	reasonFlag := cli.StringFlag{Name: "reason", Usage: "Reason recorded in the audit log"}
+	archiveFlags := []cli.Flag{
+		cli.StringFlag{Name: "passphrase_file", Usage: "File with the archive passphrase, MFA_ARCHIVE_PASSPHRASE by default"},
+	}
+
+	return []cli.Command{
+		{
+			Name:   "status",
+			Usage:  "Show factors, trusted devices and lockout of a user",
+			Flags:  userFlags,
+			Action: a.action(a.status),
+		},
+		{
+			Name:   "reset",
+			Usage:  "Remove all factors of a user so the user can enroll again",
+			Flags:  append(userFlags, reasonFlag),
+			Action: a.action(a.reset),
+		},
+		{
+			Name:   "unlock",
+			Usage:  "Clear the lockout and failed attempts of a user",
+			Flags:  append(userFlags, reasonFlag),
+			Action: a.action(a.unlock),
+		},
+		{
+			Name:  "bypass",
+			Usage: "Issue a single-use emergency bypass code",
+			Flags: append(userFlags, reasonFlag,
+				cli.DurationFlag{Name: "ttl", Usage: "Lifetime of the code, 15m by default"},
+			),
+			Action: a.action(a.bypass),
+		},
+		{
+			Name:  "audit",
+			Usage: "Export audit events as JSON lines",
+			Flags: append(userFlags,
+				cli.StringSliceFlag{Name: "type", Usage: "Event type, may be repeated"},
+				cli.StringFlag{Name: "from", Usage: "Export events created at or after the time, RFC 3339"},
+				cli.StringFlag{Name: "to", Usage: "Export events created at or before the time, RFC 3339"},
+				cli.IntFlag{Name: "limit", Usage: "Maximum number of events"},
+			),
+			Action: a.action(a.audit),
+		},
+		{
+			Name:  "import",
+			Usage: "Import TOTP secrets enrolled in another system and print the result of every record",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "provider", Usage: "Provider id"},
+				cli.StringFlag{Name: "file", Usage: "File with the records, - reads the standard input"},
+				cli.StringFlag{Name: "format", Usage: "uri, csv or json, the file extension by default"},
+				cli.IntFlag{Name: "batch", Usage: "Records sent in a request, 500 by default"},
+			},
+			Action: a.action(a.importEnrollment),
+		},
+		{
+			Name:  "export",
+			Usage: "Export enrollments to an archive encrypted with a passphrase or a public key",
+			Flags: append(archiveFlags,
+				cli.StringFlag{Name: "provider", Usage: "Provider id, all providers by default"},
+				cli.StringFlag{Name: "output", Usage: "Archive file, - writes the standard output"},
+				cli.StringFlag{Name: "public_key", Usage: "PEM file with the RSA public key the archive is encrypted for"},
+			),
+			Action: a.action(a.export),
+		},
+		{
+			Name:  "restore",
+			Usage: "Restore enrollments from an archive and print the result of every enrollment",
+			Flags: append(archiveFlags,
+				cli.StringFlag{Name: "file", Usage: "Archive file, - reads the standard input"},
+				cli.StringFlag{Name: "private_key", Usage: "PEM file with the RSA private key of an archive encrypted for a public key"},
+				cli.StringFlag{Name: "conflict", Usage: "Policy for users already enrolled: skip, overwrite or merge, skip by default"},
+				cli.IntFlag{Name: "batch", Usage: "Enrollments sent in a request, 500 by default"},
+			),
+			Action: a.action(a.restore),
+		},
+	}
+}
+
+// action connects the client before running the command and logs its outcome.
+func (a *admin) action(run func(c *cli.Context) error) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if err := a.connect(c); err != nil {
+			a.logger.Error("Connecting failed with error", zap.Error(err))
+
+			return err
+		}
+
+		err := run(c)
+
+		fields := []zap.Field{
+			zap.String("command", c.Command.Name),
+			zap.String("mode", a.mode),
+			zap.String("operator", a.operator),
+			zap.String("userId", c.String("user")),
+			zap.String("providerId", c.String("provider")),
+		}
+		if err != nil {
+			a.logger.Error("Admin command failed with error", append(fields, zap.Error(err))...)
+
+			return err
+		}
+		a.logger.Info("Admin command succeeded", fields...)
+
+		return nil
+	}
+}
+
+func (a *admin) connect(c *cli.Context) error {
+	a.operator = c.GlobalString("operator")
+	if a.operator == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "operator")
+	}
+
+	a.ctx = context.Background()
+
+	if !c.GlobalBool("break_glass") {
+		a.mode = modeService
+		a.client = proto.NewMfaService(mfa.ServiceName, a.service.Client())
+		if key := c.GlobalString("api_key"); key != "" {
+			a.ctx = metadata.NewContext(a.ctx, metadata.Metadata{mfa.APIKeyHeader: key})
+		}
+		return nil
+	}
+
+	addr := c.GlobalString("redis_addr")
+	if addr == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "redis_addr")
+	}
+
+	r := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+	if err := r.Ping().Err(); err != nil {
+		return err
+	}
+
+	a.logger.Warn("Running in break-glass mode directly against the storage", zap.String("operator", a.operator))
+
+	a.mode = modeBreakGlass
+	a.client = &localClient{handler: mfa.NewService(r, a.logger)}
+
+	return nil
+}
+
+func (a *admin) status(c *cli.Context) error {
+	res, err := a.client.GetUserStatus(a.ctx, &proto.MfaGetUserStatusDataRequest{
+		ProviderID: c.String("provider"),
+		UserID:     c.String("user"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(res, "  ")
+}
+
+func (a *admin) reset(c *cli.Context) error {
+	if c.String("reason") == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "reason")
+	}
+
+	res, err := a.client.ResetEnrollment(a.ctx, &proto.MfaResetEnrollmentDataRequest{
+		ProviderID: c.String("provider"),
+		UserID:     c.String("user"),
+		Actor:      a.operator,
+		Reason:     c.String("reason"),
+	})
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return errors.New(res.Error.Message)
+	}
+
+	return a.print(res, "  ")
+}
+
+func (a *admin) unlock(c *cli.Context) error {
+	if c.String("reason") == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "reason")
+	}
+
+	res, err := a.client.ClearLockout(a.ctx, &proto.MfaClearLockoutDataRequest{
+		ProviderID: c.String("provider"),
+		UserID:     c.String("user"),
+		Actor:      a.operator,
+		Reason:     c.String("reason"),
+	})
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return errors.New(res.Error.Message)
+	}
+
+	return a.print(res, "  ")
+}
+
+func (a *admin) bypass(c *cli.Context) error {
+	if c.String("reason") == "" {
+		return fmt.Errorf(mfa.ErrorRequestPropertyRequired, "reason")
+	}
+
+	res, err := a.client.IssueBypassCode(a.ctx, &proto.MfaIssueBypassCodeDataRequest{
+		ProviderID: c.String("provider"),
+		UserID:     c.String("user"),
+		Actor:      a.operator,
+		Reason:     c.String("reason"),
+		TTL:        int64(c.Duration("ttl") / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(res, "  ")
+}
+
+func (a *admin) audit(c *cli.Context) error {
+	req := &proto.MfaQueryAuditEventsDataRequest{
+		ProviderID: c.String("provider"),
+		UserID:     c.String("user"),
+		Types:      c.StringSlice("type"),
+		Limit:      int32(c.Int("limit")),
+	}
+
+	for name, value := range map[string]*int64{"from": &req.From, "to": &req.To} {
+		if c.String(name) == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, c.String(name))
+		if err != nil {
+			return fmt.Errorf(mfa.ErrorRequestPropertyFormat, name)
+		}
+		*value = t.Unix()
+	}
+
+	res, err := a.client.QueryAuditEvents(a.ctx, req)
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return errors.New(res.Error.Message)
+	}
+
+	for _, event := range res.Events {
+		if err := a.print(event, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *admin) print(msg protobuf.Message, indent string) error {
+	m := &jsonpb.Marshaler{OrigName: true, EmitDefaults: true, Indent: indent}
+	if err := m.Marshal(a.out, msg); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(a.out)
+	return err
+}
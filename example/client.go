@@ -6,6 +6,8 @@ import (
 	"github.com/ProtocolONE/mfa-service/pkg"
 	"github.com/ProtocolONE/mfa-service/pkg/proto"
 	"github.com/micro/go-micro"
+	"github.com/micro/go-micro/metadata"
+	"os"
 )
 
 func main() {
@@ -18,8 +20,14 @@ func main() {
 	// Create new greeter client
 	client := proto.NewMfaService(mfa.ServiceName, service.Client())
 
+	// Pass the API key when the service authorizes callers
+	ctx := context.TODO()
+	if key := os.Getenv("MFA_API_KEY"); key != "" {
+		ctx = metadata.NewContext(ctx, metadata.Metadata{mfa.APIKeyHeader: key})
+	}
+
 	// Call it
-	rsp, err := client.Create(context.TODO(), &proto.MfaCreateDataRequest{
+	rsp, err := client.Create(ctx, &proto.MfaCreateDataRequest{
 		ProviderID: "12312312312313",
 		AppName:    "Dummy",
 		UserID:     "12312312312313",
@@ -33,7 +41,7 @@ func main() {
 	fmt.Printf("%+v\n", rsp)
 
 	// Call it
-	rsp2, err2 := client.Check(context.TODO(), &proto.MfaCheckDataRequest{
+	rsp2, err2 := client.Check(ctx, &proto.MfaCheckDataRequest{
 		ProviderID: "12312312312313",
 		UserID:     "12312312312313",
 		Code:       "dummy",
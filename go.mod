@@ -6,17 +6,25 @@ require (
 	github.com/ProtocolONE/go-micro-plugins v0.3.0
 	github.com/boombuler/barcode v1.0.0 // indirect
 	github.com/go-redis/redis v6.15.1+incompatible
-	github.com/golang/protobuf v1.3.2
-	github.com/kelseyhightower/envconfig v1.3.0
+	github.com/golang/protobuf v1.5.3
+	github.com/micro/cli v0.2.0
 	github.com/micro/go-micro v1.8.0
 	github.com/micro/go-plugins v1.2.0
 	github.com/pquerna/otp v1.1.0
 	github.com/prometheus/client_golang v1.1.0
-	github.com/stretchr/testify v1.4.0
+	github.com/stretchr/testify v1.8.4
+	go.opentelemetry.io/otel v1.19.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.19.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.19.0
+	go.opentelemetry.io/otel/sdk v1.19.0
+	go.opentelemetry.io/otel/trace v1.19.0
 	go.uber.org/zap v1.10.0
+	golang.org/x/crypto v0.11.0
+	google.golang.org/grpc v1.58.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace (
 	github.com/hashicorp/consul => github.com/hashicorp/consul v1.5.1
-	golang.org/x/sys => golang.org/x/sys v0.0.0-20190927073244-c990c680b611
+	golang.org/x/sys => golang.org/x/sys v0.12.0
 )
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"github.com/InVisionApp/go-health"
 	"github.com/InVisionApp/go-health/handlers"
@@ -8,52 +11,177 @@ import (
 	"github.com/ProtocolONE/mfa-service/pkg"
 	"github.com/ProtocolONE/mfa-service/pkg/proto"
 	"github.com/go-redis/redis"
-	"github.com/kelseyhightower/envconfig"
+	protobuf "github.com/golang/protobuf/proto"
 	"github.com/micro/go-micro"
+	"github.com/micro/go-micro/broker"
+	"github.com/micro/go-micro/transport"
 	"github.com/micro/go-plugins/client/selector/static"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"io"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 )
 
+const (
+	serverModeMicro = "micro"
+	serverModeGRPC  = "grpc"
+
+	grpcServiceName = "proto.MfaService"
+)
+
+// Config is read from the CONFIG_FILE, then the env vars and then the flags,
+// see mfa.LoadConfig. The settings tagged reload are applied without restart
+// when the file changes.
 type Config struct {
-	RedisAddr   string `envconfig:"REDIS_ADDR" required:"true"`
-	MetricsPort int    `envconfig:"METRICS_PORT" required:"false" default:"8081"`
+	RedisAddr   string `config:"redis_addr" required:"true"`
+	LogLevel    string `config:"log_level" default:"info"`
+	LogFormat   string `config:"log_format" default:"json" oneof:"json,console"`
+	MetricsPort int    `config:"metrics_port" default:"8081"`
+
+	ServerMode  string `config:"server_mode" default:"micro" oneof:"micro,grpc"`
+	GrpcAddr    string `config:"grpc_addr" default:":50051"`
+	GrpcTLSCert string `config:"grpc_tls_cert"`
+	GrpcTLSKey  string `config:"grpc_tls_key"`
+	GrpcTLSCA   string `config:"grpc_tls_client_ca"`
+
+	ConfigReloadInterval time.Duration `config:"config_reload_interval" default:"10s"`
+
+	MicroTLSCert string `config:"micro_tls_cert"`
+	MicroTLSKey  string `config:"micro_tls_key"`
+	MicroTLSCA   string `config:"micro_tls_client_ca"`
+
+	AuthClientsFile string                  `config:"auth_clients_file"`
+	AuthInsecure    bool                    `config:"auth_insecure"`
+	ProvidersFile   string                  `config:"providers_file" reload:"true"`
+	Providers       []*proto.ProviderConfig `config:"providers" reload:"true"`
+
+	TrustedDeviceSecret string        `config:"trusted_device_secret"`
+	TrustedDeviceTTL    time.Duration `config:"trusted_device_ttl" default:"720h" reload:"true"`
+
+	AssertionIssuer    string        `config:"assertion_issuer" default:"p1mfa"`
+	AssertionTTL       time.Duration `config:"assertion_ttl" default:"5m" reload:"true"`
+	SigningKeyRotation time.Duration `config:"signing_key_rotation" default:"24h"`
+	SigningKeySecret   string        `config:"signing_key_secret"`
+
+	SecretRotationGrace time.Duration `config:"secret_rotation_grace" default:"168h" reload:"true"`
+
+	HealthCheckInterval time.Duration `config:"health_check_interval" default:"5s"`
+	ClockSkewThreshold  time.Duration `config:"clock_skew_threshold" default:"5s" reload:"true"`
+	ClockSkewPolicy     string        `config:"clock_skew_policy" default:"fail_closed" oneof:"fail_closed,ignore" reload:"true"`
+	ShutdownTimeout     time.Duration `config:"shutdown_timeout" default:"25s"`
+
+	LowRecoveryCodes int `config:"low_recovery_codes" default:"3" reload:"true"`
+
+	TracingExporter    string  `config:"tracing_exporter" default:"none" oneof:"none,otlp,stdout"`
+	TracingEndpoint    string  `config:"tracing_otlp_endpoint"`
+	TracingInsecure    bool    `config:"tracing_otlp_insecure"`
+	TracingSampleRatio float64 `config:"tracing_sample_ratio" default:"1"`
+
+	AuditSink   string `config:"audit_sink" default:"storage" oneof:"storage,file,broker"`
+	AuditFile   string `config:"audit_file" default:"audit.log"`
+	AuditTopic  string `config:"audit_topic" default:"mfa.audit"`
+	AuditMaxLen int64  `config:"audit_max_len" default:"1000000"`
+
+	AccountRecoveryDelay    time.Duration `config:"account_recovery_delay" default:"72h" reload:"true"`
+	AccountRecoveryReminder time.Duration `config:"account_recovery_reminder" default:"24h" reload:"true"`
+	Notifier                string        `config:"notifier" default:"log" oneof:"log,broker"`
+	NotifierTopic           string        `config:"notifier_topic" default:"mfa.recovery_notification"`
+
+	TopicEnrolled           string `config:"topic_enrolled" default:"mfa.enrolled"`
+	TopicRemoved            string `config:"topic_removed" default:"mfa.removed"`
+	TopicRecoveryCodeUsed   string `config:"topic_recovery_code_used" default:"mfa.recovery_code_used"`
+	TopicVerificationFailed string `config:"topic_verification_failed" default:"mfa.verification_failed"`
+	TopicLockedOut          string `config:"topic_locked_out" default:"mfa.locked_out"`
+	TopicBypassCodeIssued   string `config:"topic_bypass_code_issued" default:"mfa.bypass_code_issued"`
+	TopicBypassCodeUsed     string `config:"topic_bypass_code_used" default:"mfa.bypass_code_used"`
+
+	TopicUserDeleted string `config:"topic_user_deleted" default:"user.deleted"`
+	TopicUserMerged  string `config:"topic_user_merged" default:"user.merged"`
 }
 
-type customHealthCheck struct{}
+type healthChecker interface {
+	CheckStorage() (*mfa.StorageStatus, error)
+	CheckSigningKey() (*mfa.SigningKeyStatus, error)
+	SelfTest() error
+}
 
-func main() {
-	logger, _ := zap.NewProduction()
-	defer logger.Sync() // flushes buffer, if any
+type microHandler interface {
+	proto.MfaServiceHandler
+	HandleUserDeleted(ctx context.Context, msg *proto.UserDeleted) error
+	HandleUserMerged(ctx context.Context, msg *proto.UserMerged) error
+}
 
+// checkFunc adapts a function to a health check.
+type checkFunc func() (interface{}, error)
+
+func main() {
 	cfg := &Config{}
 
-	if err := envconfig.Process("", cfg); err != nil {
-		logger.Fatal("Config init failed with error", zap.Error(err))
+	configFile := mfa.ConfigFile(os.Args[1:])
+	if err := mfa.LoadConfig(cfg, configFile, os.Args[1:]); err != nil {
+		log.Fatalf("Config init failed with error: %s", err)
+	}
+
+	logger, err := mfa.NewLogger(cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatalf("Logger init failed with error: %s", err)
 	}
+	defer logger.Sync() // flushes buffer, if any
+
+	shutdownTracing := initTracing(cfg, logger)
+	defer shutdownTracing()
 
 	r := redis.NewClient(&redis.Options{
 		Addr: cfg.RedisAddr,
 	})
+	mfa.InstrumentStorage(r)
 
-	defer func() {
-		err := r.Close()
-		if err != nil {
-			logger.Error("redis shutdown with error", zap.Error(err))
-		}
-	}()
+	authorizer := initAuthorizer(cfg, logger)
 
 	var service micro.Service
 
+	drainer := mfa.NewDrainer()
 	options := []micro.Option{
 		micro.Name(mfa.ServiceName),
 		micro.Version(mfa.Version),
+		micro.Flags(mfa.ConfigFlags(cfg)...),
+		micro.WrapHandler(drainer.HandlerWrapper()),
+		micro.WrapSubscriber(drainer.SubscriberWrapper()),
+		micro.WrapHandler(mfa.CorrelationHandlerWrapper()),
+		micro.WrapHandler(mfa.TraceHandlerWrapper()),
 		micro.WrapHandler(prometheusPlugin.NewHandlerWrapper()),
 	}
+	if authorizer != nil {
+		options = append(options, micro.WrapHandler(authorizer.HandlerWrapper()))
+	}
+	if cfg.ServerMode != serverModeGRPC && cfg.MicroTLSCert != "" {
+		tlsConfig := serverTLSConfig("micro", cfg.MicroTLSCert, cfg.MicroTLSKey, cfg.MicroTLSCA, logger)
+		if authorizer != nil {
+			tlsConfig = authorizer.TLSConfig(tlsConfig)
+		}
+		options = append(options, micro.Transport(transport.NewTransport(transport.TLSConfig(tlsConfig))))
+	}
 
 	if os.Getenv("MICRO_SELECTOR") == "static" {
 		log.Println("Use micro selector `static`")
@@ -65,53 +193,578 @@ func main() {
 
 	service.Init()
 
-	err := proto.RegisterMfaServiceHandler(service.Server(), mfa.NewService(r, logger))
+	outbox := mfa.NewOutbox(r, logger)
+	topics := map[string]protobuf.Message{
+		cfg.TopicEnrolled:           &proto.MfaEnrolled{},
+		cfg.TopicRemoved:            &proto.MfaRemoved{},
+		cfg.TopicRecoveryCodeUsed:   &proto.RecoveryCodeUsed{},
+		cfg.TopicVerificationFailed: &proto.VerificationFailed{},
+		cfg.TopicLockedOut:          &proto.LockedOut{},
+		cfg.TopicBypassCodeIssued:   &proto.BypassCodeIssued{},
+		cfg.TopicBypassCodeUsed:     &proto.BypassCodeUsed{},
+	}
+	for topic, msg := range topics {
+		if topic != "" {
+			outbox.Register(msg, micro.NewPublisher(topic, service.Client()))
+		}
+	}
+
+	auditSink := initAuditSink(cfg, r, outbox, service, logger)
+	if closer, ok := auditSink.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// The background loops are stopped before the outbox is flushed on shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		outbox.Run(ctx)
+	}()
+
+	if cfg.SigningKeySecret == "" {
+		logger.Warn("SIGNING_KEY_SECRET is not set, signing keys are stored unencrypted")
+	}
+	serviceOptions := []mfa.Option{
+		mfa.TrustedDeviceSecret([]byte(cfg.TrustedDeviceSecret)),
+		mfa.AssertionIssuer(cfg.AssertionIssuer),
+		mfa.SigningKeyRotation(cfg.SigningKeyRotation),
+		mfa.SigningKeySecret([]byte(cfg.SigningKeySecret)),
+		mfa.Audit(auditSink),
+		mfa.EventOutbox(outbox),
+		mfa.RecoveryNotifier(initNotifier(cfg, outbox, service, logger)),
+	}
+	settings, err := settingOptions(cfg)
 	if err != nil {
-		logger.Fatal("Register MfaServiceHandler failed with error", zap.Error(err))
+		logger.Fatal("Load provider configs failed with error", zap.Error(err))
+	}
+
+	mfaService := mfa.NewService(r, logger, append(serviceOptions, settings...)...)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		if err := mfaService.IndexUserProviders(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Building the provider index of the users failed with error", zap.Error(err))
+		}
+	}()
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		mfaService.RunAccountRecovery(ctx)
+	}()
+
+	if (configFile != "" || cfg.ProvidersFile != "") && cfg.ConfigReloadInterval > 0 {
+		reload, paths := reloadConfig(cfg, configFile, serviceOptions, mfaService.Reload, logger)
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			mfa.WatchConfig(ctx, paths, cfg.ConfigReloadInterval, reload, logger)
+		}()
 	}
 
-	initHealth(cfg, logger)
-	initMetrics()
+	subscriptions := map[string]func(context.Context, []byte) error{
+		cfg.TopicUserDeleted: func(ctx context.Context, body []byte) error {
+			msg := &proto.UserDeleted{}
+			if err := protobuf.Unmarshal(body, msg); err != nil {
+				return err
+			}
+			return mfaService.HandleUserDeleted(ctx, msg)
+		},
+		cfg.TopicUserMerged: func(ctx context.Context, body []byte) error {
+			msg := &proto.UserMerged{}
+			if err := protobuf.Unmarshal(body, msg); err != nil {
+				return err
+			}
+			return mfaService.HandleUserMerged(ctx, msg)
+		},
+	}
 
+	ready := initHealth(cfg, mfaService, outbox, drainer, logger)
+	initMetrics(mfaService.MetricsCollector())
+	http.Handle("/.well-known/jwks.json", mfaService.JWKSHandler())
+	// The REST API shares the port with the metrics, it is not served without authorization.
+	if authorizer != nil {
+		http.Handle(mfa.GatewayPrefix, drainer.Handler(mfa.NewGateway(mfaService, authorizer, logger)))
+	} else {
+		logger.Warn("AUTH_CLIENTS_FILE is not set, the REST API is not served")
+	}
+
+	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", cfg.MetricsPort)}
 	go func() {
-		if err = http.ListenAndServe(fmt.Sprintf(":%d", cfg.MetricsPort), nil); err != nil {
-			logger.Fatal("Metrics listen failed")
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Metrics listen failed", zap.Error(err))
 		}
 	}()
 
-	if err := service.Run(); err != nil {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	var drain func(context.Context)
+	var stop func()
+	if cfg.ServerMode == serverModeGRPC {
+		drain, stop = runGRPC(ctx, &background, cfg, service, mfaService, authorizer, subscriptions, ready, drainer, logger)
+	} else {
+		drain, stop = runMicro(cfg, service, mfaService, drainer, logger)
+	}
+
+	sig := <-signals
+	logger.Info("Shutting down", zap.String("signal", sig.String()), zap.Duration("timeout", cfg.ShutdownTimeout))
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+
+	drain(shutdownCtx)
+
+	cancel()
+	background.Wait()
+	if err := outbox.Flush(shutdownCtx); err != nil {
+		logger.Error("Outbox flush failed with error, messages are kept for the next instance", zap.Error(err))
+	}
+
+	stop()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP server shutdown with error", zap.Error(err))
+	}
+	if err := r.Close(); err != nil {
+		logger.Error("redis shutdown with error", zap.Error(err))
+	}
+
+	logger.Info("Shutdown finished")
+}
+
+// settingOptions returns the service options of the settings applied again by
+// a reload. The providers of the config take precedence over the ones of the
+// PROVIDERS_FILE, which is read again on every reload.
+func settingOptions(cfg *Config) ([]mfa.Option, error) {
+	options := []mfa.Option{
+		mfa.TrustedDeviceTTL(cfg.TrustedDeviceTTL),
+		mfa.AssertionTTL(cfg.AssertionTTL),
+		mfa.AccountRecoveryDelay(cfg.AccountRecoveryDelay),
+		mfa.AccountRecoveryReminder(cfg.AccountRecoveryReminder),
+		mfa.SecretRotationGrace(cfg.SecretRotationGrace),
+		mfa.ClockSkewThreshold(cfg.ClockSkewThreshold),
+		mfa.ClockSkewPolicy(cfg.ClockSkewPolicy),
+		mfa.LowRecoveryCodes(cfg.LowRecoveryCodes),
+	}
+	if err := mfa.ValidateProviderConfigs(cfg.Providers); err != nil {
+		return nil, err
+	}
+	providers := cfg.Providers
+	if cfg.ProvidersFile != "" {
+		configs, err := mfa.LoadProviderConfigs(cfg.ProvidersFile)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(configs, providers...)
+	}
+
+	return append(options, mfa.ProviderConfigs(providers...)), nil
+}
+
+// reloadConfig returns the reload and the watched paths of mfa.WatchConfig: the
+// config file and the PROVIDERS_FILE of the running config. The settings are
+// applied to the service with the options set at startup, changes of the other
+// keys are only logged as they need a restart.
+func reloadConfig(cfg *Config, path string, serviceOptions []mfa.Option, apply func(...mfa.Option), logger *zap.Logger) (func() ([]string, error), func() []string) {
+	current := *cfg
+
+	paths := func() []string {
+		return []string{path, current.ProvidersFile}
+	}
+
+	reload := func() ([]string, error) {
+		next := &Config{}
+		if err := mfa.LoadConfig(next, path, os.Args[1:]); err != nil {
+			return nil, err
+		}
+
+		settings, err := settingOptions(next)
+		if err != nil {
+			return nil, err
+		}
+
+		reloaded, restart, err := mfa.ReloadConfig(&current, next)
+		if err != nil {
+			return nil, err
+		}
+		if len(restart) > 0 {
+			logger.Warn("Config changes are applied on restart", zap.Strings("keys", restart))
+		}
+
+		options := append([]mfa.Option{}, serviceOptions...)
+		apply(append(options, settings...)...)
+
+		return reloaded, nil
+	}
+
+	return reload, paths
+}
+
+// runMicro serves the MfaService with go-micro. The returned drain function
+// deregisters the service and waits for the calls in flight, stop closes the
+// server and disconnects the broker.
+func runMicro(cfg *Config, service micro.Service, handler microHandler, drainer *mfa.Drainer, logger *zap.Logger) (func(context.Context), func()) {
+	err := proto.RegisterMfaServiceHandler(service.Server(), handler)
+	if err != nil {
+		logger.Fatal("Register MfaServiceHandler failed with error", zap.Error(err))
+	}
+
+	if cfg.TopicUserDeleted != "" {
+		if err = micro.RegisterSubscriber(cfg.TopicUserDeleted, service.Server(), handler.HandleUserDeleted); err != nil {
+			logger.Fatal("Register user deleted subscriber failed with error", zap.Error(err))
+		}
+	}
+	if cfg.TopicUserMerged != "" {
+		if err = micro.RegisterSubscriber(cfg.TopicUserMerged, service.Server(), handler.HandleUserMerged); err != nil {
+			logger.Fatal("Register user merged subscriber failed with error", zap.Error(err))
+		}
+	}
+
+	if err = service.Server().Start(); err != nil {
 		logger.Fatal("service run failed with error", zap.Error(err))
 	}
+
+	drain := func(ctx context.Context) {
+		// The server deregisters itself on stop as well, but only after the calls in flight are done.
+		if s, ok := service.Server().(interface{ Deregister() error }); ok {
+			if err := s.Deregister(); err != nil {
+				logger.Error("Service deregister failed with error", zap.Error(err))
+			}
+		}
+
+		drainer.Close()
+		if err := drainer.Wait(ctx); err != nil {
+			logger.Error("Calls in flight did not finish before the shutdown timeout", zap.Error(err))
+		}
+	}
+	stop := func() {
+		if err := service.Server().Stop(); err != nil {
+			logger.Error("Service stop failed with error", zap.Error(err))
+		}
+	}
+
+	return drain, stop
 }
 
-func initHealth(cfg *Config, logger *zap.Logger) {
-	h := health.New()
-	err := h.AddChecks([]*health.Config{
-		{
-			Name:     "health-check",
-			Checker:  &customHealthCheck{},
-			Interval: time.Duration(1) * time.Second,
-			Fatal:    true,
-		},
-	})
+// runGRPC serves the MfaService as a plain gRPC server. The go-micro broker is
+// still used to publish and consume events. The returned drain function stops
+// the server gracefully, stop disconnects the broker. The serving status is
+// updated in the background until ctx is done.
+func runGRPC(ctx context.Context, background *sync.WaitGroup, cfg *Config, service micro.Service, handler proto.MfaServiceHandler, authorizer *mfa.Authorizer, subscriptions map[string]func(context.Context, []byte) error, ready health.IHealth, drainer *mfa.Drainer, logger *zap.Logger) (func(context.Context), func()) {
+	b := service.Options().Broker
+	if err := b.Connect(); err != nil {
+		logger.Fatal("Broker connect failed with error", zap.Error(err))
+	}
+
+	for topic, handle := range subscriptions {
+		if topic == "" {
+			continue
+		}
+		handle := handle
+		_, err := b.Subscribe(topic, func(e broker.Event) error {
+			return drainer.Do(func() error {
+				return handle(context.Background(), e.Message().Body)
+			})
+		})
+		if err != nil {
+			logger.Fatal("Broker subscribe failed with error", zap.Error(err), zap.String("topic", topic))
+		}
+	}
+
+	var opts []grpc.ServerOption
+	if cfg.GrpcTLSCert != "" {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(serverTLSConfig("gRPC", cfg.GrpcTLSCert, cfg.GrpcTLSKey, cfg.GrpcTLSCA, logger))))
+	}
+	unary := []grpc.UnaryServerInterceptor{drainer.UnaryInterceptor(), mfa.CorrelationUnaryInterceptor(), mfa.TraceUnaryInterceptor()}
+	stream := []grpc.StreamServerInterceptor{drainer.StreamInterceptor()}
+	if authorizer != nil {
+		unary = append(unary, authorizer.UnaryInterceptor())
+		stream = append(stream, authorizer.StreamInterceptor())
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+
+	server := grpc.NewServer(opts...)
+	proto.RegisterMfaServiceServer(server, mfa.NewGRPCServer(handler, logger))
+
+	healthServer := grpcHealth.NewServer()
+	healthServer.SetServingStatus(grpcServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+
+		// The serving status follows the readiness checks.
+		ticker := time.NewTicker(cfg.HealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			status := healthpb.HealthCheckResponse_SERVING
+			if states, failed, err := ready.State(); err != nil || failed || len(states) == 0 || drainer.Closed() {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+			healthServer.SetServingStatus(grpcServiceName, status)
+		}
+	}()
+	reflection.Register(server)
 
+	lis, err := net.Listen("tcp", cfg.GrpcAddr)
 	if err != nil {
-		logger.Fatal("Health check register failed with error", zap.Error(err))
+		logger.Fatal("gRPC listen failed with error", zap.Error(err))
+	}
+
+	logger.Info("gRPC server listening", zap.String("addr", cfg.GrpcAddr), zap.Bool("tls", cfg.GrpcTLSCert != ""))
+
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			logger.Fatal("gRPC serve failed with error", zap.Error(err))
+		}
+	}()
+
+	drain := func(ctx context.Context) {
+		healthServer.Shutdown()
+		drainer.Close()
+
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			logger.Error("gRPC calls in flight did not finish before the shutdown timeout")
+			server.Stop()
+		}
+
+		// Calls of the REST API and messages of the subscribers.
+		if err := drainer.Wait(ctx); err != nil {
+			logger.Error("Calls in flight did not finish before the shutdown timeout", zap.Error(err))
+		}
+	}
+	stop := func() {
+		if err := b.Disconnect(); err != nil {
+			logger.Error("Broker disconnect failed with error", zap.Error(err))
+		}
+	}
+
+	return drain, stop
+}
+
+// serverTLSConfig loads the certificate of the server, client certificates are
+// verified against the CA when one is set. Certificates stay optional so
+// clients may authenticate with an API key instead.
+func serverTLSConfig(server string, certFile string, keyFile string, caFile string, logger *zap.Logger) *tls.Config {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		logger.Fatal("Load TLS certificate failed with error", zap.Error(err), zap.String("server", server))
+	}
+
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if caFile != "" {
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			logger.Fatal("Load client CA failed with error", zap.Error(err), zap.String("server", server))
+		}
+
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(ca) {
+			logger.Fatal("Client CA contains no certificates", zap.String("server", server))
+		}
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return tlsConfig
+}
+
+// initTracing registers the tracer provider exporting spans with TRACING_EXPORTER,
+// "otlp" sends them to an OTLP/HTTP collector and "stdout" prints them for local
+// debugging. The returned function flushes the pending spans.
+func initTracing(cfg *Config, logger *zap.Logger) func() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.TracingExporter {
+	case "none":
+		return func() {}
+	case "otlp":
+		// The OTEL_EXPORTER_OTLP_* variables apply when no endpoint is set.
+		var opts []otlptracehttp.Option
+		if cfg.TracingEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.TracingEndpoint))
+		}
+		if cfg.TracingInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		logger.Fatal("Unknown tracing exporter", zap.String("exporter", cfg.TracingExporter))
+	}
+	if err != nil {
+		logger.Fatal("Tracing exporter init failed with error", zap.Error(err))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(mfa.ServiceName),
+			semconv.ServiceVersion(mfa.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.Error("Tracing shutdown with error", zap.Error(err))
+		}
+	}
+}
+
+func initAuthorizer(cfg *Config, logger *zap.Logger) *mfa.Authorizer {
+	if cfg.AuthClientsFile == "" {
+		if !cfg.AuthInsecure {
+			logger.Fatal("AUTH_CLIENTS_FILE is not set, set AUTH_INSECURE to accept calls without authorization")
+		}
+		logger.Warn("AUTH_CLIENTS_FILE is not set and AUTH_INSECURE is set, calls are not authorized")
+		return nil
+	}
+
+	clients, err := mfa.LoadAuthClients(cfg.AuthClientsFile)
+	if err != nil {
+		logger.Fatal("Load auth clients failed with error", zap.Error(err))
+	}
+
+	authorizer, err := mfa.NewAuthorizer(clients, logger)
+	if err != nil {
+		logger.Fatal("Auth clients are invalid", zap.Error(err))
+	}
+
+	return authorizer
+}
+
+// initHealth serves the liveness probe at /live and the readiness probe at /ready,
+// /health is kept as an alias of /ready. The readiness checks run in the background
+// and report the state of every component in the JSON output.
+func initHealth(cfg *Config, service healthChecker, outbox *mfa.Outbox, drainer *mfa.Drainer, logger *zap.Logger) health.IHealth {
+	checks := map[string]checkFunc{
+		"storage": func() (interface{}, error) {
+			return service.CheckStorage()
+		},
+		"broker": func() (interface{}, error) {
+			return outbox.Status()
+		},
+		"signing_key": func() (interface{}, error) {
+			return service.CheckSigningKey()
+		},
+		"self_test": func() (interface{}, error) {
+			return nil, service.SelfTest()
+		},
+	}
+
+	h := health.New()
+	for name, check := range checks {
+		err := h.AddCheck(&health.Config{
+			Name:     name,
+			Checker:  check,
+			Interval: cfg.HealthCheckInterval,
+			Fatal:    true,
+		})
+		if err != nil {
+			logger.Fatal("Health check register failed with error", zap.Error(err))
+		}
 	}
 
 	logger.Info("Health check listening on port", zap.Int("port", cfg.MetricsPort))
 
-	if err = h.Start(); err != nil {
+	if err := h.Start(); err != nil {
 		logger.Fatal("Health check start failed with error", zap.Error(err))
 	}
 
-	http.HandleFunc("/health", handlers.NewJSONHandlerFunc(h, nil))
+	http.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})
+	http.HandleFunc("/ready", readyHandler(h, len(checks), drainer))
+	http.HandleFunc("/health", readyHandler(h, len(checks), drainer))
+
+	return h
+}
+
+// readyHandler answers 503 until every check has run once, the go-health handler
+// reports ok while the checks are still spinning up. The service is not ready
+// any more once it is shutting down.
+func readyHandler(h health.IHealth, checks int, drainer *mfa.Drainer) http.HandlerFunc {
+	handler := handlers.NewJSONHandlerFunc(h, nil)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if drainer.Closed() {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"failed","message":"Service is shutting down"}`))
+			return
+		}
+		if states, _, err := h.State(); err != nil || len(states) < checks {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"failed","message":"Health checks are spinning up"}`))
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func initAuditSink(cfg *Config, r *redis.Client, outbox *mfa.Outbox, service micro.Service, logger *zap.Logger) mfa.AuditSink {
+	switch cfg.AuditSink {
+	case "storage":
+		return mfa.NewStorageAuditSink(r, cfg.AuditMaxLen)
+	case "file":
+		sink, err := mfa.NewFileAuditSink(cfg.AuditFile)
+		if err != nil {
+			logger.Fatal("Audit file open failed with error", zap.Error(err))
+		}
+		return sink
+	case "broker":
+		outbox.Register(&proto.AuditEvent{}, micro.NewPublisher(cfg.AuditTopic, service.Client()))
+		return mfa.NewBrokerAuditSink(outbox)
+	}
+
+	logger.Fatal("Unknown audit sink", zap.String("sink", cfg.AuditSink))
+	return nil
+}
+
+func initNotifier(cfg *Config, outbox *mfa.Outbox, service micro.Service, logger *zap.Logger) mfa.Notifier {
+	switch cfg.Notifier {
+	case "log":
+		return mfa.NewLogNotifier(logger)
+	case "broker":
+		outbox.Register(&proto.RecoveryNotification{}, micro.NewPublisher(cfg.NotifierTopic, service.Client()))
+		return mfa.NewBrokerNotifier(outbox)
+	}
+
+	logger.Fatal("Unknown notifier", zap.String("notifier", cfg.Notifier))
+	return nil
 }
 
-func initMetrics() {
+func initMetrics(collector prometheus.Collector) {
+	prometheus.MustRegister(collector)
 	http.Handle("/metrics", promhttp.Handler())
 }
 
-func (c *customHealthCheck) Status() (interface{}, error) {
-	return "ok", nil
+func (f checkFunc) Status() (interface{}, error) {
+	return f()
 }
@@ -0,0 +1,377 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+const (
+	mfaAccountRecoveryStoragePattern = "mfa_account_recovery_%s_%s"
+	mfaAccountRecoverySchedule       = "mfa_account_recoveries"
+	accountRecoveryPollInterval      = time.Minute
+	accountRecoveryBatchSize         = 100
+	// accountRecoveryLease is how long a claimed recovery is left to its instance
+	// before another instance may claim it again.
+	accountRecoveryLease = 5 * time.Minute
+
+	RecoveryNotificationRequested = "requested"
+	RecoveryNotificationReminder  = "reminder"
+	RecoveryNotificationCancelled = "cancelled"
+	RecoveryNotificationCompleted = "completed"
+)
+
+// accountRecovery is a pending reset of the enrollment requested by the user.
+// The schedule holds its storage key scored by the time of the next reminder
+// or of the completion.
+type accountRecovery struct {
+	UserID      string `json:"user_id"`
+	ProviderID  string `json:"provider_id"`
+	RequestedAt int64  `json:"requested_at"`
+	CompletesAt int64  `json:"completes_at"`
+}
+
+// RequestAccountRecovery starts the waiting period after which the enrollment
+// of the user is reset. Requesting a pending recovery again does not restart it.
+func (s *service) RequestAccountRecovery(ctx context.Context, req *proto.MfaRequestAccountRecoveryDataRequest, res *proto.MfaRequestAccountRecoveryDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate request account recovery request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	enrolled, err := s.hasEnrollment(req.UserID, req.ProviderID)
+	if err != nil {
+		s.logger.Error("Getting enrollment from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+	if !enrolled {
+		res.Error = &proto.Error{
+			Message: ErrorEnrollmentNotExists,
+		}
+		return nil
+	}
+
+	now := time.Now()
+	ar := &accountRecovery{
+		UserID:      req.UserID,
+		ProviderID:  req.ProviderID,
+		RequestedAt: now.Unix(),
+		CompletesAt: now.Add(s.opts().AccountRecoveryDelay).Unix(),
+	}
+	data, err := json.Marshal(ar)
+	if err != nil {
+		s.logger.Error("Marshal account recovery failed with error", zap.Error(err))
+
+		return err
+	}
+
+	key := s.GetAccountRecoveryStorageKey(req.UserID, req.ProviderID)
+	created, err := s.redis.SetNX(key, data, 0).Result()
+	if err != nil {
+		s.logger.Error("Add account recovery to Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if !created {
+		if data, err = s.redis.Get(key).Bytes(); err == nil {
+			err = json.Unmarshal(data, ar)
+		}
+		if err != nil {
+			s.logger.Error("Getting account recovery from Redis failed with error", zap.Error(err))
+
+			return err
+		}
+
+		// Schedules the recovery again if the request creating it failed before scheduling it.
+		err = s.redis.ZAddNX(mfaAccountRecoverySchedule, redis.Z{Score: float64(s.nextAccountRecoveryRun(ar, now)), Member: key}).Err()
+		if err != nil {
+			s.logger.Error("Schedule account recovery in Redis failed with error", zap.Error(err))
+
+			return err
+		}
+
+		res.CompletesAt = ar.CompletesAt
+		return nil
+	}
+
+	err = s.redis.ZAdd(mfaAccountRecoverySchedule, redis.Z{Score: float64(s.nextAccountRecoveryRun(ar, now)), Member: key}).Err()
+	if err != nil {
+		s.logger.Error("Schedule account recovery in Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditAccountRecoveryRequested,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		ExpiresAt:  ar.CompletesAt,
+	})
+	s.notify(RecoveryNotificationRequested, ar)
+
+	res.CompletesAt = ar.CompletesAt
+
+	return nil
+}
+
+// RunAccountRecovery sends reminders and completes due account recoveries until the context is done.
+func (s *service) RunAccountRecovery(ctx context.Context) {
+	ticker := time.NewTicker(accountRecoveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.processAccountRecoveries(time.Now()); err != nil {
+			s.logger.Error("Processing account recoveries failed with error", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processAccountRecoveries handles recoveries scheduled up to now. A recovery is
+// claimed by moving it in the schedule to the end of a lease, so instances do
+// not handle it twice and it is handled again when its instance stops before
+// rescheduling or removing it.
+func (s *service) processAccountRecoveries(now time.Time) error {
+	keys, err := s.redis.ZRangeByScore(mfaAccountRecoverySchedule, redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: accountRecoveryBatchSize,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		claimed, err := s.claimAccountRecovery(key, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if ar, err := s.processAccountRecovery(key, now); err != nil {
+			s.logger.Error(
+				"Processing account recovery failed with error",
+				zap.Error(err),
+				zap.String("userId", ar.UserID),
+				zap.String("providerId", ar.ProviderID),
+			)
+
+			retry := now.Add(accountRecoveryPollInterval).Unix()
+			if err = s.redis.ZAdd(mfaAccountRecoverySchedule, redis.Z{Score: float64(retry), Member: key}).Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// claimAccountRecovery leases the recovery if it is still due, a recovery
+// changed by another instance in the meantime is left to the next run.
+func (s *service) claimAccountRecovery(key string, now time.Time) (bool, error) {
+	claimed := false
+	err := s.redis.Watch(func(tx *redis.Tx) error {
+		score, err := tx.ZScore(mfaAccountRecoverySchedule, key).Result()
+		if err == redis.Nil || (err == nil && score > float64(now.Unix())) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.ZAdd(mfaAccountRecoverySchedule, redis.Z{Score: float64(now.Add(accountRecoveryLease).Unix()), Member: key})
+			return nil
+		})
+		claimed = err == nil
+		return err
+	}, mfaAccountRecoverySchedule)
+	if err == redis.TxFailedErr {
+		return false, nil
+	}
+
+	return claimed, err
+}
+
+// processAccountRecovery reminds the user of the recovery or completes it when
+// it is due. The recovery is returned as far as it was read, so a failure is
+// logged with its user and provider.
+func (s *service) processAccountRecovery(key string, now time.Time) (*accountRecovery, error) {
+	ar := &accountRecovery{}
+	data, err := s.redis.Get(key).Bytes()
+	if err == redis.Nil {
+		// Cancelled or reset in the meantime.
+		return ar, s.redis.ZRem(mfaAccountRecoverySchedule, key).Err()
+	}
+	if err != nil {
+		return ar, err
+	}
+
+	if err = json.Unmarshal(data, ar); err != nil {
+		return ar, err
+	}
+
+	if now.Unix() < ar.CompletesAt {
+		s.notify(RecoveryNotificationReminder, ar)
+
+		return ar, s.redis.ZAdd(mfaAccountRecoverySchedule, redis.Z{Score: float64(s.nextAccountRecoveryRun(ar, now)), Member: key}).Err()
+	}
+
+	// The recovery is removed with the enrollment, so a check cancelling it in
+	// the meantime keeps the enrollment.
+	err = s.redis.Watch(func(tx *redis.Tx) error {
+		if err := tx.Get(key).Err(); err != nil {
+			return err
+		}
+
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			s.queueResetEnrollment(pipe, ar.UserID, ar.ProviderID)
+			pipe.ZRem(mfaAccountRecoverySchedule, key)
+			return nil
+		})
+		return err
+	}, key)
+	if err == redis.Nil {
+		// Cancelled in the meantime.
+		return ar, s.redis.ZRem(mfaAccountRecoverySchedule, key).Err()
+	}
+	if err == redis.TxFailedErr {
+		// Changed in the meantime, the run after the lease sees the outcome.
+		return ar, nil
+	}
+	if err != nil {
+		return ar, err
+	}
+
+	s.logger.Info("Enrollment reset by account recovery", zap.String("userId", ar.UserID), zap.String("providerId", ar.ProviderID))
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditAccountRecoveryCompleted,
+		UserID:     ar.UserID,
+		ProviderID: ar.ProviderID,
+	})
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentRemoved,
+		UserID:     ar.UserID,
+		ProviderID: ar.ProviderID,
+		Reason:     "account recovery",
+	})
+	s.notify(RecoveryNotificationCompleted, ar)
+
+	return ar, nil
+}
+
+// cancelAccountRecovery cancels the pending recovery of a user who passed a check.
+func (s *service) cancelAccountRecovery(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse) error {
+	if !res.Result {
+		return nil
+	}
+
+	key := s.GetAccountRecoveryStorageKey(req.UserID, req.ProviderID)
+	data, err := s.redis.Get(key).Bytes()
+	if err == redis.Nil {
+		return nil
+	}
+
+	ar := &accountRecovery{}
+	if err == nil {
+		err = json.Unmarshal(data, ar)
+	}
+	if err == nil {
+		_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.Del(key)
+			pipe.ZRem(mfaAccountRecoverySchedule, key)
+			return nil
+		})
+	}
+	if err != nil {
+		s.logger.Error("Cancel account recovery in Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	s.logger.Info("Account recovery cancelled by check", zap.String("userId", req.UserID), zap.String("providerId", req.ProviderID))
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditAccountRecoveryCancelled,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     res.Method,
+	})
+	s.notify(RecoveryNotificationCancelled, ar)
+
+	return nil
+}
+
+// nextAccountRecoveryRun returns when the next reminder is due, or the completion if it is earlier.
+func (s *service) nextAccountRecoveryRun(ar *accountRecovery, now time.Time) int64 {
+	reminder := s.opts().AccountRecoveryReminder
+	next := now.Add(reminder).Unix()
+	if reminder <= 0 || next > ar.CompletesAt {
+		return ar.CompletesAt
+	}
+	return next
+}
+
+// notify sends the notification, failures are logged and do not fail the request.
+func (s *service) notify(notificationType string, ar *accountRecovery) {
+	span := s.startSpan("notifier.notify", attribute.String("mfa.notification_type", notificationType))
+	err := s.opts().Notifier.Notify(&proto.RecoveryNotification{
+		Type:        notificationType,
+		UserID:      ar.UserID,
+		ProviderID:  ar.ProviderID,
+		CompletesAt: ar.CompletesAt,
+		CreatedAt:   time.Now().Unix(),
+	})
+	endSpan(span, err)
+	if err != nil {
+		s.logger.Error(
+			"Sending account recovery notification failed with error",
+			zap.Error(err),
+			zap.String("type", notificationType),
+			zap.String("userId", ar.UserID),
+			zap.String("providerId", ar.ProviderID),
+		)
+	}
+}
+
+func (s *service) hasEnrollment(userId string, providerId string) (bool, error) {
+	var exists *redis.IntCmd
+	var legacy *redis.BoolCmd
+	_, err := s.redis.Pipelined(func(pipe redis.Pipeliner) error {
+		exists = pipe.Exists(
+			s.GetDeviceStorageKey(userId, providerId),
+			s.GetYubiKeyStorageKey(userId, providerId),
+			s.GetRecoveryStorageKey(userId, providerId),
+		)
+		legacy = pipe.HExists(s.GetSecretStorageKey(userId), providerId)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists.Val() > 0 || legacy.Val(), nil
+}
+
+func (s *service) GetAccountRecoveryStorageKey(userId string, providerId string) string {
+	return fmt.Sprintf(mfaAccountRecoveryStoragePattern, userId, providerId)
+}
@@ -0,0 +1,152 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"time"
+)
+
+type testNotifier struct {
+	notifications []*proto.RecoveryNotification
+}
+
+func (n *testNotifier) Notify(notification *proto.RecoveryNotification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func (n *testNotifier) types() []string {
+	var types []string
+	for _, notification := range n.notifications {
+		types = append(types, notification.Type)
+	}
+	return types
+}
+
+func (suite *ServiceTestSuite) requestAccountRecovery() *proto.MfaRequestAccountRecoveryDataResponse {
+	res := &proto.MfaRequestAccountRecoveryDataResponse{}
+	err := suite.service.RequestAccountRecovery(context.TODO(), &proto.MfaRequestAccountRecoveryDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+func (suite *ServiceTestSuite) TestRequestAccountRecoveryToReturnErrorWithoutEnrollment() {
+	res := suite.requestAccountRecovery()
+	assert.Equal(suite.T(), ErrorEnrollmentNotExists, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestRequestAccountRecoveryToStartWaitingPeriod() {
+	notifier := &testNotifier{}
+	suite.service = NewService(suite.redis, zap.L(), RecoveryNotifier(notifier))
+	suite.createDevice("")
+
+	res := suite.requestAccountRecovery()
+	assert.Nil(suite.T(), res.Error)
+	assert.InDelta(suite.T(), time.Now().Add(defaultAccountRecoveryDelay).Unix(), res.CompletesAt, 2)
+	assert.Equal(suite.T(), []string{RecoveryNotificationRequested}, notifier.types())
+
+	again := suite.requestAccountRecovery()
+	assert.Equal(suite.T(), res.CompletesAt, again.CompletesAt)
+	assert.Equal(suite.T(), 1, len(notifier.notifications))
+
+	status := &proto.MfaGetUserStatusDataResponse{}
+	_ = suite.service.GetUserStatus(context.TODO(), &proto.MfaGetUserStatusDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, status)
+	assert.Equal(suite.T(), res.CompletesAt, status.AccountRecoveryCompletesAt)
+}
+
+func (suite *ServiceTestSuite) TestCheckToCancelAccountRecovery() {
+	notifier := &testNotifier{}
+	suite.service = NewService(suite.redis, zap.L(), RecoveryNotifier(notifier))
+	device := suite.createDevice("")
+	suite.requestAccountRecovery()
+
+	code, _ := totp.GenerateCode(device.SecretKey, time.Now())
+	res := &proto.MfaCheckDataResponse{}
+	err := suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, res)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+
+	assert.Equal(suite.T(), []string{RecoveryNotificationRequested, RecoveryNotificationCancelled}, notifier.types())
+	assert.Equal(suite.T(), int64(0), suite.redis.Exists(suite.service.GetAccountRecoveryStorageKey(suite.userID, suite.ProviderID)).Val())
+	assert.Equal(suite.T(), int64(0), suite.redis.ZCard(mfaAccountRecoverySchedule).Val())
+
+	events := &proto.MfaQueryAuditEventsDataResponse{}
+	_ = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{UserID: suite.userID, Types: []string{AuditAccountRecoveryCancelled}}, events)
+	assert.Equal(suite.T(), 1, len(events.Events))
+}
+
+func (suite *ServiceTestSuite) TestProcessAccountRecoveriesToRemindAndResetEnrollment() {
+	notifier := &testNotifier{}
+	suite.service = NewService(
+		suite.redis,
+		zap.L(),
+		RecoveryNotifier(notifier),
+		AccountRecoveryDelay(time.Hour),
+		AccountRecoveryReminder(20*time.Minute),
+	)
+	suite.createDevice("")
+	suite.requestAccountRecovery()
+	now := time.Now()
+
+	assert.NoError(suite.T(), suite.service.processAccountRecoveries(now))
+	assert.Equal(suite.T(), 1, len(notifier.notifications))
+
+	assert.NoError(suite.T(), suite.service.processAccountRecoveries(now.Add(21*time.Minute)))
+	assert.Equal(suite.T(), []string{RecoveryNotificationRequested, RecoveryNotificationReminder}, notifier.types())
+	assert.Equal(suite.T(), int64(1), suite.redis.ZCard(mfaAccountRecoverySchedule).Val())
+
+	assert.NoError(suite.T(), suite.service.processAccountRecoveries(now.Add(time.Hour+time.Second)))
+	assert.Equal(suite.T(), RecoveryNotificationCompleted, notifier.notifications[len(notifier.notifications)-1].Type)
+	assert.Equal(suite.T(), int64(0), suite.redis.ZCard(mfaAccountRecoverySchedule).Val())
+
+	enrolled, _ := suite.service.hasEnrollment(suite.userID, suite.ProviderID)
+	assert.False(suite.T(), enrolled)
+
+	events := &proto.MfaQueryAuditEventsDataResponse{}
+	_ = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{UserID: suite.userID, Types: []string{AuditAccountRecoveryCompleted}}, events)
+	assert.Equal(suite.T(), 1, len(events.Events))
+}
+
+func (suite *ServiceTestSuite) TestRequestAccountRecoveryToScheduleUnscheduledRecovery() {
+	suite.service = NewService(suite.redis, zap.L(), AccountRecoveryReminder(0))
+	suite.createDevice("")
+	res := suite.requestAccountRecovery()
+	key := suite.service.GetAccountRecoveryStorageKey(suite.userID, suite.ProviderID)
+
+	// The instance stopped after storing the recovery and before scheduling it.
+	suite.redis.ZRem(mfaAccountRecoverySchedule, key)
+
+	again := suite.requestAccountRecovery()
+	assert.Equal(suite.T(), res.CompletesAt, again.CompletesAt)
+	assert.Equal(suite.T(), float64(res.CompletesAt), suite.redis.ZScore(mfaAccountRecoverySchedule, key).Val())
+}
+
+func (suite *ServiceTestSuite) TestProcessAccountRecoveriesToReclaimAfterLease() {
+	suite.service = NewService(suite.redis, zap.L(), AccountRecoveryDelay(time.Hour), AccountRecoveryReminder(0))
+	suite.createDevice("")
+	res := suite.requestAccountRecovery()
+	key := suite.service.GetAccountRecoveryStorageKey(suite.userID, suite.ProviderID)
+	due := time.Unix(res.CompletesAt, 0)
+
+	// The instance claiming the recovery stops before completing it.
+	claimed, err := suite.service.claimAccountRecovery(key, due)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), claimed)
+
+	claimed, err = suite.service.claimAccountRecovery(key, due)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), claimed)
+
+	assert.NoError(suite.T(), suite.service.processAccountRecoveries(due.Add(accountRecoveryLease-time.Second)))
+	enrolled, _ := suite.service.hasEnrollment(suite.userID, suite.ProviderID)
+	assert.True(suite.T(), enrolled)
+
+	assert.NoError(suite.T(), suite.service.processAccountRecoveries(due.Add(accountRecoveryLease)))
+	enrolled, _ = suite.service.hasEnrollment(suite.userID, suite.ProviderID)
+	assert.False(suite.T(), enrolled)
+	assert.Equal(suite.T(), int64(0), suite.redis.ZCard(mfaAccountRecoverySchedule).Val())
+}
@@ -0,0 +1,209 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"sort"
+)
+
+const (
+	ErrorEnrollmentNotExists = "Enrollment not exists"
+	ErrorNotLockedOut        = "User is not locked out"
+)
+
+func (s *service) GetUserStatus(ctx context.Context, req *proto.MfaGetUserStatusDataRequest, res *proto.MfaGetUserStatusDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate get user status request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	devices, err := s.loadDevices(req.UserID, req.ProviderID)
+	if err != nil {
+		s.logger.Error("Getting devices from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	for _, d := range devices {
+		res.Devices = append(res.Devices, &proto.Device{
+			ID:        d.ID,
+			Name:      d.Name,
+			CreatedAt: d.CreatedAt,
+			Drift:     int32(d.Drift),
+		})
+	}
+
+	var yubiKeys *redis.StringSliceCmd
+	var recoveryCodes, trustedDevices *redis.IntCmd
+	var failures, lockout, bypass, recovery *redis.StringCmd
+	_, err = s.redis.Pipelined(func(pipe redis.Pipeliner) error {
+		yubiKeys = pipe.HKeys(s.GetYubiKeyStorageKey(req.UserID, req.ProviderID))
+		recoveryCodes = pipe.SCard(s.GetRecoveryStorageKey(req.UserID, req.ProviderID))
+		trustedDevices = pipe.HLen(s.GetTrustedDeviceStorageKey(req.UserID, req.ProviderID))
+		failures = pipe.Get(s.GetFailureStorageKey(req.UserID, req.ProviderID))
+		lockout = pipe.Get(s.GetLockoutStorageKey(req.UserID, req.ProviderID))
+		bypass = pipe.Get(s.GetBypassStorageKey(req.UserID, req.ProviderID))
+		recovery = pipe.Get(s.GetAccountRecoveryStorageKey(req.UserID, req.ProviderID))
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		s.logger.Error("Getting user status from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	res.YubiKeys = yubiKeys.Val()
+	sort.Strings(res.YubiKeys)
+	res.RecoveryCodes = int32(recoveryCodes.Val())
+	res.TrustedDevices = int32(trustedDevices.Val())
+	if n, err := failures.Int64(); err == nil {
+		res.FailedAttempts = int32(n)
+	}
+	if until, err := lockout.Int64(); err == nil {
+		res.LockedOutUntil = until
+	}
+	if data, err := bypass.Bytes(); err == nil {
+		bc := &bypassCode{}
+		if err = json.Unmarshal(data, bc); err == nil {
+			res.BypassCodeExpiresAt = bc.ExpiresAt
+		}
+	}
+	if data, err := recovery.Bytes(); err == nil {
+		ar := &accountRecovery{}
+		if err = json.Unmarshal(data, ar); err == nil {
+			res.AccountRecoveryCompletesAt = ar.CompletesAt
+		}
+	}
+
+	return nil
+}
+
+// ResetEnrollment removes every factor, trusted device and lockout of the user
+// for the provider so the user can enroll again.
+func (s *service) ResetEnrollment(ctx context.Context, req *proto.MfaResetEnrollmentDataRequest, res *proto.MfaResetEnrollmentDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate reset enrollment request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	removed, err := s.resetEnrollment(req.UserID, req.ProviderID)
+	if err != nil {
+		s.logger.Error("Reset enrollment in Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if !removed {
+		res.Error = &proto.Error{
+			Message: ErrorEnrollmentNotExists,
+		}
+		return nil
+	}
+
+	s.logger.Info(
+		"Enrollment reset by administrator",
+		zap.String("userId", req.UserID),
+		zap.String("providerId", req.ProviderID),
+		zap.String("actor", AuthClientID(ctx)),
+		zap.String("note", req.Actor),
+	)
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentReset,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Actor:      AuthClientID(ctx),
+		Reason:     req.Reason,
+		Note:       req.Actor,
+	})
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentRemoved,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Actor:      AuthClientID(ctx),
+		Reason:     "enrollment reset",
+		Note:       req.Actor,
+	})
+
+	res.Result = true
+
+	return nil
+}
+
+// resetEnrollment removes all storage of the user for the provider and reports whether there was any.
+func (s *service) resetEnrollment(userId string, providerId string) (bool, error) {
+	var removed, legacy *redis.IntCmd
+	_, err := s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		removed, legacy = s.queueResetEnrollment(pipe, userId, providerId)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return removed.Val()+legacy.Val() > 0, nil
+}
+
+// queueResetEnrollment queues the removal of all storage of the user for the provider.
+func (s *service) queueResetEnrollment(pipe redis.Pipeliner, userId string, providerId string) (*redis.IntCmd, *redis.IntCmd) {
+	return pipe.Del(s.userStorageKeys(userId, providerId)...), pipe.HDel(s.GetSecretStorageKey(userId), providerId)
+}
+
+func (s *service) ClearLockout(ctx context.Context, req *proto.MfaClearLockoutDataRequest, res *proto.MfaClearLockoutDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate clear lockout request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	var removed *redis.IntCmd
+	_, err := s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		removed = pipe.Del(s.GetLockoutStorageKey(req.UserID, req.ProviderID))
+		pipe.Del(s.GetFailureStorageKey(req.UserID, req.ProviderID))
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Clear lockout in Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if removed.Val() == 0 {
+		res.Error = &proto.Error{
+			Message: ErrorNotLockedOut,
+		}
+		return nil
+	}
+
+	s.logger.Info(
+		"Lockout cleared by administrator",
+		zap.String("userId", req.UserID),
+		zap.String("providerId", req.ProviderID),
+		zap.String("actor", AuthClientID(ctx)),
+		zap.String("note", req.Actor),
+	)
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditLockoutCleared,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Actor:      AuthClientID(ctx),
+		Reason:     req.Reason,
+		Note:       req.Actor,
+	})
+
+	res.Result = true
+
+	return nil
+}
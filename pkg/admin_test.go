@@ -0,0 +1,90 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestGetUserStatusToReturnFactorsAndLockout() {
+	suite.createDevice("phone")
+	until := time.Now().Add(time.Minute).Unix()
+	suite.redis.Set(suite.service.GetLockoutStorageKey(suite.userID, suite.ProviderID), until, time.Minute)
+	suite.redis.Set(suite.service.GetFailureStorageKey(suite.userID, suite.ProviderID), 2, time.Minute)
+
+	res := &proto.MfaGetUserStatusDataResponse{}
+	err := suite.service.GetUserStatus(context.TODO(), &proto.MfaGetUserStatusDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(res.Devices))
+	assert.Equal(suite.T(), "phone", res.Devices[0].Name)
+	assert.Empty(suite.T(), res.YubiKeys)
+	assert.Equal(suite.T(), int32(defaultRecoveryCodeCount), res.RecoveryCodes)
+	assert.Equal(suite.T(), int32(2), res.FailedAttempts)
+	assert.Equal(suite.T(), until, res.LockedOutUntil)
+}
+
+func (suite *ServiceTestSuite) TestGetUserStatusToReturnEmptyStatus() {
+	res := &proto.MfaGetUserStatusDataResponse{}
+	err := suite.service.GetUserStatus(context.TODO(), &proto.MfaGetUserStatusDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), res.Devices)
+	assert.Equal(suite.T(), int64(0), res.LockedOutUntil)
+}
+
+func (suite *ServiceTestSuite) TestResetEnrollmentToRemoveFactors() {
+	suite.createDevice("")
+	req := &proto.MfaResetEnrollmentDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Actor: "support", Reason: "lost phone"}
+
+	res := &proto.MfaResetEnrollmentDataResponse{}
+	err := suite.service.ResetEnrollment(ContextWithAuthClientID(context.TODO(), "admin"), req, res)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+
+	for _, key := range suite.service.userStorageKeys(suite.userID, suite.ProviderID) {
+		assert.Equal(suite.T(), int64(0), suite.redis.Exists(key).Val())
+	}
+
+	events := &proto.MfaQueryAuditEventsDataResponse{}
+	_ = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{UserID: suite.userID, Types: []string{AuditEnrollmentReset}}, events)
+	assert.Equal(suite.T(), 1, len(events.Events))
+	assert.Equal(suite.T(), "admin", events.Events[0].Actor)
+	assert.Equal(suite.T(), "support", events.Events[0].Note)
+	assert.Equal(suite.T(), "lost phone", events.Events[0].Reason)
+
+	res = &proto.MfaResetEnrollmentDataResponse{}
+	err = suite.service.ResetEnrollment(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorEnrollmentNotExists, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestClearLockoutToAllowChecks() {
+	suite.redis.Set(suite.service.GetLockoutStorageKey(suite.userID, suite.ProviderID), time.Now().Unix(), time.Minute)
+	req := &proto.MfaClearLockoutDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Actor: "support"}
+
+	res := &proto.MfaClearLockoutDataResponse{}
+	err := suite.service.ClearLockout(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+
+	locked, _ := suite.service.lockedOut(suite.userID, suite.ProviderID)
+	assert.False(suite.T(), locked)
+
+	res = &proto.MfaClearLockoutDataResponse{}
+	_ = suite.service.ClearLockout(context.TODO(), req, res)
+	assert.Equal(suite.T(), ErrorNotLockedOut, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestAdminRequestsToReturnErrorRequestData() {
+	err := suite.service.GetUserStatus(context.TODO(), &proto.MfaGetUserStatusDataRequest{ProviderID: suite.ProviderID}, &proto.MfaGetUserStatusDataResponse{})
+	assert.EqualError(suite.T(), err, "UserID is required field")
+
+	err = suite.service.ResetEnrollment(context.TODO(), &proto.MfaResetEnrollmentDataRequest{UserID: suite.userID}, &proto.MfaResetEnrollmentDataResponse{})
+	assert.EqualError(suite.T(), err, "ProviderID is required field")
+
+	err = suite.service.ClearLockout(context.TODO(), &proto.MfaClearLockoutDataRequest{UserID: suite.userID}, &proto.MfaClearLockoutDataResponse{})
+	assert.EqualError(suite.T(), err, "ProviderID is required field")
+}
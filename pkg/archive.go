@@ -0,0 +1,374 @@
+package mfa
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"time"
+)
+
+const (
+	archiveFormat    = "mfa-enrollments"
+	archiveVersion   = 1
+	archiveCipher    = "AES-256-GCM"
+	archiveKeySize   = 32
+	archiveChunkSize = 64 * 1024
+	archiveNonceSize = 12
+	archiveMaxRecord = 1 << 20
+
+	ArchiveKeyPassphrase = "scrypt"
+	ArchiveKeyRSA        = "rsa-oaep-sha256"
+
+	archiveScryptN = 1 << 15
+	archiveScryptR = 8
+	archiveScryptP = 1
+)
+
+var archiveRSALabel = []byte(archiveFormat)
+
+// archiveHeader is the plain text first line of an archive, it is authenticated
+// as the additional data of every chunk.
+type archiveHeader struct {
+	Format      string `json:"format"`
+	Version     int    `json:"version"`
+	Cipher      string `json:"cipher"`
+	Key         string `json:"key"`
+	Salt        []byte `json:"salt,omitempty"`
+	ScryptN     int    `json:"scrypt_n,omitempty"`
+	ScryptR     int    `json:"scrypt_r,omitempty"`
+	ScryptP     int    `json:"scrypt_p,omitempty"`
+	WrappedKey  []byte `json:"wrapped_key,omitempty"`
+	NoncePrefix []byte `json:"nonce_prefix"`
+	ProviderID  string `json:"provider_id,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// ArchiveKey encrypts or decrypts an archive with a passphrase or an RSA key
+// pair, archives encrypted for a public key are decrypted with its private key.
+type ArchiveKey struct {
+	Passphrase string
+	PublicKey  *rsa.PublicKey
+	PrivateKey *rsa.PrivateKey
+}
+
+// ArchiveWriter writes enrollments to an encrypted archive. The archive is
+// split into chunks sealed with AES-GCM, the nonce of a chunk holds its number
+// and whether it is the last one, so reordered or truncated archives are rejected.
+type ArchiveWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	buf    bytes.Buffer
+	chunk  uint32
+}
+
+// NewArchiveWriter writes the archive header and returns the writer for the enrollments.
+func NewArchiveWriter(w io.Writer, key ArchiveKey, providerId string) (*ArchiveWriter, error) {
+	h := &archiveHeader{
+		Format:     archiveFormat,
+		Version:    archiveVersion,
+		Cipher:     archiveCipher,
+		ProviderID: providerId,
+		CreatedAt:  time.Now().Unix(),
+	}
+
+	dataKey := make([]byte, archiveKeySize)
+	h.NoncePrefix = make([]byte, archiveNonceSize-5)
+	if _, err := rand.Read(h.NoncePrefix); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case key.PublicKey != nil:
+		if _, err := rand.Read(dataKey); err != nil {
+			return nil, err
+		}
+
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key.PublicKey, dataKey, archiveRSALabel)
+		if err != nil {
+			return nil, err
+		}
+		h.Key, h.WrappedKey = ArchiveKeyRSA, wrapped
+	case key.Passphrase != "":
+		h.Key, h.ScryptN, h.ScryptR, h.ScryptP = ArchiveKeyPassphrase, archiveScryptN, archiveScryptR, archiveScryptP
+		h.Salt = make([]byte, 16)
+		if _, err := rand.Read(h.Salt); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if dataKey, err = scrypt.Key([]byte(key.Passphrase), h.Salt, h.ScryptN, h.ScryptR, h.ScryptP, archiveKeySize); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("archive needs a passphrase or a public key")
+	}
+
+	aead, err := newArchiveAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(append(header, '\n')); err != nil {
+		return nil, err
+	}
+
+	return &ArchiveWriter{w: w, aead: aead, header: header, prefix: h.NoncePrefix}, nil
+}
+
+// Write adds the enrollment to the archive.
+func (a *ArchiveWriter) Write(e *proto.Enrollment) error {
+	data, err := protobuf.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var size [binary.MaxVarintLen64]byte
+	a.buf.Write(size[:binary.PutUvarint(size[:], uint64(len(data)))])
+	a.buf.Write(data)
+
+	if a.buf.Len() >= archiveChunkSize {
+		return a.flush(false)
+	}
+	return nil
+}
+
+// Close writes the last chunk, an archive without it is considered truncated.
+func (a *ArchiveWriter) Close() error {
+	return a.flush(true)
+}
+
+func (a *ArchiveWriter) flush(last bool) error {
+	sealed := a.aead.Seal(nil, archiveNonce(a.prefix, a.chunk, last), a.buf.Bytes(), a.header)
+	a.buf.Reset()
+	a.chunk++
+
+	// The size and the chunk are written at once, so a streamed archive sends a message per chunk.
+	data := make([]byte, 4, 4+len(sealed))
+	binary.BigEndian.PutUint32(data, uint32(len(sealed)))
+	_, err := a.w.Write(append(data, sealed...))
+	return err
+}
+
+// ArchiveReader reads enrollments from an archive written by ArchiveWriter.
+type ArchiveReader struct {
+	// ProviderID is the provider the archive was exported for, empty for all providers.
+	ProviderID string
+	CreatedAt  time.Time
+
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	buf    *bytes.Reader
+	chunk  uint32
+	last   bool
+}
+
+// NewArchiveReader reads the archive header and derives or unwraps its key.
+func NewArchiveReader(r io.Reader, key ArchiveKey) (*ArchiveReader, error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("reading archive header failed: %v", err)
+	}
+	header := bytes.TrimSuffix(line, []byte("\n"))
+
+	h := &archiveHeader{}
+	if err = json.Unmarshal(header, h); err != nil || h.Format != archiveFormat {
+		return nil, errors.New("not an enrollment archive")
+	}
+	if h.Version != archiveVersion || h.Cipher != archiveCipher || len(h.NoncePrefix) != archiveNonceSize-5 {
+		return nil, fmt.Errorf("unsupported archive version %d", h.Version)
+	}
+
+	var dataKey []byte
+	switch h.Key {
+	case ArchiveKeyRSA:
+		if key.PrivateKey == nil {
+			return nil, errors.New("archive is encrypted for a public key, the private key is required")
+		}
+		if dataKey, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, key.PrivateKey, h.WrappedKey, archiveRSALabel); err != nil {
+			return nil, errors.New("archive is encrypted for another key")
+		}
+	case ArchiveKeyPassphrase:
+		if key.Passphrase == "" {
+			return nil, errors.New("archive is encrypted with a passphrase, the passphrase is required")
+		}
+		// Bound the parameters, they come from the archive.
+		if h.ScryptN > 1<<20 || h.ScryptR > 32 || h.ScryptP > 16 {
+			return nil, errors.New("archive key parameters are out of range")
+		}
+		if dataKey, err = scrypt.Key([]byte(key.Passphrase), h.Salt, h.ScryptN, h.ScryptR, h.ScryptP, archiveKeySize); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported archive key %q", h.Key)
+	}
+
+	aead, err := newArchiveAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArchiveReader{
+		r:          br,
+		aead:       aead,
+		header:     header,
+		prefix:     h.NoncePrefix,
+		buf:        bytes.NewReader(nil),
+		CreatedAt:  time.Unix(h.CreatedAt, 0),
+		ProviderID: h.ProviderID,
+	}, nil
+}
+
+// Read returns the next enrollment, io.EOF after the last one.
+func (a *ArchiveReader) Read() (*proto.Enrollment, error) {
+	for a.buf.Len() == 0 {
+		if a.last {
+			return nil, io.EOF
+		}
+		if err := a.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	size, err := binary.ReadUvarint(a.buf)
+	if err != nil || size > archiveMaxRecord || size > uint64(a.buf.Len()) {
+		return nil, errors.New("archive record is corrupted")
+	}
+
+	data := make([]byte, size)
+	if _, err = io.ReadFull(a.buf, data); err != nil {
+		return nil, err
+	}
+
+	e := &proto.Enrollment{}
+	if err = protobuf.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (a *ArchiveReader) next() error {
+	var size [4]byte
+	if _, err := io.ReadFull(a.r, size[:]); err != nil {
+		if err == io.EOF {
+			return errors.New("archive is truncated")
+		}
+		return err
+	}
+
+	n := binary.BigEndian.Uint32(size[:])
+	if n > archiveChunkSize+archiveMaxRecord+uint32(a.aead.Overhead())+binary.MaxVarintLen64 {
+		return errors.New("archive chunk is corrupted")
+	}
+
+	sealed := make([]byte, n)
+	if _, err := io.ReadFull(a.r, sealed); err != nil {
+		return err
+	}
+
+	data, err := a.aead.Open(nil, archiveNonce(a.prefix, a.chunk, false), sealed, a.header)
+	if err != nil {
+		if data, err = a.aead.Open(nil, archiveNonce(a.prefix, a.chunk, true), sealed, a.header); err != nil {
+			return errors.New("archive can not be decrypted, the key is wrong or the archive is corrupted")
+		}
+		a.last = true
+	}
+	a.chunk++
+	a.buf = bytes.NewReader(data)
+
+	return nil
+}
+
+// archiveChunkWriter sends every write of an ArchiveWriter as a chunk of the export stream.
+type archiveChunkWriter struct {
+	stream proto.MfaService_ExportEnrollmentsStream
+}
+
+func (w *archiveChunkWriter) Write(p []byte) (int, error) {
+	// The stream may keep the chunk after Send returns and the writer reuses p.
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := w.stream.Send(&proto.ArchiveChunk{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ParseArchivePublicKey parses a PEM encoded RSA public key.
+func ParseArchivePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if rsaKey, ok := key.(*rsa.PublicKey); ok {
+		return rsaKey, nil
+	}
+	return nil, errors.New("public key is not an RSA key")
+}
+
+// ParseArchivePrivateKey parses a PEM encoded RSA private key in PKCS #1 or PKCS #8 form.
+func ParseArchivePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+		return rsaKey, nil
+	}
+	return nil, errors.New("private key is not an RSA key")
+}
+
+func newArchiveAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// archiveNonce joins the random prefix of the archive, the chunk number and the last chunk flag.
+func archiveNonce(prefix []byte, chunk uint32, last bool) []byte {
+	nonce := make([]byte, archiveNonceSize)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[len(prefix):], chunk)
+	if last {
+		nonce[archiveNonceSize-1] = 1
+	}
+	return nonce
+}
@@ -0,0 +1,102 @@
+package mfa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	assertionAcr = "mfa"
+)
+
+// assertionAmr maps verification methods to RFC 8176 authentication method references.
+var assertionAmr = map[string][]string{
+	MethodTotp:         {"otp"},
+	MethodYubiKey:      {"hwk", "otp"},
+	MethodRecoveryCode: {"otp"},
+	MethodBypassCode:   {"otp"},
+}
+
+type assertionHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// assertionClaims are the claims of the step-up assertion returned by a successful check.
+type assertionClaims struct {
+	Issuer     string   `json:"iss"`
+	Subject    string   `json:"sub"`
+	Audience   string   `json:"aud"`
+	IssuedAt   int64    `json:"iat"`
+	Expiration int64    `json:"exp"`
+	AuthTime   int64    `json:"auth_time"`
+	Acr        string   `json:"acr"`
+	Amr        []string `json:"amr"`
+	Method     string   `json:"mfa_method"`
+	DeviceID   string   `json:"mfa_device,omitempty"`
+}
+
+// issueAssertion signs a JWT proving the user passed MFA if the caller asked for it.
+func (s *service) issueAssertion(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse) error {
+	if !res.Result || !req.Assertion {
+		return nil
+	}
+
+	now := time.Now()
+	token, err := s.signAssertion(&assertionClaims{
+		Issuer:     s.opts().AssertionIssuer,
+		Subject:    req.UserID,
+		Audience:   req.ProviderID,
+		IssuedAt:   now.Unix(),
+		Expiration: now.Add(s.opts().AssertionTTL).Unix(),
+		AuthTime:   now.Unix(),
+		Acr:        assertionAcr,
+		Amr:        assertionAmr[res.Method],
+		Method:     res.Method,
+		DeviceID:   res.DeviceID,
+	})
+	if err != nil {
+		s.logger.Error("Sign assertion failed with error", zap.Error(err))
+
+		return err
+	}
+
+	res.Assertion = token
+
+	return nil
+}
+
+func (s *service) signAssertion(claims *assertionClaims) (string, error) {
+	key, err := s.activeSigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(&assertionHeader{Alg: signingKeyAlgorithm, Typ: "JWT", Kid: key.ID})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
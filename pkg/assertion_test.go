@@ -0,0 +1,199 @@
+package mfa
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestCheckToNotReturnAssertionByDefault() {
+	res := suite.checkAssertion(false)
+
+	assert.True(suite.T(), res.Result)
+	assert.Equal(suite.T(), MethodTotp, res.Method)
+	assert.Empty(suite.T(), res.Assertion)
+}
+
+func (suite *ServiceTestSuite) TestCheckToReturnSignedAssertion() {
+	suite.service = NewService(suite.redis, zap.L(), AssertionIssuer("issuer"))
+	res := suite.checkAssertion(true)
+
+	claims := &assertionClaims{}
+	kid := suite.verifyAssertion(res.Assertion, claims)
+
+	assert.NotEmpty(suite.T(), kid)
+	assert.Equal(suite.T(), "issuer", claims.Issuer)
+	assert.Equal(suite.T(), suite.userID, claims.Subject)
+	assert.Equal(suite.T(), suite.ProviderID, claims.Audience)
+	assert.Equal(suite.T(), assertionAcr, claims.Acr)
+	assert.Equal(suite.T(), []string{"otp"}, claims.Amr)
+	assert.Equal(suite.T(), MethodTotp, claims.Method)
+	assert.Equal(suite.T(), res.DeviceID, claims.DeviceID)
+	assert.True(suite.T(), claims.Expiration > time.Now().Unix())
+}
+
+func (suite *ServiceTestSuite) TestCheckToSignAssertionWithRotatedKey() {
+	suite.service = NewService(suite.redis, zap.L(), SigningKeyRotation(0))
+
+	kid1 := suite.verifyAssertion(suite.checkAssertion(true).Assertion, &assertionClaims{})
+	kid2 := suite.verifyAssertion(suite.checkAssertion(true).Assertion, &assertionClaims{})
+
+	assert.NotEqual(suite.T(), kid1, kid2)
+}
+
+func (suite *ServiceTestSuite) checkAssertion(assertion bool) *proto.MfaCheckDataResponse {
+	res1 := suite.createDevice("")
+	code, _ := totp.GenerateCode(res1.SecretKey, time.Now())
+
+	res := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code, Assertion: assertion}
+	err := suite.service.Check(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+// verifyAssertion checks the signature of the assertion against the published key set and returns the key id.
+func (suite *ServiceTestSuite) verifyAssertion(token string, claims *assertionClaims) string {
+	rec := httptest.NewRecorder()
+	suite.service.JWKSHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	set := &jwks{}
+	assert.NoError(suite.T(), json.Unmarshal(rec.Body.Bytes(), set))
+
+	parts := strings.Split(token, ".")
+	assert.Equal(suite.T(), 3, len(parts))
+
+	header := &assertionHeader{}
+	data, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	assert.NoError(suite.T(), json.Unmarshal(data, header))
+	assert.Equal(suite.T(), signingKeyAlgorithm, header.Alg)
+
+	var key *rsa.PublicKey
+	for _, k := range set.Keys {
+		if k.Kid == header.Kid {
+			n, _ := base64.RawURLEncoding.DecodeString(k.N)
+			e, _ := base64.RawURLEncoding.DecodeString(k.E)
+			key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		}
+	}
+	assert.NotNil(suite.T(), key)
+
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(suite.T(), rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+
+	data, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(suite.T(), json.Unmarshal(data, claims))
+
+	return header.Kid
+}
+
+func (suite *ServiceTestSuite) TestActiveSigningKeyToPrePublishNextKey() {
+	suite.service = NewService(suite.redis, zap.L(), SigningKeyRotation(time.Hour))
+
+	current, err := suite.service.activeSigningKey()
+	assert.NoError(suite.T(), err)
+	suite.ageSigningKey(current.ID, time.Hour-time.Minute)
+
+	key, err := suite.service.activeSigningKey()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), current.ID, key.ID)
+
+	keys, err := suite.service.signingKeys()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 2)
+	next := keys[1]
+	assert.NotEqual(suite.T(), current.ID, next.ID)
+
+	kid := suite.verifyAssertion(suite.checkAssertion(true).Assertion, &assertionClaims{})
+	assert.Equal(suite.T(), current.ID, kid)
+
+	suite.ageSigningKey(next.ID, jwksMaxAge)
+
+	key, err = suite.service.activeSigningKey()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), next.ID, key.ID)
+}
+
+func (suite *ServiceTestSuite) TestActiveSigningKeyToRotateOnceAcrossInstances() {
+	services := []*service{suite.service, NewService(suite.redis, zap.L())}
+	ids := make(chan string, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(ids); i++ {
+		wg.Add(1)
+		go func(s *service) {
+			defer wg.Done()
+			if key, err := s.activeSigningKey(); assert.NoError(suite.T(), err) {
+				ids <- key.ID
+			}
+		}(services[i%len(services)])
+	}
+	wg.Wait()
+	close(ids)
+
+	keys, err := suite.service.reloadSigningKeys()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 1)
+	for id := range ids {
+		assert.Equal(suite.T(), keys[0].ID, id)
+	}
+}
+
+func (suite *ServiceTestSuite) TestSigningKeysToUseCachedKeys() {
+	key, err := suite.service.activeSigningKey()
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.redis.Del(mfaSigningKeyStorage).Err())
+
+	keys, err := suite.service.signingKeys()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 1)
+	assert.Equal(suite.T(), key.ID, keys[0].ID)
+}
+
+func (suite *ServiceTestSuite) TestSigningKeysToEncryptKeysAtRest() {
+	suite.service = NewService(suite.redis, zap.L(), SigningKeySecret([]byte("secret")))
+	key, err := suite.service.activeSigningKey()
+	assert.NoError(suite.T(), err)
+
+	data, err := suite.redis.HGet(mfaSigningKeyStorage, key.ID).Result()
+	assert.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), data, "PRIVATE KEY")
+
+	keys, err := NewService(suite.redis, zap.L(), SigningKeySecret([]byte("secret"))).signingKeys()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 1)
+	assert.Equal(suite.T(), key.key.D, keys[0].key.D)
+
+	_, err = NewService(suite.redis, zap.L(), SigningKeySecret([]byte("other"))).signingKeys()
+	assert.Error(suite.T(), err)
+	_, err = NewService(suite.redis, zap.L()).signingKeys()
+	assert.Error(suite.T(), err)
+}
+
+// ageSigningKey moves the creation time of the stored key into the past and
+// clears the cached keys.
+func (suite *ServiceTestSuite) ageSigningKey(id string, age time.Duration) {
+	data, err := suite.redis.HGet(mfaSigningKeyStorage, id).Result()
+	assert.NoError(suite.T(), err)
+
+	sk := &signingKey{}
+	assert.NoError(suite.T(), json.Unmarshal([]byte(data), sk))
+	sk.CreatedAt = time.Now().Add(-age).Unix()
+	b, err := json.Marshal(sk)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.redis.HSet(mfaSigningKeyStorage, id, b).Err())
+
+	suite.service.keys = &signingKeyCache{parsed: map[string]*signingKey{}}
+}
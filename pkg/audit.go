@@ -0,0 +1,214 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	AuditEnrollmentCreated       = "enrollment.created"
+	AuditEnrollmentConfirmed     = "enrollment.confirmed"
+	AuditEnrollmentRemoved       = "enrollment.removed"
+	AuditVerificationSucceeded   = "verification.succeeded"
+	AuditVerificationFailed      = "verification.failed"
+	AuditRecoveryCodeUsed        = "recovery_code.used"
+	AuditTrustedDeviceIssued     = "trusted_device.issued"
+	AuditTrustedDeviceRevoked    = "trusted_device.revoked"
+	AuditLockout                 = "lockout"
+	AuditAdminAction             = "admin.action"
+	AuditEnrollmentReset         = "enrollment.reset"
+	AuditLockoutCleared          = "lockout.cleared"
+	AuditBypassCodeIssued        = "bypass_code.issued"
+	AuditBypassCodeUsed          = "bypass_code.used"
+	AuditSecretRotationStarted   = "secret_rotation.started"
+	AuditSecretRotationCompleted = "secret_rotation.completed"
+	AuditSecretRotationExpired   = "secret_rotation.expired"
+	AuditEnrollmentsExported     = "enrollments.exported"
+	AuditMigrationExported       = "migration.exported"
+
+	AuditAccountRecoveryRequested = "account_recovery.requested"
+	AuditAccountRecoveryCancelled = "account_recovery.cancelled"
+	AuditAccountRecoveryCompleted = "account_recovery.completed"
+
+	auditEventIDSize     = 16
+	defaultAuditLimit    = 100
+	maxAuditLimit        = 1000
+	ErrorAuditNotQueried = "Audit sink does not support queries"
+)
+
+// AuditSink is an append-only destination of audit events.
+type AuditSink interface {
+	Write(event *proto.AuditEvent) error
+}
+
+// AuditQuerier is implemented by sinks able to search the written events.
+type AuditQuerier interface {
+	Query(filter *proto.MfaQueryAuditEventsDataRequest) ([]*proto.AuditEvent, error)
+}
+
+func (s *service) QueryAuditEvents(ctx context.Context, req *proto.MfaQueryAuditEventsDataRequest, res *proto.MfaQueryAuditEventsDataResponse) error {
+	s = s.withContext(ctx)
+
+	querier, ok := s.opts().AuditSink.(AuditQuerier)
+	if !ok {
+		res.Error = &proto.Error{Message: ErrorAuditNotQueried}
+		return nil
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = defaultAuditLimit
+	}
+	if req.Limit > maxAuditLimit {
+		req.Limit = maxAuditLimit
+	}
+
+	events, err := querier.Query(req)
+	if err != nil {
+		s.logger.Error("Query audit events failed with error", zap.Error(err))
+
+		return err
+	}
+
+	res.Events = events
+
+	return nil
+}
+
+// audit writes the event to the audit sink, updates the metrics and publishes
+// the matching domain event. Failures are logged and do not fail the request.
+func (s *service) audit(event *proto.AuditEvent) {
+	id := make([]byte, auditEventIDSize)
+	if _, err := rand.Read(id); err != nil {
+		s.logger.Error("Generate audit event id failed with error", zap.Error(err))
+	}
+
+	event.ID = hex.EncodeToString(id)
+	event.CreatedAt = time.Now().Unix()
+
+	options := s.opts()
+	if options.AuditSink != nil {
+		if err := options.AuditSink.Write(event); err != nil {
+			s.logger.Error(
+				"Write audit event failed with error",
+				zap.Error(err),
+				zap.String("type", event.Type),
+				zap.String("userId", event.UserID),
+				zap.String("providerId", event.ProviderID),
+			)
+		}
+	}
+
+	observeAuditEvent(event)
+	switch event.Type {
+	case AuditEnrollmentCreated, AuditEnrollmentRemoved, AuditRecoveryCodeUsed:
+		s.trackRecoveryCodes(event.UserID, event.ProviderID)
+	}
+
+	if msg := domainEvent(event); msg != nil && options.Outbox != nil {
+		if err := options.Outbox.Enqueue(msg); err != nil {
+			s.logger.Error("Enqueue domain event failed with error", zap.Error(err), zap.String("type", event.Type))
+		}
+	}
+}
+
+// auditVerification records the result of a check.
+func (s *service) auditVerification(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse) {
+	event := &proto.AuditEvent{
+		Type:       AuditVerificationSucceeded,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     res.Method,
+		DeviceID:   res.DeviceID,
+	}
+
+	if !res.Result {
+		event.Type = AuditVerificationFailed
+		if res.Error != nil {
+			event.Reason = res.Error.Message
+		}
+	}
+
+	s.audit(event)
+
+	if res.Result && res.Method == MethodRecoveryCode {
+		s.audit(&proto.AuditEvent{
+			Type:       AuditRecoveryCodeUsed,
+			UserID:     req.UserID,
+			ProviderID: req.ProviderID,
+			Method:     res.Method,
+		})
+	}
+
+	if res.Result && res.Method == MethodBypassCode {
+		s.audit(&proto.AuditEvent{
+			Type:       AuditBypassCodeUsed,
+			UserID:     req.UserID,
+			ProviderID: req.ProviderID,
+			Method:     res.Method,
+		})
+	}
+}
+
+// domainEvent maps an audit event to the event published for other services.
+func domainEvent(event *proto.AuditEvent) protobuf.Message {
+	switch event.Type {
+	case AuditEnrollmentCreated:
+		return &proto.MfaEnrolled{
+			UserID:     event.UserID,
+			ProviderID: event.ProviderID,
+			Method:     event.Method,
+			DeviceID:   event.DeviceID,
+			CreatedAt:  event.CreatedAt,
+		}
+	case AuditEnrollmentRemoved:
+		return &proto.MfaRemoved{
+			UserID:     event.UserID,
+			ProviderID: event.ProviderID,
+			Method:     event.Method,
+			DeviceID:   event.DeviceID,
+			CreatedAt:  event.CreatedAt,
+		}
+	case AuditRecoveryCodeUsed:
+		return &proto.RecoveryCodeUsed{
+			UserID:     event.UserID,
+			ProviderID: event.ProviderID,
+			CreatedAt:  event.CreatedAt,
+		}
+	case AuditVerificationFailed:
+		return &proto.VerificationFailed{
+			UserID:     event.UserID,
+			ProviderID: event.ProviderID,
+			Method:     event.Method,
+			Reason:     event.Reason,
+			CreatedAt:  event.CreatedAt,
+		}
+	case AuditLockout:
+		return &proto.LockedOut{
+			UserID:     event.UserID,
+			ProviderID: event.ProviderID,
+			Until:      event.ExpiresAt,
+			CreatedAt:  event.CreatedAt,
+		}
+	case AuditBypassCodeIssued:
+		return &proto.BypassCodeIssued{
+			UserID:     event.UserID,
+			ProviderID: event.ProviderID,
+			Actor:      event.Actor,
+			Reason:     event.Reason,
+			ExpiresAt:  event.ExpiresAt,
+			CreatedAt:  event.CreatedAt,
+		}
+	case AuditBypassCodeUsed:
+		return &proto.BypassCodeUsed{
+			UserID:     event.UserID,
+			ProviderID: event.ProviderID,
+			CreatedAt:  event.CreatedAt,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package mfa
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	mfaAuditStorage      = "mfa_audit"
+	auditStorageField    = "event"
+	auditStorageScanSize = 500
+
+	// DefaultAuditMaxLen is the approximate number of events kept in the stream.
+	DefaultAuditMaxLen = 1000000
+)
+
+type storageAuditSink struct {
+	redis  *redis.Client
+	maxLen int64
+}
+
+// NewStorageAuditSink returns a sink appending events to a Redis stream, the sink supports queries.
+// The stream is trimmed to about maxLen events, the oldest are removed first. It is not trimmed
+// when maxLen is 0.
+func NewStorageAuditSink(redis *redis.Client, maxLen int64) AuditSink {
+	return &storageAuditSink{redis: redis, maxLen: maxLen}
+}
+
+func (s *storageAuditSink) Write(event *proto.AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.redis.XAdd(&redis.XAddArgs{
+		Stream:       mfaAuditStorage,
+		MaxLenApprox: s.maxLen,
+		Values:       map[string]interface{}{auditStorageField: data},
+	}).Err()
+}
+
+// Query scans the stream in the time range of the filter, stream ids start
+// with the time of the event in milliseconds.
+func (s *storageAuditSink) Query(filter *proto.MfaQueryAuditEventsDataRequest) ([]*proto.AuditEvent, error) {
+	start, stop := "-", "+"
+	if filter.From > 0 {
+		start = strconv.FormatInt(filter.From*1000, 10)
+	}
+	if filter.To > 0 {
+		stop = strconv.FormatInt(filter.To*1000+999, 10)
+	}
+
+	types := map[string]bool{}
+	for _, t := range filter.Types {
+		types[t] = true
+	}
+
+	var events []*proto.AuditEvent
+	for {
+		messages, err := s.redis.XRangeN(mfaAuditStorage, start, stop, auditStorageScanSize).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range messages {
+			data, ok := m.Values[auditStorageField].(string)
+			if !ok {
+				continue
+			}
+
+			event := &proto.AuditEvent{}
+			if err := json.Unmarshal([]byte(data), event); err != nil {
+				return nil, err
+			}
+
+			if filter.UserID != "" && event.UserID != filter.UserID {
+				continue
+			}
+			if filter.ProviderID != "" && event.ProviderID != filter.ProviderID {
+				continue
+			}
+			if len(types) > 0 && !types[event.Type] {
+				continue
+			}
+
+			events = append(events, event)
+			if len(events) >= int(filter.Limit) {
+				return events, nil
+			}
+		}
+
+		if len(messages) < auditStorageScanSize {
+			return events, nil
+		}
+
+		start = nextStreamID(messages[len(messages)-1].ID)
+	}
+}
+
+// nextStreamID returns the smallest stream id greater than id.
+func nextStreamID(id string) string {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return id
+	}
+
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return id
+	}
+
+	return fmt.Sprintf("%s-%d", parts[0], seq+1)
+}
+
+type fileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink returns a sink appending events to the file as JSON lines.
+func NewFileAuditSink(path string) (AuditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileAuditSink{file: file}, nil
+}
+
+func (s *fileAuditSink) Write(event *proto.AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+func (s *fileAuditSink) Close() error {
+	return s.file.Close()
+}
+
+type brokerAuditSink struct {
+	outbox *Outbox
+}
+
+// NewBrokerAuditSink returns a sink publishing events to the broker through
+// the outbox, a publisher for proto.AuditEvent must be registered in it.
+func NewBrokerAuditSink(outbox *Outbox) AuditSink {
+	return &brokerAuditSink{outbox: outbox}
+}
+
+func (s *brokerAuditSink) Write(event *proto.AuditEvent) error {
+	return s.outbox.Enqueue(event)
+}
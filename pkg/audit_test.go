@@ -0,0 +1,113 @@
+package mfa
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestQueryAuditEventsToReturnLifecycleEvents() {
+	res1 := suite.createDevice("")
+	code, _ := totp.GenerateCode(res1.SecretKey, time.Now())
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "000000"}, &proto.MfaCheckDataResponse{})
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, &proto.MfaCheckDataResponse{})
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: res1.RecoveryCode[0]}, &proto.MfaCheckDataResponse{})
+	_ = suite.service.RemoveDevice(context.TODO(), &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: res1.DeviceID}, &proto.MfaRemoveDeviceDataResponse{})
+
+	res := &proto.MfaQueryAuditEventsDataResponse{}
+	req := &proto.MfaQueryAuditEventsDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}
+	err := suite.service.QueryAuditEvents(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), res.Error)
+
+	var types []string
+	for _, e := range res.Events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(suite.T(), []string{
+		AuditEnrollmentCreated,
+		AuditVerificationFailed,
+		AuditEnrollmentConfirmed,
+		AuditVerificationSucceeded,
+		AuditVerificationSucceeded,
+		AuditRecoveryCodeUsed,
+		AuditEnrollmentRemoved,
+	}, types)
+	assert.Equal(suite.T(), MethodTotp, res.Events[1].Method)
+	assert.Equal(suite.T(), ErrorCodeInvalid, res.Events[1].Reason)
+	assert.Equal(suite.T(), res1.DeviceID, res.Events[3].DeviceID)
+	assert.Equal(suite.T(), MethodRecoveryCode, res.Events[4].Method)
+}
+
+func (suite *ServiceTestSuite) TestQueryAuditEventsToFilterByTypeAndTime() {
+	suite.createDevice("")
+	suite.createDevice("")
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "000000"}, &proto.MfaCheckDataResponse{})
+
+	res := &proto.MfaQueryAuditEventsDataResponse{}
+	req := &proto.MfaQueryAuditEventsDataRequest{UserID: suite.userID, Types: []string{AuditEnrollmentCreated}, Limit: 1}
+	_ = suite.service.QueryAuditEvents(context.TODO(), req, res)
+	assert.Equal(suite.T(), 1, len(res.Events))
+	assert.Equal(suite.T(), AuditEnrollmentCreated, res.Events[0].Type)
+
+	res = &proto.MfaQueryAuditEventsDataResponse{}
+	req = &proto.MfaQueryAuditEventsDataRequest{UserID: suite.userID, From: time.Now().Add(time.Hour).Unix()}
+	_ = suite.service.QueryAuditEvents(context.TODO(), req, res)
+	assert.Empty(suite.T(), res.Events)
+
+	res = &proto.MfaQueryAuditEventsDataResponse{}
+	req = &proto.MfaQueryAuditEventsDataRequest{UserID: suite.userID, From: time.Now().Add(-time.Hour).Unix(), To: time.Now().Unix()}
+	_ = suite.service.QueryAuditEvents(context.TODO(), req, res)
+	assert.Equal(suite.T(), 3, len(res.Events))
+}
+
+func (suite *ServiceTestSuite) TestFileAuditSinkToAppendEvents() {
+	file, _ := ioutil.TempFile("", "audit")
+	_ = file.Close()
+	defer os.Remove(file.Name())
+
+	sink, err := NewFileAuditSink(file.Name())
+	assert.NoError(suite.T(), err)
+
+	suite.service = NewService(suite.redis, zap.L(), Audit(sink))
+	suite.createDevice("")
+	_ = sink.(*fileAuditSink).Close()
+
+	f, _ := os.Open(file.Name())
+	defer f.Close()
+
+	var events []*proto.AuditEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		event := &proto.AuditEvent{}
+		assert.NoError(suite.T(), json.Unmarshal(scanner.Bytes(), event))
+		events = append(events, event)
+	}
+	assert.Equal(suite.T(), 1, len(events))
+	assert.Equal(suite.T(), AuditEnrollmentCreated, events[0].Type)
+	assert.Equal(suite.T(), suite.userID, events[0].UserID)
+
+	res := &proto.MfaQueryAuditEventsDataResponse{}
+	err = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{}, res)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ErrorAuditNotQueried, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestStorageAuditSinkToTrimStream() {
+	sink := NewStorageAuditSink(suite.redis, 10)
+	for i := 0; i < 1000; i++ {
+		assert.NoError(suite.T(), sink.Write(&proto.AuditEvent{Type: AuditVerificationFailed, UserID: suite.userID}))
+	}
+
+	length, err := suite.redis.XLen(mfaAuditStorage).Result()
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), length >= 10 && length < 1000, "stream length %d", length)
+}
@@ -0,0 +1,374 @@
+package mfa
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	microErrors "github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpcMetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	APIKeyHeader = "X-Api-Key"
+
+	authWildcard    = "*"
+	grpcServicePath = "/proto.MfaService/"
+	// microRemoteHeader is the metadata go-micro sets to the remote address of the connection.
+	microRemoteHeader = "Remote"
+	// peerIdleTimeout is how long the certificate of a go-micro connection is kept after its last call.
+	peerIdleTimeout = time.Hour
+
+	ErrorUnauthenticated = "Caller is not authenticated"
+	ErrorForbidden       = "Caller is not allowed to call %s for provider %q"
+)
+
+// AuthClient is a caller of the service identified by an API key or by the
+// common name of its TLS client certificate.
+type AuthClient struct {
+	ID           string   `json:"id"`
+	APIKeys      []string `json:"api_keys"`
+	CommonNames  []string `json:"common_names"`
+	ProviderIDs  []string `json:"provider_ids"`
+	Operations   []string `json:"operations"`
+	providers    map[string]bool
+	operations   map[string]bool
+	allProviders bool
+}
+
+// Authorizer authenticates callers and allows them only the configured operations and providers.
+type Authorizer struct {
+	logger      *zap.Logger
+	apiKeys     map[[sha256.Size]byte]*AuthClient
+	commonNames map[string]*AuthClient
+	peers       *peerCertificates
+}
+
+// peerCertificates keeps the common names of the verified client certificates
+// of go-micro connections by remote address.
+type peerCertificates struct {
+	mu       sync.Mutex
+	byRemote map[string]*peerCertificate
+	prunedAt time.Time
+}
+
+type peerCertificate struct {
+	commonNames []string
+	usedAt      time.Time
+}
+
+type authClientIDKey struct{}
+
+// authServerStream passes the context carrying the authenticated client to the stream handler.
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// ContextWithAuthClientID returns a context carrying the ID of the client
+// authenticated for the call.
+func ContextWithAuthClientID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, authClientIDKey{}, id)
+}
+
+// AuthClientID returns the ID of the client authenticated for the call, it is
+// empty when calls are not authorized.
+func AuthClientID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(authClientIDKey{}).(string)
+	return id
+}
+
+// set replaces the common names of the connection, every handshake of a new
+// connection from the same address replaces them.
+func (p *peerCertificates) set(remote string, commonNames []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(p.prunedAt) > time.Minute {
+		for r, c := range p.byRemote {
+			if now.Sub(c.usedAt) > peerIdleTimeout {
+				delete(p.byRemote, r)
+			}
+		}
+		p.prunedAt = now
+	}
+
+	p.byRemote[remote] = &peerCertificate{commonNames: commonNames, usedAt: now}
+}
+
+func (p *peerCertificates) get(remote string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.byRemote[remote]
+	if !ok {
+		return nil
+	}
+	c.usedAt = time.Now()
+
+	return c.commonNames
+}
+
+// LoadAuthClients reads the list of clients from a JSON file.
+func LoadAuthClients(path string) ([]*AuthClient, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []*AuthClient
+	if err = json.Unmarshal(data, &clients); err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func NewAuthorizer(clients []*AuthClient, logger *zap.Logger) (*Authorizer, error) {
+	a := &Authorizer{
+		logger:      logger,
+		apiKeys:     map[[sha256.Size]byte]*AuthClient{},
+		commonNames: map[string]*AuthClient{},
+		peers:       &peerCertificates{byRemote: map[string]*peerCertificate{}},
+	}
+
+	for _, c := range clients {
+		if c.ID == "" {
+			return nil, errors.New("auth client id is required")
+		}
+
+		c.providers = map[string]bool{}
+		for _, providerId := range c.ProviderIDs {
+			c.providers[providerId] = true
+		}
+		c.allProviders = c.providers[authWildcard]
+
+		c.operations = map[string]bool{}
+		for _, op := range c.Operations {
+			c.operations[op] = true
+		}
+
+		for _, key := range c.APIKeys {
+			h := sha256.Sum256([]byte(key))
+			if _, ok := a.apiKeys[h]; ok || key == "" {
+				return nil, fmt.Errorf("auth client %s has an empty or duplicated api key", c.ID)
+			}
+			a.apiKeys[h] = c
+		}
+		for _, cn := range c.CommonNames {
+			if _, ok := a.commonNames[cn]; ok || cn == "" {
+				return nil, fmt.Errorf("auth client %s has an empty or duplicated common name", c.ID)
+			}
+			a.commonNames[cn] = c
+		}
+	}
+
+	return a, nil
+}
+
+// TLSConfig returns a copy of the TLS config of the go-micro transport which
+// records the verified client certificate of every connection. go-micro does
+// not pass the TLS state to handlers, so HandlerWrapper finds the certificate
+// by the remote address of the call.
+func (a *Authorizer) TLSConfig(config *tls.Config) *tls.Config {
+	c := config.Clone()
+	c.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		remote := hello.Conn.RemoteAddr().String()
+
+		cc := config.Clone()
+		cc.VerifyConnection = func(state tls.ConnectionState) error {
+			if config.VerifyConnection != nil {
+				if err := config.VerifyConnection(state); err != nil {
+					return err
+				}
+			}
+			a.peers.set(remote, verifiedCommonNames(state.VerifiedChains))
+			return nil
+		}
+		return cc, nil
+	}
+
+	return c
+}
+
+// HandlerWrapper enforces authorization of go-micro calls, the caller passes
+// its API key in the X-Api-Key metadata or presents a client certificate to a
+// transport using the TLSConfig of the authorizer.
+func (a *Authorizer) HandlerWrapper() server.HandlerWrapper {
+	return func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			var apiKey string
+			var commonNames []string
+			if md, ok := metadata.FromContext(ctx); ok {
+				for k, v := range md {
+					if strings.EqualFold(k, APIKeyHeader) {
+						apiKey = v
+					}
+				}
+				// go-micro sets the remote address after the metadata of the caller.
+				if remote := md[microRemoteHeader]; remote != "" {
+					commonNames = a.peers.get(remote)
+				}
+			}
+
+			op := req.Method()
+			if i := strings.LastIndex(op, "."); i >= 0 {
+				op = op[i+1:]
+			}
+
+			client := a.authenticate(apiKey, commonNames, op)
+			if client == nil {
+				return microErrors.Unauthorized(ServiceName, ErrorUnauthenticated)
+			}
+			if err := a.authorize(client, op, req.Body()); err != nil {
+				return microErrors.Forbidden(ServiceName, "%s", err.Error())
+			}
+
+			return fn(ContextWithAuthClientID(ctx, client.ID), req, rsp)
+		}
+	}
+}
+
+// UnaryInterceptor enforces authorization of MfaService gRPC calls, the caller
+// passes its API key in the x-api-key metadata or presents a client certificate.
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, grpcServicePath) {
+			return handler(ctx, req)
+		}
+
+		client, err := a.authorizeGRPC(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ContextWithAuthClientID(ctx, client.ID), req)
+	}
+}
+
+// StreamInterceptor enforces authorization of MfaService gRPC streams. The
+// request is read by the handler, so streams are allowed only to clients
+// allowed for all providers.
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, grpcServicePath) {
+			return handler(srv, ss)
+		}
+
+		client, err := a.authorizeGRPC(ss.Context(), info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ContextWithAuthClientID(ss.Context(), client.ID)})
+	}
+}
+
+func (a *Authorizer) authorizeGRPC(ctx context.Context, fullMethod string, req interface{}) (*AuthClient, error) {
+	var apiKey string
+	if md, ok := grpcMetadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(APIKeyHeader); len(v) > 0 {
+			apiKey = v[0]
+		}
+	}
+
+	var commonNames []string
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			commonNames = verifiedCommonNames(info.State.VerifiedChains)
+		}
+	}
+
+	op := strings.TrimPrefix(fullMethod, grpcServicePath)
+	client := a.authenticate(apiKey, commonNames, op)
+	if client == nil {
+		return nil, status.Error(codes.Unauthenticated, ErrorUnauthenticated)
+	}
+	if err := a.authorize(client, op, req); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return client, nil
+}
+
+func (a *Authorizer) authenticate(apiKey string, commonNames []string, op string) *AuthClient {
+	if apiKey != "" {
+		if c, ok := a.apiKeys[sha256.Sum256([]byte(apiKey))]; ok {
+			return c
+		}
+	} else {
+		for _, cn := range commonNames {
+			if c, ok := a.commonNames[cn]; ok {
+				return c
+			}
+		}
+	}
+
+	a.logger.Warn("Unauthenticated call rejected", zap.String("operation", op))
+
+	return nil
+}
+
+func (a *Authorizer) authorize(client *AuthClient, op string, req interface{}) error {
+	var providerId string
+	switch r := req.(type) {
+	case interface{ GetProviderID() string }:
+		providerId = r.GetProviderID()
+	case interface{ GetConfig() *proto.ProviderConfig }:
+		providerId = r.GetConfig().GetProviderID()
+	}
+
+	allowed := client.operations[authWildcard] || client.operations[op]
+	if allowed && !client.allProviders {
+		// Calls for all providers, e.g. an audit query without ProviderID, need the wildcard.
+		allowed = providerId != "" && client.providers[providerId]
+	}
+
+	if !allowed {
+		a.logger.Warn(
+			"Unauthorized call rejected",
+			zap.String("clientId", client.ID),
+			zap.String("operation", op),
+			zap.String("providerId", providerId),
+		)
+
+		return fmt.Errorf(ErrorForbidden, op, providerId)
+	}
+
+	return nil
+}
+
+// verifiedCommonNames returns the common names of the leaf certificates of the verified chains.
+func verifiedCommonNames(chains [][]*x509.Certificate) []string {
+	var commonNames []string
+	for _, chain := range chains {
+		if len(chain) > 0 {
+			commonNames = append(commonNames, chain[0].Subject.CommonName)
+		}
+	}
+	return commonNames
+}
@@ -0,0 +1,216 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	microErrors "github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcMetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+type testRequest struct {
+	server.Request
+	method string
+	body   interface{}
+}
+
+func (r *testRequest) Method() string {
+	return r.method
+}
+
+func (r *testRequest) Body() interface{} {
+	return r.body
+}
+
+func (suite *ServiceTestSuite) newTestAuthorizer() *Authorizer {
+	authorizer, err := NewAuthorizer([]*AuthClient{
+		{ID: "frontend", APIKeys: []string{"frontend-key"}, ProviderIDs: []string{suite.ProviderID}, Operations: []string{"Check"}},
+		{ID: "admin", APIKeys: []string{"admin-key"}, CommonNames: []string{"admin.local"}, ProviderIDs: []string{"*"}, Operations: []string{"*"}},
+	}, zap.L())
+	assert.NoError(suite.T(), err)
+	return authorizer
+}
+
+func (suite *ServiceTestSuite) TestAuthorizerToRejectInvalidClients() {
+	_, err := NewAuthorizer([]*AuthClient{{APIKeys: []string{"key"}}}, zap.L())
+	assert.Error(suite.T(), err)
+
+	_, err = NewAuthorizer([]*AuthClient{{ID: "a", APIKeys: []string{"key"}}, {ID: "b", APIKeys: []string{"key"}}}, zap.L())
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ServiceTestSuite) TestAuthorizerHandlerWrapperToEnforcePermissions() {
+	called := 0
+	var clientId string
+	fn := suite.newTestAuthorizer().HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		called++
+		clientId = AuthClientID(ctx)
+		return nil
+	})
+	call := func(apiKey string, method string, providerId string) error {
+		ctx := context.TODO()
+		if apiKey != "" {
+			ctx = metadata.NewContext(ctx, metadata.Metadata{APIKeyHeader: apiKey})
+		}
+		req := &testRequest{method: method, body: &proto.MfaCheckDataRequest{ProviderID: providerId}}
+		return fn(ctx, req, nil)
+	}
+
+	assert.NoError(suite.T(), call("frontend-key", "MfaService.Check", suite.ProviderID))
+	assert.Equal(suite.T(), "frontend", clientId)
+	assert.NoError(suite.T(), call("admin-key", "MfaService.Create", "other"))
+	assert.Equal(suite.T(), "admin", clientId)
+	assert.Equal(suite.T(), 2, called)
+
+	err := call("", "MfaService.Check", suite.ProviderID)
+	assert.Equal(suite.T(), int32(http.StatusUnauthorized), microErrors.Parse(err.Error()).Code)
+
+	err = call("unknown-key", "MfaService.Check", suite.ProviderID)
+	assert.Equal(suite.T(), int32(http.StatusUnauthorized), microErrors.Parse(err.Error()).Code)
+
+	err = call("frontend-key", "MfaService.Create", suite.ProviderID)
+	assert.Equal(suite.T(), int32(http.StatusForbidden), microErrors.Parse(err.Error()).Code)
+
+	err = call("frontend-key", "MfaService.Check", "other")
+	assert.Equal(suite.T(), int32(http.StatusForbidden), microErrors.Parse(err.Error()).Code)
+	assert.Equal(suite.T(), fmt.Sprintf(ErrorForbidden, "Check", "other"), microErrors.Parse(err.Error()).Detail)
+
+	assert.Equal(suite.T(), 2, called)
+}
+
+func (suite *ServiceTestSuite) TestAuthorizerHandlerWrapperToAuthenticateClientCertificate() {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(suite.T(), err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	assert.NoError(suite.T(), err)
+	ca, _ = x509.ParseCertificate(caDER)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	certificate := func(cn string, usage x509.ExtKeyUsage) tls.Certificate {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(suite.T(), err)
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: cn},
+			DNSNames:     []string{cn},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}, ca, &key.PublicKey, caKey)
+		assert.NoError(suite.T(), err)
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+
+	authorizer := suite.newTestAuthorizer()
+	serverConfig := authorizer.TLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{certificate("mfa.local", x509.ExtKeyUsageServerAuth)},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	})
+	clientConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate("admin.local", x509.ExtKeyUsageClientAuth)},
+		RootCAs:      pool,
+		ServerName:   "mfa.local",
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	done := make(chan error, 1)
+	go func() {
+		done <- tls.Client(clientConn, clientConfig).Handshake()
+	}()
+	assert.NoError(suite.T(), tls.Server(serverConn, serverConfig).Handshake())
+	assert.NoError(suite.T(), <-done)
+
+	fn := authorizer.HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})
+	call := func(remote string) error {
+		ctx := metadata.NewContext(context.TODO(), metadata.Metadata{microRemoteHeader: remote})
+		return fn(ctx, &testRequest{method: "MfaService.Create", body: &proto.MfaCreateDataRequest{ProviderID: "other"}}, nil)
+	}
+
+	assert.NoError(suite.T(), call(serverConn.RemoteAddr().String()))
+
+	err = call("127.0.0.1:1")
+	assert.Equal(suite.T(), int32(http.StatusUnauthorized), microErrors.Parse(err.Error()).Code)
+}
+
+func (suite *ServiceTestSuite) TestAuthorizerToRequireWildcardForAllProviders() {
+	authorizer, _ := NewAuthorizer([]*AuthClient{
+		{ID: "auditor", APIKeys: []string{"key"}, ProviderIDs: []string{suite.ProviderID}, Operations: []string{"QueryAuditEvents"}},
+	}, zap.L())
+	client := authorizer.authenticate("key", nil, "QueryAuditEvents")
+
+	assert.NoError(suite.T(), authorizer.authorize(client, "QueryAuditEvents", &proto.MfaQueryAuditEventsDataRequest{ProviderID: suite.ProviderID}))
+	assert.Error(suite.T(), authorizer.authorize(client, "QueryAuditEvents", &proto.MfaQueryAuditEventsDataRequest{}))
+}
+
+func (suite *ServiceTestSuite) TestAuthorizerUnaryInterceptorToEnforcePermissions() {
+	interceptor := suite.newTestAuthorizer().UnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return AuthClientID(ctx), nil
+	}
+	req := &proto.MfaCreateDataRequest{ProviderID: suite.ProviderID}
+
+	ctx := grpcMetadata.NewIncomingContext(context.TODO(), grpcMetadata.Pairs(APIKeyHeader, "admin-key"))
+	res, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: grpcServicePath + "Create"}, handler)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "admin", res)
+
+	ctx = grpcMetadata.NewIncomingContext(context.TODO(), grpcMetadata.Pairs(APIKeyHeader, "frontend-key"))
+	_, err = interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: grpcServicePath + "Create"}, handler)
+	assert.Equal(suite.T(), codes.PermissionDenied, status.Code(err))
+
+	_, err = interceptor(context.TODO(), req, &grpc.UnaryServerInfo{FullMethod: grpcServicePath + "Create"}, handler)
+	assert.Equal(suite.T(), codes.Unauthenticated, status.Code(err))
+
+	_, err = interceptor(context.TODO(), req, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *ServiceTestSuite) TestGatewayToEnforcePermissions() {
+	gateway := NewGateway(suite.service, suite.newTestAuthorizer(), zap.L())
+	serve := func(apiKey string, path string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, GatewayPrefix+path, strings.NewReader(fmt.Sprintf(`{"ProviderID":"%s","UserID":"%s","AppName":"test"}`, suite.ProviderID, suite.userID)))
+		if apiKey != "" {
+			r.Header.Set(APIKeyHeader, apiKey)
+		}
+		gateway.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, serve("", "create"))
+	assert.Equal(suite.T(), http.StatusForbidden, serve("frontend-key", "create"))
+	assert.Equal(suite.T(), http.StatusOK, serve("admin-key", "create"))
+}
@@ -0,0 +1,199 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+const (
+	mfaBypassStoragePattern = "mfa_bypass_%s_%s"
+	bypassCodePrefix        = "BYPASS-"
+	bypassCodeSize          = 10
+	bypassCodeGroupSize     = 4
+	defaultBypassCodeTTL    = 15 * time.Minute
+	maxBypassCodeTTL        = 24 * time.Hour
+)
+
+// bypassCode is a single-use code issued by an administrator. Only the hash of
+// the code is stored, the key expires with the code.
+type bypassCode struct {
+	Hash      string `json:"hash"`
+	Actor     string `json:"actor,omitempty"`
+	Note      string `json:"note,omitempty"`
+	Reason    string `json:"reason"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// IssueBypassCode issues a code accepted once by Check in place of any factor.
+// A new code replaces the previous one of the user.
+func (s *service) IssueBypassCode(ctx context.Context, req *proto.MfaIssueBypassCodeDataRequest, res *proto.MfaIssueBypassCodeDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateIssueBypassCodeRequest(req); err != nil {
+		s.logger.Error("Validate issue bypass code request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	ttl := defaultBypassCodeTTL
+	if req.TTL > 0 {
+		ttl = time.Duration(req.TTL) * time.Second
+	}
+
+	code, err := generateBypassCode()
+	if err != nil {
+		s.logger.Error("Generate bypass code failed with error", zap.Error(err))
+
+		return err
+	}
+
+	expiresAt := time.Now().Add(ttl).Unix()
+	data, err := json.Marshal(&bypassCode{
+		Hash:      sha256Hex(code),
+		Actor:     AuthClientID(ctx),
+		Note:      req.Actor,
+		Reason:    req.Reason,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		s.logger.Error("Marshal bypass code failed with error", zap.Error(err))
+
+		return err
+	}
+
+	_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(s.GetUserProvidersStorageKey(req.UserID), req.ProviderID)
+		pipe.Set(s.GetBypassStorageKey(req.UserID, req.ProviderID), data, ttl)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Add bypass code to Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	s.logger.Warn(
+		"Bypass code issued by administrator",
+		zap.String("userId", req.UserID),
+		zap.String("providerId", req.ProviderID),
+		zap.String("actor", AuthClientID(ctx)),
+		zap.String("note", req.Actor),
+		zap.String("reason", req.Reason),
+	)
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditBypassCodeIssued,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     MethodBypassCode,
+		Actor:      AuthClientID(ctx),
+		Reason:     req.Reason,
+		Note:       req.Actor,
+		ExpiresAt:  expiresAt,
+	})
+
+	res.Code = code
+	res.ExpiresAt = expiresAt
+
+	return nil
+}
+
+// checkBypassCode accepts the bypass code of the user and removes it, so a
+// code is accepted once even by concurrent checks.
+func (s *service) checkBypassCode(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse) error {
+	key := s.GetBypassStorageKey(req.UserID, req.ProviderID)
+
+	data, err := s.redis.Get(key).Bytes()
+	if err != nil && err != redis.Nil {
+		s.logger.Error("Getting bypass code from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	bc := &bypassCode{}
+	if err == nil {
+		if err = json.Unmarshal(data, bc); err != nil {
+			s.logger.Error("Unmarshal bypass code failed with error", zap.Error(err))
+
+			return err
+		}
+	}
+
+	code := sha256Hex(strings.ToUpper(strings.TrimSpace(req.Code)))
+	if bc.Hash == "" || subtle.ConstantTimeCompare([]byte(code), []byte(bc.Hash)) != 1 {
+		s.logger.Warn(
+			"Validating bypass code failed",
+			zap.String("userId", req.UserID),
+			zap.String("providerId", req.ProviderID),
+		)
+
+		res.Error = &proto.Error{
+			Message: ErrorCodeInvalid,
+		}
+		return nil
+	}
+
+	removed, err := s.redis.Del(key).Result()
+	if err != nil {
+		s.logger.Error("Remove bypass code from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+	if removed == 0 {
+		res.Error = &proto.Error{
+			Message: ErrorCodeInvalid,
+		}
+		return nil
+	}
+
+	res.Result = true
+
+	return nil
+}
+
+// generateBypassCode returns a code such as BYPASS-ABCD-EFGH-IJKL-MNOP. The
+// prefix tells it apart from other codes and the groups never form a TOTP code.
+func generateBypassCode() (string, error) {
+	secret := make([]byte, bypassCodeSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	encoded := strings.TrimRight(base32.StdEncoding.EncodeToString(secret), "=")
+	groups := make([]string, 0, len(encoded)/bypassCodeGroupSize)
+	for i := 0; i < len(encoded); i += bypassCodeGroupSize {
+		groups = append(groups, encoded[i:i+bypassCodeGroupSize])
+	}
+
+	return bypassCodePrefix + strings.Join(groups, "-"), nil
+}
+
+func isBypassCode(code string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(code)), bypassCodePrefix)
+}
+
+func (s *service) validateIssueBypassCodeRequest(req *proto.MfaIssueBypassCodeDataRequest) error {
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		return err
+	}
+	if req.Reason == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "Reason")
+	}
+	if req.TTL < 0 || req.TTL > int64(maxBypassCodeTTL/time.Second) {
+		return newRequestError(ErrorRequestPropertyFormat, "TTL")
+	}
+	return nil
+}
+
+func (s *service) GetBypassStorageKey(userId string, providerId string) string {
+	return fmt.Sprintf(mfaBypassStoragePattern, userId, providerId)
+}
@@ -0,0 +1,101 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"regexp"
+	"strings"
+	"time"
+)
+
+func (suite *ServiceTestSuite) issueBypassCode() *proto.MfaIssueBypassCodeDataResponse {
+	res := &proto.MfaIssueBypassCodeDataResponse{}
+	err := suite.service.IssueBypassCode(ContextWithAuthClientID(context.TODO(), "admin"), &proto.MfaIssueBypassCodeDataRequest{
+		ProviderID: suite.ProviderID,
+		UserID:     suite.userID,
+		Actor:      "support",
+		Reason:     "lost phone and recovery codes",
+	}, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+func (suite *ServiceTestSuite) TestCheckToAcceptBypassCodeOnce() {
+	issued := &testPublisher{}
+	used := &testPublisher{}
+	outbox := NewOutbox(suite.redis, zap.L())
+	outbox.Register(&proto.BypassCodeIssued{}, issued)
+	outbox.Register(&proto.BypassCodeUsed{}, used)
+	suite.service = NewService(suite.redis, zap.L(), EventOutbox(outbox))
+
+	suite.createDevice("")
+	bypass := suite.issueBypassCode()
+	assert.Regexp(suite.T(), regexp.MustCompile("^BYPASS(-[A-Z2-7]{4}){4}$"), bypass.Code)
+	assert.True(suite.T(), bypass.ExpiresAt > time.Now().Add(defaultBypassCodeTTL-time.Minute).Unix())
+
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: strings.ToLower(bypass.Code)}
+	res := &proto.MfaCheckDataResponse{}
+	err := suite.service.Check(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+	assert.Equal(suite.T(), MethodBypassCode, res.Method)
+
+	res = &proto.MfaCheckDataResponse{}
+	err = suite.service.Check(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorCodeInvalid, res.Error.Message)
+
+	events := &proto.MfaQueryAuditEventsDataResponse{}
+	_ = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{
+		UserID: suite.userID,
+		Types:  []string{AuditBypassCodeIssued, AuditBypassCodeUsed},
+	}, events)
+	assert.Equal(suite.T(), 2, len(events.Events))
+
+	assert.NoError(suite.T(), outbox.Flush(context.TODO()))
+	assert.Equal(suite.T(), 1, len(issued.messages))
+	assert.Equal(suite.T(), "admin", issued.messages[0].(*proto.BypassCodeIssued).Actor)
+	assert.Equal(suite.T(), 1, len(used.messages))
+}
+
+func (suite *ServiceTestSuite) TestCheckToAcceptBypassCodeRegardlessOfFactors() {
+	suite.setProviderConfig(&proto.ProviderConfig{AllowedFactors: []string{MethodYubiKey}})
+	bypass := suite.issueBypassCode()
+
+	res := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: bypass.Code}, res)
+	assert.True(suite.T(), res.Result)
+}
+
+func (suite *ServiceTestSuite) TestCheckToRejectReplacedBypassCode() {
+	first := suite.issueBypassCode()
+	suite.issueBypassCode()
+
+	res := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: first.Code}, res)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorCodeInvalid, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestGetUserStatusToReturnBypassCodeExpiration() {
+	bypass := suite.issueBypassCode()
+
+	res := &proto.MfaGetUserStatusDataResponse{}
+	_ = suite.service.GetUserStatus(context.TODO(), &proto.MfaGetUserStatusDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, res)
+	assert.Equal(suite.T(), bypass.ExpiresAt, res.BypassCodeExpiresAt)
+}
+
+func (suite *ServiceTestSuite) TestIssueBypassCodeToReturnErrorRequestData() {
+	reqs := map[string]*proto.MfaIssueBypassCodeDataRequest{
+		"Reason is required field": {ProviderID: suite.ProviderID, UserID: suite.userID},
+		"TTL has invalid format":   {ProviderID: suite.ProviderID, UserID: suite.userID, Reason: "test", TTL: int64(maxBypassCodeTTL/time.Second) + 1},
+	}
+	for message, req := range reqs {
+		err := suite.service.IssueBypassCode(context.TODO(), req, &proto.MfaIssueBypassCodeDataResponse{})
+		assert.EqualError(suite.T(), err, message)
+	}
+}
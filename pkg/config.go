@@ -0,0 +1,403 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/micro/cli"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ConfigFileFlag = "config"
+	ConfigFileEnv  = "CONFIG_FILE"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configField is a field of a config struct, its struct tags are:
+//
+//	config    the key in the file and the name of the flag, the env var is the key in upper case;
+//	default   the value used when no layer sets the key;
+//	required  "true" when the value must not be empty;
+//	oneof     the comma separated values allowed;
+//	reload    "true" when a change is applied by a reload, other changes need a restart.
+type configField struct {
+	key   string
+	tag   reflect.StructTag
+	value reflect.Value
+}
+
+func (f *configField) env() string {
+	return strings.ToUpper(f.key)
+}
+
+func (f *configField) set(value string) error {
+	v, err := parseConfigValue(f.value.Type(), value)
+	if err != nil {
+		return fmt.Errorf("config %s: %v", f.key, err)
+	}
+
+	f.value.Set(v)
+
+	return nil
+}
+
+// setNode sets the value of the key in the file. Scalars are parsed as the env
+// vars are, lists and objects are decoded as JSON.
+func (f *configField) setNode(node *yaml.Node) error {
+	switch {
+	case node.Kind == yaml.ScalarNode && node.Tag == "!!null":
+		f.value.Set(reflect.Zero(f.value.Type()))
+	case node.Kind == yaml.ScalarNode:
+		return f.set(node.Value)
+	case node.Kind == yaml.MappingNode && f.value.Kind() == reflect.Map:
+		m := reflect.MakeMap(f.value.Type())
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := parseConfigValue(f.value.Type().Elem(), node.Content[i+1].Value)
+			if err != nil {
+				return fmt.Errorf("config %s: %v", f.key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(node.Content[i].Value).Convert(f.value.Type().Key()), v)
+		}
+		f.value.Set(m)
+	default:
+		var raw interface{}
+		if err := node.Decode(&raw); err != nil {
+			return fmt.Errorf("config %s: %v", f.key, err)
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("config %s: %v", f.key, err)
+		}
+		return f.set(string(data))
+	}
+
+	return nil
+}
+
+// parseConfigValue parses a value of the type. Maps are written as
+// "key:value,key:value", lists and structs as JSON.
+func parseConfigValue(t reflect.Type, value string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+
+	switch {
+	case t == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(int64(d))
+	case t.Kind() == reflect.String:
+		v.SetString(value)
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		i, err := strconv.ParseInt(value, 0, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		v = reflect.MakeMap(t)
+		for _, pair := range strings.Split(value, ",") {
+			if pair == "" {
+				continue
+			}
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) != 2 {
+				return v, fmt.Errorf("invalid map item %q", pair)
+			}
+			e, err := parseConfigValue(t.Elem(), kv[1])
+			if err != nil {
+				return v, err
+			}
+			v.SetMapIndex(reflect.ValueOf(kv[0]).Convert(t.Key()), e)
+		}
+	default:
+		p := reflect.New(t)
+		if err := json.Unmarshal([]byte(value), p.Interface()); err != nil {
+			return v, err
+		}
+		v = p.Elem()
+	}
+
+	return v, nil
+}
+
+func configFields(spec interface{}) ([]*configField, error) {
+	v := reflect.ValueOf(spec)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("config spec must be a pointer to a struct")
+	}
+	v = v.Elem()
+
+	var fields []*configField
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag
+		if key := tag.Get("config"); key != "" {
+			fields = append(fields, &configField{key: key, tag: tag, value: v.Field(i)})
+		}
+	}
+
+	return fields, nil
+}
+
+// configArgs returns the values of the flags named in fields, the other args
+// are left to the micro command line. Bool flags take no separate value.
+func configArgs(args []string, fields map[string]*configField) map[string]string {
+	values := map[string]string{}
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+
+		name, value, hasValue := strings.TrimLeft(args[i], "-"), "", false
+		if j := strings.Index(name, "="); j >= 0 {
+			name, value, hasValue = name[:j], name[j+1:], true
+		}
+
+		f, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if !hasValue {
+			if f != nil && f.value.Kind() == reflect.Bool {
+				value = "true"
+			} else if i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+		}
+
+		values[name] = value
+	}
+
+	return values
+}
+
+// ConfigFile returns the config file passed with the config flag or the
+// CONFIG_FILE env var, it is empty when there is none.
+func ConfigFile(args []string) string {
+	if path, ok := configArgs(args, map[string]*configField{ConfigFileFlag: nil})[ConfigFileFlag]; ok {
+		return path
+	}
+	return os.Getenv(ConfigFileEnv)
+}
+
+// ConfigFlags returns the flags of the config file and of the keys of spec. The
+// micro command line parses the args as well and fails on flags it doesn't know.
+func ConfigFlags(spec interface{}) []cli.Flag {
+	flags := []cli.Flag{
+		cli.StringFlag{Name: ConfigFileFlag, EnvVar: ConfigFileEnv, Usage: "YAML or JSON config file"},
+	}
+
+	fields, _ := configFields(spec)
+	for _, f := range fields {
+		usage := fmt.Sprintf("Overrides %s", f.env())
+		if f.value.Kind() == reflect.Bool {
+			flags = append(flags, cli.BoolFlag{Name: f.key, Usage: usage})
+		} else {
+			flags = append(flags, cli.StringFlag{Name: f.key, Usage: usage})
+		}
+	}
+
+	return flags
+}
+
+// LoadConfig fills spec, a pointer to a struct of config fields, from its
+// layers: the defaults, the YAML or JSON file at path, the env vars and the
+// flags in args, a layer overrides the ones before it. Unknown keys in the
+// file, values of the wrong type or not allowed and empty required values
+// fail the load.
+func LoadConfig(spec interface{}, path string, args []string) error {
+	fields, err := configFields(spec)
+	if err != nil {
+		return err
+	}
+
+	byKey := map[string]*configField{}
+	for _, f := range fields {
+		byKey[f.key] = f
+
+		f.value.Set(reflect.Zero(f.value.Type()))
+		if def, ok := f.tag.Lookup("default"); ok {
+			if err = f.set(def); err != nil {
+				return err
+			}
+		}
+	}
+
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var doc map[string]yaml.Node
+		if err = yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("config file %s: %v", path, err)
+		}
+
+		keys := make([]string, 0, len(doc))
+		for key := range doc {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			f, ok := byKey[key]
+			if !ok {
+				return fmt.Errorf("config file %s: unknown key %q", path, key)
+			}
+			node := doc[key]
+			if err = f.setNode(&node); err != nil {
+				return err
+			}
+		}
+	}
+
+	flags := configArgs(args, byKey)
+	for _, f := range fields {
+		if value, ok := os.LookupEnv(f.env()); ok {
+			if err = f.set(value); err != nil {
+				return err
+			}
+		}
+		if value, ok := flags[f.key]; ok {
+			if err = f.set(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if f.tag.Get("required") == "true" && f.value.IsZero() {
+			return fmt.Errorf("config %s (%s) is required", f.key, f.env())
+		}
+		if oneof := f.tag.Get("oneof"); oneof != "" && !containsString(strings.Split(oneof, ","), fmt.Sprint(f.value.Interface())) {
+			return fmt.Errorf("config %s must be one of %s", f.key, oneof)
+		}
+	}
+
+	return nil
+}
+
+// ReloadConfig copies the changed fields of next tagged reload to current and
+// returns their keys. The keys of the other changed fields are returned as
+// the ones needing a restart, their values in current are kept.
+func ReloadConfig(current interface{}, next interface{}) (reloaded []string, restart []string, err error) {
+	if reflect.TypeOf(current) != reflect.TypeOf(next) {
+		return nil, nil, errors.New("configs must be of the same type")
+	}
+
+	currentFields, err := configFields(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	nextFields, _ := configFields(next)
+
+	for i, f := range currentFields {
+		value := nextFields[i].value
+		if reflect.DeepEqual(f.value.Interface(), value.Interface()) {
+			continue
+		}
+
+		if f.tag.Get("reload") == "true" {
+			f.value.Set(value)
+			reloaded = append(reloaded, f.key)
+		} else {
+			restart = append(restart, f.key)
+		}
+	}
+
+	return reloaded, restart, nil
+}
+
+// WatchConfig checks the files returned by paths every interval and calls
+// reload when the content of one of them changed, until the context is done.
+// The paths are listed again on every check, so a file named by the reloaded
+// config is watched as well, empty paths are skipped. Reloads are logged and
+// counted by result, the running config is kept when a reload fails.
+func WatchConfig(ctx context.Context, paths func() []string, interval time.Duration, reload func() ([]string, error), logger *zap.Logger) {
+	last, err := configFilesHash(paths())
+	if err != nil {
+		logger.Error("Reading config file failed with error", zap.Error(err), zap.Strings("paths", paths()))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		hash, err := configFilesHash(paths())
+		if err != nil {
+			// Logged once until the file can be read again.
+			if last != "" {
+				logger.Error("Reading config file failed with error", zap.Error(err), zap.Strings("paths", paths()))
+			}
+			last = ""
+			continue
+		}
+		if hash == last {
+			continue
+		}
+		last = hash
+
+		keys, err := reload()
+		if err != nil {
+			configReloads.WithLabelValues(metricResultError).Inc()
+			logger.Error("Config reload failed with error, the running config is kept", zap.Error(err), zap.Strings("paths", paths()))
+
+			continue
+		}
+
+		configReloads.WithLabelValues(metricResultSuccess).Inc()
+		configLastReload.SetToCurrentTime()
+		logger.Info("Config reloaded", zap.Strings("paths", paths()), zap.Strings("keys", keys))
+	}
+}
+
+func configFilesHash(paths []string) (string, error) {
+	var hashes []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		hashes = append(hashes, sha256Hex(string(data)))
+	}
+	return strings.Join(hashes, ","), nil
+}
@@ -0,0 +1,185 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type testConfig struct {
+	Addr      string                   `config:"mfa_test_addr" required:"true"`
+	Port      int                      `config:"mfa_test_port" default:"8081"`
+	Mode      string                   `config:"mfa_test_mode" default:"micro" oneof:"micro,grpc"`
+	Insecure  bool                     `config:"mfa_test_insecure"`
+	Ratio     float64                  `config:"mfa_test_ratio" default:"1"`
+	Timeout   time.Duration            `config:"mfa_test_timeout" default:"5s" reload:"true"`
+	TTL       map[string]time.Duration `config:"mfa_test_ttl" reload:"true"`
+	Providers []*proto.ProviderConfig  `config:"mfa_test_providers" reload:"true"`
+}
+
+func (suite *ServiceTestSuite) writeConfigFile(name string, content string) string {
+	dir, err := ioutil.TempDir("", "mfa-config")
+	assert.NoError(suite.T(), err)
+	suite.T().Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	assert.NoError(suite.T(), ioutil.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func (suite *ServiceTestSuite) TestLoadConfigToLayerFileEnvAndFlags() {
+	path := suite.writeConfigFile("config.yaml", `
+mfa_test_addr: redis:6379
+mfa_test_port: 9000
+mfa_test_mode: grpc
+mfa_test_timeout: 10s
+mfa_test_ttl:
+  provider1: 1h
+mfa_test_providers:
+  - ProviderID: provider1
+    LockoutThreshold: 5
+`)
+
+	cfg := &testConfig{}
+	assert.NoError(suite.T(), LoadConfig(cfg, path, nil))
+	assert.Equal(suite.T(), "redis:6379", cfg.Addr)
+	assert.Equal(suite.T(), 9000, cfg.Port)
+	assert.Equal(suite.T(), "grpc", cfg.Mode)
+	assert.Equal(suite.T(), 1.0, cfg.Ratio)
+	assert.Equal(suite.T(), 10*time.Second, cfg.Timeout)
+	assert.Equal(suite.T(), map[string]time.Duration{"provider1": time.Hour}, cfg.TTL)
+	assert.Len(suite.T(), cfg.Providers, 1)
+	assert.Equal(suite.T(), int32(5), cfg.Providers[0].LockoutThreshold)
+
+	_ = os.Setenv("MFA_TEST_PORT", "9001")
+	_ = os.Setenv("MFA_TEST_TTL", "provider1:2h,provider2:3h")
+	defer os.Unsetenv("MFA_TEST_PORT")
+	defer os.Unsetenv("MFA_TEST_TTL")
+
+	args := []string{"--registry", "mdns", "--mfa_test_port=9002", "--mfa_test_insecure", "-mfa_test_mode", "micro"}
+	assert.NoError(suite.T(), LoadConfig(cfg, path, args))
+	assert.Equal(suite.T(), 9002, cfg.Port)
+	assert.Equal(suite.T(), "micro", cfg.Mode)
+	assert.True(suite.T(), cfg.Insecure)
+	assert.Equal(suite.T(), map[string]time.Duration{"provider1": 2 * time.Hour, "provider2": 3 * time.Hour}, cfg.TTL)
+	assert.Equal(suite.T(), "redis:6379", cfg.Addr)
+}
+
+func (suite *ServiceTestSuite) TestLoadConfigToReadJSONFile() {
+	path := suite.writeConfigFile("config.json", `{"mfa_test_addr": "redis:6379", "mfa_test_providers": [{"ProviderID": "provider1", "TotpDigits": 8}]}`)
+
+	cfg := &testConfig{}
+	assert.NoError(suite.T(), LoadConfig(cfg, path, nil))
+	assert.Equal(suite.T(), "redis:6379", cfg.Addr)
+	assert.Equal(suite.T(), int32(8), cfg.Providers[0].TotpDigits)
+	assert.Equal(suite.T(), "config.json", filepath.Base(ConfigFile([]string{"--config", path})))
+}
+
+func (suite *ServiceTestSuite) TestLoadConfigToValidateSchema() {
+	tests := map[string]string{
+		"unknown key":    "mfa_test_addr: redis:6379\nmfa_test_unknown: 1\n",
+		"wrong type":     "mfa_test_addr: redis:6379\nmfa_test_port: http\n",
+		"not allowed":    "mfa_test_addr: redis:6379\nmfa_test_mode: rest\n",
+		"missing":        "mfa_test_port: 9000\n",
+		"wrong duration": "mfa_test_addr: redis:6379\nmfa_test_timeout: 10\n",
+	}
+
+	for name, content := range tests {
+		err := LoadConfig(&testConfig{}, suite.writeConfigFile("config.yaml", content), nil)
+		assert.Error(suite.T(), err, name)
+	}
+
+	assert.Error(suite.T(), LoadConfig(testConfig{}, "", nil))
+}
+
+func (suite *ServiceTestSuite) TestReloadConfigToApplyOnlyReloadableKeys() {
+	current := &testConfig{Addr: "redis:6379", Port: 8081, Timeout: time.Second}
+	next := &testConfig{Addr: "redis:6380", Port: 8081, Timeout: 2 * time.Second}
+
+	reloaded, restart, err := ReloadConfig(current, next)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"mfa_test_timeout"}, reloaded)
+	assert.Equal(suite.T(), []string{"mfa_test_addr"}, restart)
+	assert.Equal(suite.T(), 2*time.Second, current.Timeout)
+	assert.Equal(suite.T(), "redis:6379", current.Addr)
+}
+
+func (suite *ServiceTestSuite) TestWatchConfigToReloadOnChange() {
+	path := suite.writeConfigFile("config.yaml", "mfa_test_timeout: 1s\n")
+
+	reloads := make(chan struct{}, 10)
+	results := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchConfig(ctx, func() []string { return []string{path} }, 5*time.Millisecond, func() ([]string, error) {
+		reloads <- struct{}{}
+		return []string{"mfa_test_timeout"}, <-results
+	}, zap.NewNop())
+
+	select {
+	case <-reloads:
+		suite.T().Fatal("Config reloaded without a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	success := testutil.ToFloat64(configReloads.WithLabelValues(metricResultSuccess))
+	results <- nil
+	assert.NoError(suite.T(), ioutil.WriteFile(path, []byte("mfa_test_timeout: 2s\n"), 0600))
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		suite.T().Fatal("Config not reloaded after a change")
+	}
+	assert.Eventually(suite.T(), func() bool {
+		return testutil.ToFloat64(configReloads.WithLabelValues(metricResultSuccess)) == success+1
+	}, time.Second, 5*time.Millisecond)
+
+	failures := testutil.ToFloat64(configReloads.WithLabelValues(metricResultError))
+	results <- errors.New("invalid config")
+	assert.NoError(suite.T(), ioutil.WriteFile(path, []byte("mfa_test_timeout: 3s\n"), 0600))
+	<-reloads
+	assert.Eventually(suite.T(), func() bool {
+		return testutil.ToFloat64(configReloads.WithLabelValues(metricResultError)) == failures+1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (suite *ServiceTestSuite) TestWatchConfigToReloadOnProvidersFileChange() {
+	path := suite.writeConfigFile("config.yaml", "mfa_test_timeout: 1s\n")
+	providers := suite.writeConfigFile("providers.json", "[]")
+
+	reloads := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchConfig(ctx, func() []string { return []string{path, providers} }, 5*time.Millisecond, func() ([]string, error) {
+		reloads <- struct{}{}
+		return nil, nil
+	}, zap.NewNop())
+
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(suite.T(), ioutil.WriteFile(providers, []byte(`[{"ProviderID": "provider1"}]`), 0600))
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		suite.T().Fatal("Config not reloaded after a change of the providers file")
+	}
+}
+
+func (suite *ServiceTestSuite) TestServiceReloadToReplaceProviderConfigs() {
+	config, err := suite.service.providerConfig(suite.ProviderID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int32(0), config.LockoutThreshold)
+
+	suite.service.Reload(ProviderConfigs(&proto.ProviderConfig{ProviderID: suite.ProviderID, LockoutThreshold: 3}))
+
+	config, err = suite.service.providerConfig(suite.ProviderID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int32(3), config.LockoutThreshold)
+}
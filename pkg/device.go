@@ -0,0 +1,480 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+const (
+	mfaDeviceStoragePattern = "mfa_device_%s_%s"
+	defaultDeviceName       = "default"
+	legacyDeviceID          = "legacy"
+	deviceIDSize            = 8
+
+	ErrorDeviceNotExists = "Device not exists"
+)
+
+// device is an authenticator enrolled for a user and provider, each device
+// has its own TOTP secret.
+type device struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Secret      string `json:"secret"`
+	CreatedAt   int64  `json:"created_at"`
+	ConfirmedAt int64  `json:"confirmed_at,omitempty"`
+	// TOTP parameters of the provider at enrollment, empty for devices enrolled with the defaults.
+	Digits    int    `json:"digits,omitempty"`
+	Period    int    `json:"period,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	// Drift is the clock drift of the authenticator in time steps, the validation window is centered on it.
+	Drift int `json:"drift,omitempty"`
+	// Rotation is the new secret issued for the device while it is not confirmed.
+	Rotation *secretRotation `json:"rotation,omitempty"`
+}
+
+func (s *service) ListDevices(ctx context.Context, req *proto.MfaListDevicesDataRequest, res *proto.MfaListDevicesDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate list devices request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	devices, err := s.loadDevices(req.UserID, req.ProviderID)
+	if err != nil {
+		s.logger.Error("Getting devices from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	for _, d := range devices {
+		res.Devices = append(res.Devices, &proto.Device{
+			ID:        d.ID,
+			Name:      d.Name,
+			CreatedAt: d.CreatedAt,
+			Drift:     int32(d.Drift),
+		})
+	}
+
+	return nil
+}
+
+func (s *service) RenameDevice(ctx context.Context, req *proto.MfaRenameDeviceDataRequest, res *proto.MfaRenameDeviceDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateRenameDeviceRequest(req); err != nil {
+		s.logger.Error("Validate rename device request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	key := s.GetDeviceStorageKey(req.UserID, req.ProviderID)
+	err := s.redis.Watch(func(tx *redis.Tx) error {
+		data, err := tx.HGet(key, req.DeviceID).Bytes()
+		if err != nil {
+			return err
+		}
+
+		d := &device{}
+		if err = json.Unmarshal(data, d); err != nil {
+			return err
+		}
+
+		d.Name = req.Name
+		if data, err = json.Marshal(d); err != nil {
+			return err
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HSet(key, d.ID, data)
+			return nil
+		})
+		return err
+	}, key)
+
+	if err == redis.Nil {
+		res.Error = &proto.Error{
+			Message: ErrorDeviceNotExists,
+		}
+		return nil
+	}
+
+	if err != nil {
+		s.logger.Error("Rename device in Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	res.Result = true
+
+	return nil
+}
+
+func (s *service) RemoveDevice(ctx context.Context, req *proto.MfaRemoveDeviceDataRequest, res *proto.MfaRemoveDeviceDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateRemoveDeviceRequest(req); err != nil {
+		s.logger.Error("Validate remove device request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	// Make sure a legacy secret is migrated, otherwise it can not be removed by its device id.
+	if _, err := s.loadDevices(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Getting devices from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	key := s.GetDeviceStorageKey(req.UserID, req.ProviderID)
+	removed, err := s.redis.HDel(key, req.DeviceID).Result()
+	if err != nil {
+		s.logger.Error("Remove device from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if removed == 0 {
+		res.Error = &proto.Error{
+			Message: ErrorDeviceNotExists,
+		}
+		return nil
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentRemoved,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     MethodTotp,
+		DeviceID:   req.DeviceID,
+	})
+
+	// Recovery codes are useless once the last device and YubiKey are gone.
+	var devices, yubiKeys *redis.IntCmd
+	_, err = s.redis.Pipelined(func(pipe redis.Pipeliner) error {
+		devices = pipe.HLen(key)
+		yubiKeys = pipe.HLen(s.GetYubiKeyStorageKey(req.UserID, req.ProviderID))
+		return nil
+	})
+	left := devices.Val() + yubiKeys.Val()
+	if err == nil && left == 0 {
+		err = s.redis.Del(s.GetRecoveryStorageKey(req.UserID, req.ProviderID)).Err()
+	}
+	if err != nil {
+		s.logger.Error("Remove recovery codes from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	// Remembered devices must not skip a second factor the user no longer has.
+	enrolled, err := s.hasEnrollment(req.UserID, req.ProviderID)
+	if err == nil && !enrolled {
+		err = s.revokeTrustedDevices(req.UserID, req.ProviderID, "enrollment removed")
+	}
+	if err != nil {
+		s.logger.Error("Revoke trusted devices in Redis failed with error", zap.Error(err))
+
+		return err
+	}
+	if left == 0 {
+		s.trackRecoveryCodes(req.UserID, req.ProviderID)
+	}
+
+	res.Result = true
+
+	return nil
+}
+
+func (s *service) addDevice(userId string, providerId string, d *device) (*device, error) {
+	id, err := newDeviceID()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Name == "" {
+		d.Name = defaultDeviceName
+	}
+	d.ID = id
+	d.CreatedAt = time.Now().Unix()
+
+	if err = s.saveDevice(userId, providerId, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// validate checks the TOTP code with the parameters the device was enrolled with
+// and returns the time step offset the code matched.
+func (d *device) validate(code string, config *proto.ProviderConfig, now time.Time) (bool, int) {
+	return validateTotp(code, d.Secret, d.Digits, d.Period, d.Algorithm, config.TotpSkew, clampDrift(d.Drift, config), now)
+}
+
+// trackDrift moves the drift estimate of the device to the matched offset and
+// reports whether it changed.
+func (d *device) trackDrift(offset int, config *proto.ProviderConfig) bool {
+	offset = clampDrift(offset, config)
+	changed := d.Drift != offset
+	d.Drift = offset
+
+	return changed
+}
+
+// clampDrift bounds the drift by the maximum drift of the provider, a negative
+// maximum disables drift tracking.
+func clampDrift(drift int, config *proto.ProviderConfig) int {
+	bound := int(config.TotpMaxDrift)
+	if bound < 0 {
+		return 0
+	}
+	if drift > bound {
+		return bound
+	}
+	if drift < -bound {
+		return -bound
+	}
+	return drift
+}
+
+// validateTotp checks the code within skew steps around the drift at now, the
+// steps closest to the drift first. Zero parameters fall back to the defaults,
+// a negative skew accepts the step of the drift only.
+func validateTotp(code string, secret string, digits int, period int, algorithm string, skew int32, drift int, now time.Time) (bool, int) {
+	if skew < 0 {
+		skew = 0
+	}
+	if digits == 0 {
+		digits = defaultTotpDigits
+	}
+	if period == 0 {
+		period = defaultTotpPeriod
+	}
+	if algorithm == "" {
+		algorithm = defaultTotpAlgorithm
+	}
+
+	if len(code) != digits {
+		return false, 0
+	}
+
+	opts := totp.ValidateOpts{
+		Period:    uint(period),
+		Digits:    otp.Digits(digits),
+		Algorithm: totpAlgorithms[algorithm],
+	}
+	now = now.UTC()
+	for i := 0; i <= int(skew); i++ {
+		for _, offset := range []int{drift - i, drift + i} {
+			expected, err := totp.GenerateCodeCustom(secret, now.Add(time.Duration(offset*period)*time.Second), opts)
+			if err != nil {
+				return false, 0
+			}
+			if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+				return true, offset
+			}
+			if i == 0 {
+				break
+			}
+		}
+	}
+	return false, 0
+}
+
+func newDeviceID() (string, error) {
+	id := make([]byte, deviceIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func (s *service) saveDevice(userId string, providerId string, d *device) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(s.GetUserProvidersStorageKey(userId), providerId)
+		pipe.HSet(s.GetDeviceStorageKey(userId, providerId), d.ID, data)
+		return nil
+	})
+
+	return err
+}
+
+// updateDevice applies the update to the stored device and saves it when the
+// update reports a change, so a stale copy never overwrites the stored one.
+// Nothing is written when the device is removed, a concurrent change fails
+// with redis.TxFailedErr.
+func (s *service) updateDevice(userId string, providerId string, deviceId string, update func(d *device) bool) (bool, error) {
+	key := s.GetDeviceStorageKey(userId, providerId)
+	updated := false
+	err := s.redis.Watch(func(tx *redis.Tx) error {
+		data, err := tx.HGet(key, deviceId).Bytes()
+		if err != nil {
+			return err
+		}
+
+		d := &device{}
+		if err = json.Unmarshal(data, d); err != nil {
+			return err
+		}
+		if !update(d) {
+			return nil
+		}
+		if data, err = json.Marshal(d); err != nil {
+			return err
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HSet(key, d.ID, data)
+			return nil
+		})
+		updated = err == nil
+		return err
+	}, key)
+
+	if err == redis.Nil {
+		return false, nil
+	}
+
+	return updated, err
+}
+
+// confirmDevice marks the device as confirmed after the first valid code.
+func (s *service) confirmDevice(userId string, providerId string, d *device) {
+	now := time.Now().Unix()
+	confirmed, err := s.updateDevice(userId, providerId, d.ID, func(stored *device) bool {
+		if stored.ConfirmedAt != 0 {
+			return false
+		}
+		stored.ConfirmedAt = now
+		return true
+	})
+	if err != nil {
+		s.logger.Error("Confirm device in Redis failed with error", zap.Error(err))
+
+		return
+	}
+	if !confirmed {
+		return
+	}
+	d.ConfirmedAt = now
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentConfirmed,
+		UserID:     userId,
+		ProviderID: providerId,
+		Method:     MethodTotp,
+		DeviceID:   d.ID,
+	})
+}
+
+// loadDevices returns the devices of the user ordered by creation time. A secret
+// stored by a previous version of the service is moved to the device storage
+// as a device with the legacy id.
+func (s *service) loadDevices(userId string, providerId string) ([]*device, error) {
+	key := s.GetDeviceStorageKey(userId, providerId)
+
+	secret, err := s.redis.HGet(s.GetSecretStorageKey(userId), providerId).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	if err == nil {
+		now := time.Now().Unix()
+		data, err := json.Marshal(&device{
+			ID:          legacyDeviceID,
+			Name:        defaultDeviceName,
+			Secret:      secret,
+			CreatedAt:   now,
+			ConfirmedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.SAdd(s.GetUserProvidersStorageKey(userId), providerId)
+			pipe.HSetNX(key, legacyDeviceID, data)
+			pipe.HDel(s.GetSecretStorageKey(userId), providerId)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := s.redis.HGetAll(key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]*device, 0, len(values))
+	for _, data := range values {
+		d := &device{}
+		if err := json.Unmarshal([]byte(data), d); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].CreatedAt != devices[j].CreatedAt {
+			return devices[i].CreatedAt < devices[j].CreatedAt
+		}
+		return devices[i].ID < devices[j].ID
+	})
+
+	return devices, nil
+}
+
+func (s *service) validateUserProvider(userId string, providerId string) error {
+	if providerId == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "ProviderID")
+	}
+	if userId == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "UserID")
+	}
+	return nil
+}
+
+func (s *service) validateRenameDeviceRequest(req *proto.MfaRenameDeviceDataRequest) error {
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		return err
+	}
+	if req.DeviceID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "DeviceID")
+	}
+	if req.Name == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "Name")
+	}
+	return nil
+}
+
+func (s *service) validateRemoveDeviceRequest(req *proto.MfaRemoveDeviceDataRequest) error {
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		return err
+	}
+	if req.DeviceID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "DeviceID")
+	}
+	return nil
+}
+
+func (s *service) GetDeviceStorageKey(userId string, providerId string) string {
+	return fmt.Sprintf(mfaDeviceStoragePattern, userId, providerId)
+}
@@ -0,0 +1,283 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestCreateToAddDeviceWithoutOverwriting() {
+	res1 := suite.createDevice("work phone")
+	res2 := suite.createDevice("backup tablet")
+
+	assert.NotEqual(suite.T(), res1.DeviceID, res2.DeviceID)
+	assert.NotEqual(suite.T(), res1.SecretKey, res2.SecretKey)
+	assert.Equal(suite.T(), 10, len(res1.RecoveryCode))
+	assert.Empty(suite.T(), res2.RecoveryCode)
+
+	res := &proto.MfaListDevicesDataResponse{}
+	err := suite.service.ListDevices(context.TODO(), &proto.MfaListDevicesDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(res.Devices))
+	assert.ElementsMatch(
+		suite.T(),
+		[]string{"work phone", "backup tablet"},
+		[]string{res.Devices[0].Name, res.Devices[1].Name},
+	)
+}
+
+func (suite *ServiceTestSuite) TestCheckToReturnMatchedDevice() {
+	suite.createDevice("work phone")
+	res1 := suite.createDevice("backup tablet")
+	code, _ := totp.GenerateCode(res1.SecretKey, time.Now())
+
+	res := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}
+	err := suite.service.Check(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+	assert.Nil(suite.T(), res.Error)
+	assert.Equal(suite.T(), res1.DeviceID, res.DeviceID)
+	assert.Equal(suite.T(), "backup tablet", res.DeviceName)
+}
+
+func (suite *ServiceTestSuite) TestDeviceRequestsToReturnErrorRequestData() {
+	err := suite.service.ListDevices(context.TODO(), &proto.MfaListDevicesDataRequest{UserID: "1"}, &proto.MfaListDevicesDataResponse{})
+	assert.Regexp(suite.T(), regexp.MustCompile("is required field"), err)
+
+	renames := []proto.MfaRenameDeviceDataRequest{
+		{ProviderID: "1", UserID: "", DeviceID: "1", Name: "test"},
+		{ProviderID: "1", UserID: "1", DeviceID: "", Name: "test"},
+		{ProviderID: "1", UserID: "1", DeviceID: "1", Name: ""},
+	}
+	for _, req := range renames {
+		err := suite.service.RenameDevice(context.TODO(), &req, &proto.MfaRenameDeviceDataResponse{})
+		assert.Regexp(suite.T(), regexp.MustCompile("is required field"), err)
+	}
+
+	err = suite.service.RemoveDevice(context.TODO(), &proto.MfaRemoveDeviceDataRequest{ProviderID: "1", UserID: "1"}, &proto.MfaRemoveDeviceDataResponse{})
+	assert.Regexp(suite.T(), regexp.MustCompile("is required field"), err)
+}
+
+func (suite *ServiceTestSuite) TestRenameDeviceToReturnSuccessResponse() {
+	res1 := suite.createDevice("")
+
+	res := &proto.MfaRenameDeviceDataResponse{}
+	req := &proto.MfaRenameDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: res1.DeviceID, Name: "work phone"}
+	err := suite.service.RenameDevice(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+
+	list := &proto.MfaListDevicesDataResponse{}
+	_ = suite.service.ListDevices(context.TODO(), &proto.MfaListDevicesDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, list)
+	assert.Equal(suite.T(), "work phone", list.Devices[0].Name)
+}
+
+func (suite *ServiceTestSuite) TestRenameDeviceToReturnErrorDeviceNotExists() {
+	res := &proto.MfaRenameDeviceDataResponse{}
+	req := &proto.MfaRenameDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: "unknown", Name: "test"}
+	err := suite.service.RenameDevice(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorDeviceNotExists, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestRemoveDeviceToStopAcceptingItsCodes() {
+	res1 := suite.createDevice("work phone")
+	res2 := suite.createDevice("backup tablet")
+
+	res := &proto.MfaRemoveDeviceDataResponse{}
+	req := &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: res1.DeviceID}
+	err := suite.service.RemoveDevice(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+
+	code, _ := totp.GenerateCode(res1.SecretKey, time.Now())
+	check := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, check)
+	assert.False(suite.T(), check.Result)
+
+	code, _ = totp.GenerateCode(res2.SecretKey, time.Now())
+	check = &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, check)
+	assert.True(suite.T(), check.Result)
+}
+
+func (suite *ServiceTestSuite) TestRemoveLastDeviceToRemoveRecoveryCodes() {
+	res1 := suite.createDevice("")
+
+	res := &proto.MfaRemoveDeviceDataResponse{}
+	req := &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: res1.DeviceID}
+	_ = suite.service.RemoveDevice(context.TODO(), req, res)
+	assert.True(suite.T(), res.Result)
+
+	exists, _ := suite.redis.Exists(suite.service.GetRecoveryStorageKey(suite.userID, suite.ProviderID)).Result()
+	assert.Equal(suite.T(), int64(0), exists)
+
+	res = &proto.MfaRemoveDeviceDataResponse{}
+	_ = suite.service.RemoveDevice(context.TODO(), req, res)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorDeviceNotExists, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestRemoveLastDeviceToKeepRecoveryCodesWithYubiKey() {
+	res1 := suite.createDevice("")
+	suite.addYubiKey()
+
+	res := &proto.MfaRemoveDeviceDataResponse{}
+	req := &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: res1.DeviceID}
+	_ = suite.service.RemoveDevice(context.TODO(), req, res)
+	assert.True(suite.T(), res.Result)
+
+	exists, _ := suite.redis.Exists(suite.service.GetRecoveryStorageKey(suite.userID, suite.ProviderID)).Result()
+	assert.Equal(suite.T(), int64(1), exists)
+}
+
+func (suite *ServiceTestSuite) TestConfirmDeviceToNotRestoreRemovedDevice() {
+	res1 := suite.createDevice("")
+	devices, _ := suite.service.loadDevices(suite.userID, suite.ProviderID)
+
+	req := &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: res1.DeviceID}
+	_ = suite.service.RemoveDevice(context.TODO(), req, &proto.MfaRemoveDeviceDataResponse{})
+	suite.service.confirmDevice(suite.userID, suite.ProviderID, devices[0])
+
+	exists, _ := suite.redis.HExists(suite.service.GetDeviceStorageKey(suite.userID, suite.ProviderID), res1.DeviceID).Result()
+	assert.False(suite.T(), exists)
+}
+
+func (suite *ServiceTestSuite) TestCheckToAcceptLegacySecret() {
+	key, _ := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: suite.userID})
+	suite.redis.HSet(suite.service.GetSecretStorageKey(suite.userID), suite.ProviderID, key.Secret())
+	code, _ := totp.GenerateCode(key.Secret(), time.Now())
+
+	res := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}
+	err := suite.service.Check(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+	assert.Equal(suite.T(), legacyDeviceID, res.DeviceID)
+
+	exists, _ := suite.redis.HExists(suite.service.GetSecretStorageKey(suite.userID), suite.ProviderID).Result()
+	assert.False(suite.T(), exists)
+}
+
+func (suite *ServiceTestSuite) createDevice(name string) *proto.MfaCreateDataResponse {
+	res := &proto.MfaCreateDataResponse{}
+	req := &proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, AppName: "test", UserID: suite.userID, DeviceName: name}
+	err := suite.service.Create(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+// driftTestTime is the middle of a time step, codes of the steps around it are validated at it.
+var driftTestTime = time.Unix(1700000025, 0)
+
+// checkCodeAt validates a code of the time step steps away from driftTestTime.
+func (suite *ServiceTestSuite) checkCodeAt(secret string, steps int) bool {
+	code, _ := totp.GenerateCode(secret, driftTestTime.Add(time.Duration(steps*defaultTotpPeriod)*time.Second))
+
+	config, err := suite.service.providerConfig(suite.ProviderID)
+	assert.NoError(suite.T(), err)
+	devices, err := suite.service.loadDevices(suite.userID, suite.ProviderID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), devices, 1)
+
+	return suite.service.validateDevice(suite.userID, suite.ProviderID, devices[0], code, config, driftTestTime)
+}
+
+func (suite *ServiceTestSuite) deviceDrift() int32 {
+	res := &proto.MfaListDevicesDataResponse{}
+	err := suite.service.ListDevices(context.TODO(), &proto.MfaListDevicesDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, res)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), res.Devices, 1)
+
+	return res.Devices[0].Drift
+}
+
+func (suite *ServiceTestSuite) TestValidateDeviceToCenterWindowOnDrift() {
+	device := suite.createDevice("")
+
+	assert.False(suite.T(), suite.checkCodeAt(device.SecretKey, 2))
+	assert.True(suite.T(), suite.checkCodeAt(device.SecretKey, 1))
+	assert.Equal(suite.T(), int32(1), suite.deviceDrift())
+
+	assert.True(suite.T(), suite.checkCodeAt(device.SecretKey, 2))
+	assert.Equal(suite.T(), int32(2), suite.deviceDrift())
+	assert.False(suite.T(), suite.checkCodeAt(device.SecretKey, 0))
+
+	assert.True(suite.T(), suite.checkCodeAt(device.SecretKey, 1))
+	assert.Equal(suite.T(), int32(1), suite.deviceDrift())
+}
+
+func (suite *ServiceTestSuite) TestValidateDeviceToBoundDrift() {
+	suite.setProviderConfig(&proto.ProviderConfig{TotpMaxDrift: 1})
+	device := suite.createDevice("")
+
+	assert.True(suite.T(), suite.checkCodeAt(device.SecretKey, 1))
+	assert.True(suite.T(), suite.checkCodeAt(device.SecretKey, 2))
+	assert.Equal(suite.T(), int32(1), suite.deviceDrift())
+	assert.False(suite.T(), suite.checkCodeAt(device.SecretKey, 3))
+}
+
+func (suite *ServiceTestSuite) TestValidateDeviceToNotTrackDriftWhenDisabled() {
+	suite.setProviderConfig(&proto.ProviderConfig{TotpMaxDrift: TotpDriftDisabled})
+	device := suite.createDevice("")
+
+	config, err := suite.service.providerConfig(suite.ProviderID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int32(TotpDriftDisabled), config.TotpMaxDrift)
+
+	assert.True(suite.T(), suite.checkCodeAt(device.SecretKey, 1))
+	assert.Equal(suite.T(), int32(0), suite.deviceDrift())
+	assert.False(suite.T(), suite.checkCodeAt(device.SecretKey, 2))
+}
+
+func (suite *ServiceTestSuite) TestValidateDeviceToAcceptCurrentStepOnlyWithoutSkew() {
+	suite.setProviderConfig(&proto.ProviderConfig{TotpSkew: TotpSkewNone})
+	device := suite.createDevice("")
+
+	assert.False(suite.T(), suite.checkCodeAt(device.SecretKey, 1))
+	assert.False(suite.T(), suite.checkCodeAt(device.SecretKey, -1))
+	assert.True(suite.T(), suite.checkCodeAt(device.SecretKey, 0))
+}
+
+func (suite *ServiceTestSuite) TestValidateDeviceToSaveDriftOnly() {
+	device := suite.createDevice("")
+	devices, _ := suite.service.loadDevices(suite.userID, suite.ProviderID)
+	config, _ := suite.service.providerConfig(suite.ProviderID)
+
+	rename := &proto.MfaRenameDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: device.DeviceID, Name: "renamed"}
+	_ = suite.service.RenameDevice(context.TODO(), rename, &proto.MfaRenameDeviceDataResponse{})
+
+	code, _ := totp.GenerateCode(device.SecretKey, driftTestTime.Add(defaultTotpPeriod*time.Second))
+	assert.True(suite.T(), suite.service.validateDevice(suite.userID, suite.ProviderID, devices[0], code, config, driftTestTime))
+
+	devices, _ = suite.service.loadDevices(suite.userID, suite.ProviderID)
+	assert.Equal(suite.T(), "renamed", devices[0].Name)
+	assert.Equal(suite.T(), 1, devices[0].Drift)
+}
+
+func (suite *ServiceTestSuite) TestValidateDeviceToNotRestoreRemovedDevice() {
+	device := suite.createDevice("")
+	devices, _ := suite.service.loadDevices(suite.userID, suite.ProviderID)
+	config, _ := suite.service.providerConfig(suite.ProviderID)
+
+	req := &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: device.DeviceID}
+	_ = suite.service.RemoveDevice(context.TODO(), req, &proto.MfaRemoveDeviceDataResponse{})
+
+	code, _ := totp.GenerateCode(device.SecretKey, driftTestTime.Add(defaultTotpPeriod*time.Second))
+	suite.service.validateDevice(suite.userID, suite.ProviderID, devices[0], code, config, driftTestTime)
+
+	exists, _ := suite.redis.HExists(suite.service.GetDeviceStorageKey(suite.userID, suite.ProviderID), device.DeviceID).Result()
+	assert.False(suite.T(), exists)
+}
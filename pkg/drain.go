@@ -0,0 +1,152 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	microErrors "github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"sync"
+)
+
+const ErrorShuttingDown = "Service is shutting down"
+
+var errShuttingDown = errors.New(ErrorShuttingDown)
+
+// Drainer tracks the calls in flight so they can finish before the service
+// stops. Calls started after Close are rejected as unavailable.
+type Drainer struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
+}
+
+func NewDrainer() *Drainer {
+	return &Drainer{}
+}
+
+func (d *Drainer) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return false
+	}
+	d.inFlight.Add(1)
+
+	return true
+}
+
+func (d *Drainer) end() {
+	d.inFlight.Done()
+}
+
+// Close stops accepting calls.
+func (d *Drainer) Close() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+}
+
+// Closed reports whether the drainer stopped accepting calls.
+func (d *Drainer) Closed() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closed
+}
+
+// Wait blocks until the calls in flight are finished or the context is done,
+// it is called after Close.
+func (d *Drainer) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Do runs fn as a call, it fails when the drainer is closed.
+func (d *Drainer) Do(fn func() error) error {
+	if !d.begin() {
+		return errShuttingDown
+	}
+	defer d.end()
+
+	return fn()
+}
+
+// HandlerWrapper tracks the calls of the micro server.
+func (d *Drainer) HandlerWrapper() server.HandlerWrapper {
+	return func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if !d.begin() {
+				return microErrors.New(ServiceName, ErrorShuttingDown, http.StatusServiceUnavailable)
+			}
+			defer d.end()
+
+			return fn(ctx, req, rsp)
+		}
+	}
+}
+
+// SubscriberWrapper tracks the messages handled by the subscribers of the micro server.
+func (d *Drainer) SubscriberWrapper() server.SubscriberWrapper {
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			return d.Do(func() error {
+				return fn(ctx, msg)
+			})
+		}
+	}
+}
+
+// UnaryInterceptor tracks the unary calls of the gRPC server.
+func (d *Drainer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !d.begin() {
+			return nil, status.Error(codes.Unavailable, ErrorShuttingDown)
+		}
+		defer d.end()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor tracks the streams of the gRPC server.
+func (d *Drainer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !d.begin() {
+			return status.Error(codes.Unavailable, ErrorShuttingDown)
+		}
+		defer d.end()
+
+		return handler(srv, ss)
+	}
+}
+
+// Handler tracks the requests of the REST API.
+func (d *Drainer) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !d.begin() {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(gatewayError{Error: &proto.Error{Message: ErrorShuttingDown}})
+			return
+		}
+		defer d.end()
+
+		h.ServeHTTP(w, r)
+	})
+}
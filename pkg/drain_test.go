@@ -0,0 +1,62 @@
+package mfa
+
+import (
+	"context"
+	microErrors "github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/server"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestDrainerToWaitForCallsInFlight() {
+	drainer := NewDrainer()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := drainer.HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		close(started)
+		<-release
+		return nil
+	})
+	go func() {
+		_ = fn(context.TODO(), &testRequest{method: "MfaService.Check"}, nil)
+	}()
+	<-started
+
+	drainer.Close()
+	assert.True(suite.T(), drainer.Closed())
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(suite.T(), context.DeadlineExceeded, drainer.Wait(ctx))
+
+	close(release)
+	assert.NoError(suite.T(), drainer.Wait(context.TODO()))
+}
+
+func (suite *ServiceTestSuite) TestDrainerToRejectCallsAfterClose() {
+	drainer := NewDrainer()
+	drainer.Close()
+
+	err := drainer.HandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return nil
+	})(context.TODO(), &testRequest{method: "MfaService.Check"}, nil)
+	assert.Equal(suite.T(), int32(http.StatusServiceUnavailable), microErrors.Parse(err.Error()).Code)
+
+	_, err = drainer.UnaryInterceptor()(context.TODO(), nil, &grpc.UnaryServerInfo{FullMethod: grpcServicePath + "Check"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	assert.Equal(suite.T(), codes.Unavailable, status.Code(err))
+
+	assert.Equal(suite.T(), errShuttingDown, drainer.Do(func() error { return nil }))
+
+	rec := httptest.NewRecorder()
+	drainer.Handler(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, GatewayPrefix+"check", nil))
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(suite.T(), rec.Body.String(), ErrorShuttingDown)
+}
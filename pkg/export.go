@@ -0,0 +1,461 @@
+package mfa
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictMerge     = "merge"
+
+	RestoreStatusRestored = "restored"
+	RestoreStatusSkipped  = "skipped"
+	RestoreStatusFailed   = "failed"
+
+	maxRestoreEnrollments = 1000
+)
+
+// enrollmentStoragePatterns are the storage keys holding the factors of an
+// enrollment, other keys of a user only hold transient state.
+var enrollmentStoragePatterns = []string{
+	mfaDeviceStoragePattern,
+	mfaYubiKeyStoragePattern,
+	mfaRecoveryStoragePattern,
+}
+
+type enrollmentOwner struct {
+	userId     string
+	providerId string
+}
+
+// ExportEnrollments streams an archive of the devices, YubiKeys and recovery
+// codes of all users, or of the users of one provider, encrypted with the
+// passphrase or for the public key of the request, so secrets never leave the
+// service in plain text. Secrets of pending rotations are not exported.
+// Without ProviderID storage keys are split at the last "_", so provider ids
+// containing it are not recognized.
+func (s *service) ExportEnrollments(ctx context.Context, req *proto.MfaExportEnrollmentsDataRequest, stream proto.MfaService_ExportEnrollmentsStream) error {
+	s = s.withContext(ctx)
+
+	key, err := s.validateExportEnrollmentsRequest(req)
+	if err != nil {
+		s.logger.Error("Validate export enrollments request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	archive, err := NewArchiveWriter(&archiveChunkWriter{stream: stream}, key, req.ProviderID)
+	if err != nil {
+		s.logger.Error("Create enrollment archive failed with error", zap.Error(err))
+
+		return err
+	}
+
+	owners, err := s.enrollmentOwners(req.ProviderID)
+	if err != nil {
+		s.logger.Error("Scanning enrollments in Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	var exported int
+	for _, owner := range owners {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		e, err := s.loadEnrollment(owner.userId, owner.providerId)
+		if err != nil {
+			s.logger.Error(
+				"Getting enrollment from Redis failed with error",
+				zap.Error(err),
+				zap.String("userId", owner.userId),
+				zap.String("providerId", owner.providerId),
+			)
+
+			return err
+		}
+		if len(e.Devices) == 0 && len(e.YubiKeys) == 0 && len(e.RecoveryCodes) == 0 {
+			// Removed since the scan.
+			continue
+		}
+
+		if err = archive.Write(e); err != nil {
+			s.logger.Error("Sending enrollment archive failed with error", zap.Error(err))
+
+			return err
+		}
+		exported++
+	}
+
+	if err = archive.Close(); err != nil {
+		s.logger.Error("Sending enrollment archive failed with error", zap.Error(err))
+
+		return err
+	}
+
+	s.logger.Warn(
+		"Enrollments exported",
+		zap.String("providerId", req.ProviderID),
+		zap.String("actor", AuthClientID(ctx)),
+		zap.String("note", req.Actor),
+		zap.Int("count", exported),
+	)
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentsExported,
+		ProviderID: req.ProviderID,
+		Actor:      AuthClientID(ctx),
+		Note:       req.Actor,
+	})
+
+	return nil
+}
+
+// RestoreEnrollments writes exported enrollments. Users already enrolled are
+// skipped, overwritten or merged with by the conflict policy, merging keeps
+// the devices, YubiKeys and recovery codes the user already has. Restoring the
+// same enrollments again does not change them.
+func (s *service) RestoreEnrollments(ctx context.Context, req *proto.MfaRestoreEnrollmentsDataRequest, res *proto.MfaRestoreEnrollmentsDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateRestoreEnrollmentsRequest(req); err != nil {
+		s.logger.Error("Validate restore enrollments request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	conflict := req.Conflict
+	if conflict == "" {
+		conflict = ConflictSkip
+	}
+
+	for i, e := range req.Enrollments {
+		result := &proto.RestoreResult{Index: int32(i), UserID: e.UserID, ProviderID: e.ProviderID}
+
+		status, err := s.restoreEnrollment(e, conflict, AuthClientID(ctx), req.Actor)
+		if err != nil {
+			if _, ok := err.(*requestError); !ok {
+				s.logger.Error(
+					"Restore enrollment failed with error",
+					zap.Error(err),
+					zap.String("userId", e.UserID),
+					zap.String("providerId", e.ProviderID),
+				)
+			}
+
+			status = RestoreStatusFailed
+			result.Error = err.Error()
+		}
+
+		switch status {
+		case RestoreStatusRestored:
+			res.Restored++
+		case RestoreStatusSkipped:
+			res.Skipped++
+		default:
+			res.Failed++
+		}
+
+		result.Status = status
+		res.Results = append(res.Results, result)
+	}
+
+	s.logger.Info(
+		"Enrollments restored",
+		zap.String("actor", AuthClientID(ctx)),
+		zap.String("note", req.Actor),
+		zap.String("conflict", conflict),
+		zap.Int32("restored", res.Restored),
+		zap.Int32("skipped", res.Skipped),
+		zap.Int32("failed", res.Failed),
+	)
+
+	return nil
+}
+
+func (s *service) restoreEnrollment(e *proto.Enrollment, conflict string, actor string, note string) (string, error) {
+	if err := validateEnrollment(e); err != nil {
+		return "", err
+	}
+
+	enrolled, err := s.hasEnrollment(e.UserID, e.ProviderID)
+	if err != nil {
+		return "", err
+	}
+	if enrolled && conflict == ConflictSkip {
+		return RestoreStatusSkipped, nil
+	}
+
+	devices := map[string][]byte{}
+	for _, d := range e.Devices {
+		data, err := json.Marshal(&device{
+			ID:          d.ID,
+			Name:        d.Name,
+			Secret:      d.Secret,
+			CreatedAt:   d.CreatedAt,
+			ConfirmedAt: d.ConfirmedAt,
+			Digits:      int(d.Digits),
+			Period:      int(d.Period),
+			Algorithm:   d.Algorithm,
+			Drift:       int(d.Drift),
+		})
+		if err != nil {
+			return "", err
+		}
+		devices[d.ID] = data
+	}
+
+	yubiKeys := map[string][]byte{}
+	for _, yk := range e.YubiKeys {
+		data, err := json.Marshal(&yubiKey{
+			PrivateID:      yk.PrivateID,
+			AesKey:         yk.AesKey,
+			UsageCounter:   uint16(yk.UsageCounter),
+			SessionCounter: uint8(yk.SessionCounter),
+		})
+		if err != nil {
+			return "", err
+		}
+		yubiKeys[yk.PublicID] = data
+	}
+
+	codes := make([]interface{}, 0, len(e.RecoveryCodes))
+	for _, code := range e.RecoveryCodes {
+		codes = append(codes, code)
+	}
+
+	deviceKey := s.GetDeviceStorageKey(e.UserID, e.ProviderID)
+	yubiKeyKey := s.GetYubiKeyStorageKey(e.UserID, e.ProviderID)
+	recoveryKey := s.GetRecoveryStorageKey(e.UserID, e.ProviderID)
+
+	_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(s.GetUserProvidersStorageKey(e.UserID), e.ProviderID)
+		if conflict == ConflictOverwrite {
+			pipe.Del(deviceKey, yubiKeyKey, recoveryKey)
+			pipe.HDel(s.GetSecretStorageKey(e.UserID), e.ProviderID)
+		}
+		for id, data := range devices {
+			pipe.HSetNX(deviceKey, id, data)
+		}
+		for publicID, data := range yubiKeys {
+			pipe.HSetNX(yubiKeyKey, publicID, data)
+		}
+		// Codes missing from a live enrollment were consumed, merging keeps them consumed.
+		if len(codes) > 0 && !(enrolled && conflict == ConflictMerge) {
+			pipe.SAdd(recoveryKey, codes...)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentCreated,
+		UserID:     e.UserID,
+		ProviderID: e.ProviderID,
+		Actor:      actor,
+		Reason:     "restore",
+		Note:       note,
+	})
+
+	return RestoreStatusRestored, nil
+}
+
+// enrollmentOwners returns the users and providers having any factor stored.
+func (s *service) enrollmentOwners(providerId string) ([]enrollmentOwner, error) {
+	found := map[enrollmentOwner]bool{}
+
+	for _, pattern := range enrollmentStoragePatterns {
+		prefix := pattern[:strings.Index(pattern, "%s")]
+		match := fmt.Sprintf(pattern, "*", "*")
+		if providerId != "" {
+			match = fmt.Sprintf(pattern, "*", escapeGlob(providerId))
+		}
+
+		err := s.scanKeys(match, func(key string) error {
+			rest := strings.TrimPrefix(key, prefix)
+			if providerId != "" {
+				found[enrollmentOwner{strings.TrimSuffix(rest, "_"+providerId), providerId}] = true
+				return nil
+			}
+
+			if i := strings.LastIndex(rest, "_"); i > 0 {
+				found[enrollmentOwner{rest[:i], rest[i+1:]}] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Secrets stored by a previous version of the service are kept per user.
+	legacyPrefix := fmt.Sprintf(mfaSecretStoragePattern, "")
+	err := s.scanKeys(legacyPrefix+"*", func(key string) error {
+		providers, err := s.redis.HKeys(key).Result()
+		if err != nil {
+			return err
+		}
+		for _, p := range providers {
+			if providerId == "" || p == providerId {
+				found[enrollmentOwner{strings.TrimPrefix(key, legacyPrefix), p}] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make([]enrollmentOwner, 0, len(found))
+	for owner := range found {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].providerId != owners[j].providerId {
+			return owners[i].providerId < owners[j].providerId
+		}
+		return owners[i].userId < owners[j].userId
+	})
+
+	return owners, nil
+}
+
+func (s *service) scanKeys(match string, fn func(key string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := s.redis.Scan(cursor, match, userScanSize).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if err = fn(key); err != nil {
+				return err
+			}
+		}
+
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *service) loadEnrollment(userId string, providerId string) (*proto.Enrollment, error) {
+	devices, err := s.loadDevices(userId, providerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var yubiKeys *redis.StringStringMapCmd
+	var codes *redis.StringSliceCmd
+	_, err = s.redis.Pipelined(func(pipe redis.Pipeliner) error {
+		yubiKeys = pipe.HGetAll(s.GetYubiKeyStorageKey(userId, providerId))
+		codes = pipe.SMembers(s.GetRecoveryStorageKey(userId, providerId))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	e := &proto.Enrollment{UserID: userId, ProviderID: providerId, RecoveryCodes: codes.Val()}
+	sort.Strings(e.RecoveryCodes)
+
+	for _, d := range devices {
+		e.Devices = append(e.Devices, &proto.EnrollmentDevice{
+			ID:          d.ID,
+			Name:        d.Name,
+			Secret:      d.Secret,
+			CreatedAt:   d.CreatedAt,
+			ConfirmedAt: d.ConfirmedAt,
+			Digits:      int32(d.Digits),
+			Period:      int32(d.Period),
+			Algorithm:   d.Algorithm,
+			Drift:       int32(d.Drift),
+		})
+	}
+
+	for publicID, data := range yubiKeys.Val() {
+		yk := &yubiKey{}
+		if err = json.Unmarshal([]byte(data), yk); err != nil {
+			return nil, err
+		}
+		e.YubiKeys = append(e.YubiKeys, &proto.EnrollmentYubiKey{
+			PublicID:       publicID,
+			PrivateID:      yk.PrivateID,
+			AesKey:         yk.AesKey,
+			UsageCounter:   uint32(yk.UsageCounter),
+			SessionCounter: uint32(yk.SessionCounter),
+		})
+	}
+	sort.Slice(e.YubiKeys, func(i, j int) bool {
+		return e.YubiKeys[i].PublicID < e.YubiKeys[j].PublicID
+	})
+
+	return e, nil
+}
+
+func validateEnrollment(e *proto.Enrollment) error {
+	if e.UserID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "UserID")
+	}
+	if e.ProviderID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "ProviderID")
+	}
+	for _, d := range e.Devices {
+		if _, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(d.Secret, "=")); d.ID == "" || d.Secret == "" || err != nil {
+			return newRequestError(ErrorRequestPropertyFormat, "Devices")
+		}
+		if _, ok := totpAlgorithms[d.Algorithm]; d.Algorithm != "" && !ok {
+			return newRequestError(ErrorRequestPropertyFormat, "Devices")
+		}
+	}
+	for _, yk := range e.YubiKeys {
+		if yk.PublicID == "" || yk.PrivateID == "" || yk.AesKey == "" {
+			return newRequestError(ErrorRequestPropertyFormat, "YubiKeys")
+		}
+	}
+	return nil
+}
+
+// validateExportEnrollmentsRequest returns the key the archive is encrypted with.
+func (s *service) validateExportEnrollmentsRequest(req *proto.MfaExportEnrollmentsDataRequest) (ArchiveKey, error) {
+	key := ArchiveKey{Passphrase: req.Passphrase}
+	if req.PublicKey != "" {
+		publicKey, err := ParseArchivePublicKey([]byte(req.PublicKey))
+		if err != nil {
+			return key, newRequestError(ErrorRequestPropertyFormat, "PublicKey")
+		}
+		key = ArchiveKey{PublicKey: publicKey}
+	}
+	if key.Passphrase == "" && key.PublicKey == nil {
+		return key, newRequestError(ErrorRequestPropertyRequired, "Passphrase")
+	}
+	return key, nil
+}
+
+func (s *service) validateRestoreEnrollmentsRequest(req *proto.MfaRestoreEnrollmentsDataRequest) error {
+	if len(req.Enrollments) == 0 {
+		return newRequestError(ErrorRequestPropertyRequired, "Enrollments")
+	}
+	if len(req.Enrollments) > maxRestoreEnrollments {
+		return newRequestError(ErrorRequestPropertyFormat, "Enrollments")
+	}
+	switch req.Conflict {
+	case "", ConflictSkip, ConflictOverwrite, ConflictMerge:
+	default:
+		return newRequestError(ErrorRequestPropertyFormat, "Conflict")
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package mfa
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"time"
+)
+
+type testExportStream struct {
+	archive bytes.Buffer
+}
+
+func (s *testExportStream) Send(c *proto.ArchiveChunk) error {
+	s.archive.Write(c.Data)
+	return nil
+}
+
+func (s *testExportStream) SendMsg(m interface{}) error {
+	return s.Send(m.(*proto.ArchiveChunk))
+}
+
+func (s *testExportStream) RecvMsg(m interface{}) error {
+	return io.EOF
+}
+
+func (s *testExportStream) Close() error {
+	return nil
+}
+
+func (suite *ServiceTestSuite) exportEnrollments() []*proto.Enrollment {
+	stream := &testExportStream{}
+	req := &proto.MfaExportEnrollmentsDataRequest{ProviderID: suite.ProviderID, Actor: "ops", Passphrase: "secret"}
+	err := suite.service.ExportEnrollments(context.TODO(), req, stream)
+	assert.NoError(suite.T(), err)
+
+	return suite.readArchive(stream.archive.Bytes(), ArchiveKey{Passphrase: "secret"})
+}
+
+func (suite *ServiceTestSuite) readArchive(archive []byte, key ArchiveKey) []*proto.Enrollment {
+	r, err := NewArchiveReader(bytes.NewReader(archive), key)
+	assert.NoError(suite.T(), err)
+
+	var enrollments []*proto.Enrollment
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(suite.T(), err) {
+			break
+		}
+		enrollments = append(enrollments, e)
+	}
+	return enrollments
+}
+
+func (suite *ServiceTestSuite) restoreEnrollments(conflict string, enrollments ...*proto.Enrollment) *proto.MfaRestoreEnrollmentsDataResponse {
+	res := &proto.MfaRestoreEnrollmentsDataResponse{}
+	err := suite.service.RestoreEnrollments(context.TODO(), &proto.MfaRestoreEnrollmentsDataRequest{Enrollments: enrollments, Conflict: conflict, Actor: "ops"}, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+func (suite *ServiceTestSuite) TestExportEnrollmentsToRestoreDeletedEnrollment() {
+	device := suite.createDevice("phone")
+	suite.addYubiKey()
+
+	enrollments := suite.exportEnrollments()
+	assert.Equal(suite.T(), 1, len(enrollments))
+	assert.Equal(suite.T(), suite.userID, enrollments[0].UserID)
+	assert.Equal(suite.T(), device.SecretKey, enrollments[0].Devices[0].Secret)
+	assert.Equal(suite.T(), 1, len(enrollments[0].YubiKeys))
+	assert.NotEmpty(suite.T(), enrollments[0].RecoveryCodes)
+
+	_ = suite.deleteKeys()
+
+	res := suite.restoreEnrollments("", enrollments...)
+	assert.Equal(suite.T(), int32(1), res.Restored)
+	assert.Equal(suite.T(), RestoreStatusRestored, res.Results[0].Status)
+	suite.assertEnrollments(enrollments, suite.exportEnrollments())
+
+	code, _ := totp.GenerateCode(device.SecretKey, time.Now())
+	check := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, check)
+	assert.True(suite.T(), check.Result)
+	assert.Equal(suite.T(), "phone", check.DeviceName)
+}
+
+func (suite *ServiceTestSuite) TestExportEnrollmentsToEncryptForPublicKey() {
+	device := suite.createDevice("phone")
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(suite.T(), err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	assert.NoError(suite.T(), err)
+
+	stream := &testExportStream{}
+	req := &proto.MfaExportEnrollmentsDataRequest{
+		ProviderID: suite.ProviderID,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+	assert.NoError(suite.T(), suite.service.ExportEnrollments(context.TODO(), req, stream))
+	assert.NotContains(suite.T(), stream.archive.String(), device.SecretKey)
+
+	enrollments := suite.readArchive(stream.archive.Bytes(), ArchiveKey{PrivateKey: privateKey})
+	assert.Equal(suite.T(), 1, len(enrollments))
+	assert.Equal(suite.T(), device.SecretKey, enrollments[0].Devices[0].Secret)
+}
+
+func (suite *ServiceTestSuite) TestExportEnrollmentsToReturnErrorRequestData() {
+	reqs := map[string]*proto.MfaExportEnrollmentsDataRequest{
+		"Passphrase is required field": {ProviderID: suite.ProviderID},
+		"PublicKey has invalid format": {ProviderID: suite.ProviderID, PublicKey: "not a key"},
+	}
+	for message, req := range reqs {
+		stream := &testExportStream{}
+		err := suite.service.ExportEnrollments(context.TODO(), req, stream)
+		assert.EqualError(suite.T(), err, message)
+		assert.Equal(suite.T(), 0, stream.archive.Len())
+	}
+}
+
+func (suite *ServiceTestSuite) TestRestoreEnrollmentsToKeepUsedRecoveryCodesOnMerge() {
+	device := suite.createDevice("")
+	exported := suite.exportEnrollments()
+
+	check := &proto.MfaCheckDataResponse{}
+	err := suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: device.RecoveryCode[0]}, check)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), check.Result)
+
+	res := suite.restoreEnrollments(ConflictMerge, exported...)
+	assert.Equal(suite.T(), int32(1), res.Restored)
+
+	check = &proto.MfaCheckDataResponse{}
+	err = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: device.RecoveryCode[0]}, check)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), check.Result)
+	assert.Equal(suite.T(), len(exported[0].RecoveryCodes)-1, len(suite.exportEnrollments()[0].RecoveryCodes))
+}
+
+func (suite *ServiceTestSuite) TestRestoreEnrollmentsToApplyConflictPolicy() {
+	first := suite.createDevice("first")
+	exported := suite.exportEnrollments()
+	_ = suite.deleteKeys()
+	second := suite.createDevice("second")
+
+	res := suite.restoreEnrollments(ConflictSkip, exported...)
+	assert.Equal(suite.T(), int32(1), res.Skipped)
+	assert.Equal(suite.T(), RestoreStatusSkipped, res.Results[0].Status)
+	assert.Equal(suite.T(), []string{second.DeviceID}, suite.exportedDeviceIDs())
+
+	res = suite.restoreEnrollments(ConflictMerge, exported...)
+	assert.Equal(suite.T(), int32(1), res.Restored)
+	suite.restoreEnrollments(ConflictMerge, exported...)
+	assert.ElementsMatch(suite.T(), []string{first.DeviceID, second.DeviceID}, suite.exportedDeviceIDs())
+
+	res = suite.restoreEnrollments(ConflictOverwrite, exported...)
+	assert.Equal(suite.T(), int32(1), res.Restored)
+	assert.Equal(suite.T(), []string{first.DeviceID}, suite.exportedDeviceIDs())
+	suite.assertEnrollments(exported, suite.exportEnrollments())
+}
+
+func (suite *ServiceTestSuite) assertEnrollments(expected []*proto.Enrollment, actual []*proto.Enrollment, msgAndArgs ...interface{}) {
+	assert.Equal(suite.T(), len(expected), len(actual), msgAndArgs...)
+	for i := range expected {
+		if i < len(actual) {
+			assert.True(suite.T(), protobuf.Equal(expected[i], actual[i]), msgAndArgs...)
+		}
+	}
+}
+
+func (suite *ServiceTestSuite) exportedDeviceIDs() []string {
+	var ids []string
+	for _, e := range suite.exportEnrollments() {
+		for _, d := range e.Devices {
+			ids = append(ids, d.ID)
+		}
+	}
+	return ids
+}
+
+func (suite *ServiceTestSuite) TestRestoreEnrollmentsToReportInvalidEnrollments() {
+	res := suite.restoreEnrollments(ConflictSkip,
+		&proto.Enrollment{ProviderID: suite.ProviderID},
+		&proto.Enrollment{UserID: suite.userID, ProviderID: suite.ProviderID, Devices: []*proto.EnrollmentDevice{{ID: "1", Secret: "not base32!"}}},
+	)
+	assert.Equal(suite.T(), int32(2), res.Failed)
+	assert.Equal(suite.T(), "UserID is required field", res.Results[0].Error)
+	assert.Equal(suite.T(), "Devices has invalid format", res.Results[1].Error)
+	assert.Equal(suite.T(), RestoreStatusFailed, res.Results[1].Status)
+}
+
+func (suite *ServiceTestSuite) TestRestoreEnrollmentsToReturnErrorRequestData() {
+	reqs := map[string]*proto.MfaRestoreEnrollmentsDataRequest{
+		"Enrollments is required field":  {},
+		"Enrollments has invalid format": {Enrollments: make([]*proto.Enrollment, maxRestoreEnrollments+1)},
+		"Conflict has invalid format":    {Enrollments: []*proto.Enrollment{{}}, Conflict: "replace"},
+	}
+	for message, req := range reqs {
+		err := suite.service.RestoreEnrollments(context.TODO(), req, &proto.MfaRestoreEnrollmentsDataResponse{})
+		assert.EqualError(suite.T(), err, message)
+	}
+}
+
+func (suite *ServiceTestSuite) TestArchiveToRoundTripEnrollments() {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(suite.T(), err)
+
+	enrollments := []*proto.Enrollment{
+		{UserID: "1", ProviderID: "p", RecoveryCodes: []string{"A", "B"}},
+		{UserID: "2", ProviderID: "p", Devices: []*proto.EnrollmentDevice{{ID: "d", Secret: importSecret}}},
+	}
+	keys := map[string][2]ArchiveKey{
+		"passphrase": {{Passphrase: "secret"}, {Passphrase: "secret"}},
+		"rsa":        {{PublicKey: &privateKey.PublicKey}, {PrivateKey: privateKey}},
+	}
+
+	for name, key := range keys {
+		buf := &bytes.Buffer{}
+		w, err := NewArchiveWriter(buf, key[0], "p")
+		assert.NoError(suite.T(), err, name)
+		for _, e := range enrollments {
+			assert.NoError(suite.T(), w.Write(e), name)
+		}
+		assert.NoError(suite.T(), w.Close(), name)
+		assert.NotContains(suite.T(), buf.String(), importSecret, name)
+
+		r, err := NewArchiveReader(bytes.NewReader(buf.Bytes()), key[1])
+		assert.NoError(suite.T(), err, name)
+		assert.Equal(suite.T(), "p", r.ProviderID, name)
+
+		var read []*proto.Enrollment
+		for {
+			e, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(suite.T(), err, name)
+			read = append(read, e)
+		}
+		suite.assertEnrollments(enrollments, read, name)
+	}
+}
+
+func (suite *ServiceTestSuite) TestArchiveToRejectWrongKeyAndTruncation() {
+	buf := &bytes.Buffer{}
+	w, _ := NewArchiveWriter(buf, ArchiveKey{Passphrase: "secret"}, "")
+	_ = w.Write(&proto.Enrollment{UserID: "1", ProviderID: "p"})
+	_ = w.Close()
+
+	r, err := NewArchiveReader(bytes.NewReader(buf.Bytes()), ArchiveKey{Passphrase: "wrong"})
+	assert.NoError(suite.T(), err)
+	_, err = r.Read()
+	assert.Error(suite.T(), err)
+
+	_, err = NewArchiveReader(bytes.NewReader(buf.Bytes()), ArchiveKey{})
+	assert.Error(suite.T(), err)
+
+	truncated := buf.Bytes()[:buf.Len()-1]
+	r, _ = NewArchiveReader(bytes.NewReader(truncated), ArchiveKey{Passphrase: "secret"})
+	_, err = r.Read()
+	assert.Error(suite.T(), err)
+}
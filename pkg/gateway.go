@@ -0,0 +1,408 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/golang/protobuf/jsonpb"
+	protobuf "github.com/golang/protobuf/proto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const (
+	GatewayPrefix = "/v1/mfa/"
+
+	gatewayOpenAPIPath = "openapi.json"
+	gatewayMaxBodySize = 1 << 20
+
+	ErrorRequestBody = "Request body is invalid"
+)
+
+// gatewayRoute maps a REST path to a method of the RPC handler.
+type gatewayRoute struct {
+	path      string
+	operation string
+	summary   string
+	request   protobuf.Message
+	response  protobuf.Message
+	call      func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error
+}
+
+var gatewayRoutes = []*gatewayRoute{
+	{
+		path:      "create",
+		operation: "Create",
+		summary:   "Enroll a new TOTP device",
+		request:   &proto.MfaCreateDataRequest{},
+		response:  &proto.MfaCreateDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.Create(ctx, req.(*proto.MfaCreateDataRequest), res.(*proto.MfaCreateDataResponse))
+		},
+	},
+	{
+		path:      "check",
+		operation: "Check",
+		summary:   "Verify a TOTP code, YubiKey OTP or recovery code",
+		request:   &proto.MfaCheckDataRequest{},
+		response:  &proto.MfaCheckDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.Check(ctx, req.(*proto.MfaCheckDataRequest), res.(*proto.MfaCheckDataResponse))
+		},
+	},
+	{
+		path:      "yubikeys/add",
+		operation: "AddYubiKey",
+		summary:   "Register a YubiKey",
+		request:   &proto.MfaAddYubiKeyDataRequest{},
+		response:  &proto.MfaAddYubiKeyDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.AddYubiKey(ctx, req.(*proto.MfaAddYubiKeyDataRequest), res.(*proto.MfaAddYubiKeyDataResponse))
+		},
+	},
+	{
+		path:      "devices/list",
+		operation: "ListDevices",
+		summary:   "List enrolled devices",
+		request:   &proto.MfaListDevicesDataRequest{},
+		response:  &proto.MfaListDevicesDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ListDevices(ctx, req.(*proto.MfaListDevicesDataRequest), res.(*proto.MfaListDevicesDataResponse))
+		},
+	},
+	{
+		path:      "devices/rename",
+		operation: "RenameDevice",
+		summary:   "Rename an enrolled device",
+		request:   &proto.MfaRenameDeviceDataRequest{},
+		response:  &proto.MfaRenameDeviceDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.RenameDevice(ctx, req.(*proto.MfaRenameDeviceDataRequest), res.(*proto.MfaRenameDeviceDataResponse))
+		},
+	},
+	{
+		path:      "devices/remove",
+		operation: "RemoveDevice",
+		summary:   "Remove an enrolled device",
+		request:   &proto.MfaRemoveDeviceDataRequest{},
+		response:  &proto.MfaRemoveDeviceDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.RemoveDevice(ctx, req.(*proto.MfaRemoveDeviceDataRequest), res.(*proto.MfaRemoveDeviceDataResponse))
+		},
+	},
+	{
+		path:      "devices/rotate-secret",
+		operation: "RotateSecret",
+		summary:   "Issue a new secret for a device with a grace period for the old one",
+		request:   &proto.MfaRotateSecretDataRequest{},
+		response:  &proto.MfaRotateSecretDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.RotateSecret(ctx, req.(*proto.MfaRotateSecretDataRequest), res.(*proto.MfaRotateSecretDataResponse))
+		},
+	},
+	{
+		path:      "enrollments/import",
+		operation: "ImportEnrollment",
+		summary:   "Import TOTP secrets enrolled in another system",
+		request:   &proto.MfaImportEnrollmentDataRequest{},
+		response:  &proto.MfaImportEnrollmentDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ImportEnrollment(ctx, req.(*proto.MfaImportEnrollmentDataRequest), res.(*proto.MfaImportEnrollmentDataResponse))
+		},
+	},
+	{
+		path:      "enrollments/restore",
+		operation: "RestoreEnrollments",
+		summary:   "Restore exported enrollments",
+		request:   &proto.MfaRestoreEnrollmentsDataRequest{},
+		response:  &proto.MfaRestoreEnrollmentsDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.RestoreEnrollments(ctx, req.(*proto.MfaRestoreEnrollmentsDataRequest), res.(*proto.MfaRestoreEnrollmentsDataResponse))
+		},
+	},
+	{
+		path:      "devices/migration/import",
+		operation: "ImportMigration",
+		summary:   "Import the accounts of a Google Authenticator export QR code",
+		request:   &proto.MfaImportMigrationDataRequest{},
+		response:  &proto.MfaImportMigrationDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ImportMigration(ctx, req.(*proto.MfaImportMigrationDataRequest), res.(*proto.MfaImportMigrationDataResponse))
+		},
+	},
+	{
+		path:      "devices/migration/export",
+		operation: "ExportMigration",
+		summary:   "Export the devices of a user as a Google Authenticator export QR code",
+		request:   &proto.MfaExportMigrationDataRequest{},
+		response:  &proto.MfaExportMigrationDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ExportMigration(ctx, req.(*proto.MfaExportMigrationDataRequest), res.(*proto.MfaExportMigrationDataResponse))
+		},
+	},
+	{
+		path:      "trusted-devices/validate",
+		operation: "ValidateTrustedDevice",
+		summary:   "Validate a trusted device token",
+		request:   &proto.MfaValidateTrustedDeviceDataRequest{},
+		response:  &proto.MfaValidateTrustedDeviceDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ValidateTrustedDevice(ctx, req.(*proto.MfaValidateTrustedDeviceDataRequest), res.(*proto.MfaValidateTrustedDeviceDataResponse))
+		},
+	},
+	{
+		path:      "trusted-devices/list",
+		operation: "ListTrustedDevices",
+		summary:   "List trusted devices",
+		request:   &proto.MfaListTrustedDevicesDataRequest{},
+		response:  &proto.MfaListTrustedDevicesDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ListTrustedDevices(ctx, req.(*proto.MfaListTrustedDevicesDataRequest), res.(*proto.MfaListTrustedDevicesDataResponse))
+		},
+	},
+	{
+		path:      "trusted-devices/revoke",
+		operation: "RevokeTrustedDevice",
+		summary:   "Revoke one or all trusted devices",
+		request:   &proto.MfaRevokeTrustedDeviceDataRequest{},
+		response:  &proto.MfaRevokeTrustedDeviceDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.RevokeTrustedDevice(ctx, req.(*proto.MfaRevokeTrustedDeviceDataRequest), res.(*proto.MfaRevokeTrustedDeviceDataResponse))
+		},
+	},
+	{
+		path:      "audit-events/query",
+		operation: "QueryAuditEvents",
+		summary:   "Query audit events",
+		request:   &proto.MfaQueryAuditEventsDataRequest{},
+		response:  &proto.MfaQueryAuditEventsDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.QueryAuditEvents(ctx, req.(*proto.MfaQueryAuditEventsDataRequest), res.(*proto.MfaQueryAuditEventsDataResponse))
+		},
+	},
+	{
+		path:      "providers/get",
+		operation: "GetProviderConfig",
+		summary:   "Get the provider config with defaults applied",
+		request:   &proto.MfaGetProviderConfigDataRequest{},
+		response:  &proto.MfaGetProviderConfigDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.GetProviderConfig(ctx, req.(*proto.MfaGetProviderConfigDataRequest), res.(*proto.MfaGetProviderConfigDataResponse))
+		},
+	},
+	{
+		path:      "providers/set",
+		operation: "SetProviderConfig",
+		summary:   "Create or replace the provider config",
+		request:   &proto.MfaSetProviderConfigDataRequest{},
+		response:  &proto.MfaSetProviderConfigDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.SetProviderConfig(ctx, req.(*proto.MfaSetProviderConfigDataRequest), res.(*proto.MfaSetProviderConfigDataResponse))
+		},
+	},
+	{
+		path:      "providers/list",
+		operation: "ListProviderConfigs",
+		summary:   "List provider configs",
+		request:   &proto.MfaListProviderConfigsDataRequest{},
+		response:  &proto.MfaListProviderConfigsDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ListProviderConfigs(ctx, req.(*proto.MfaListProviderConfigsDataRequest), res.(*proto.MfaListProviderConfigsDataResponse))
+		},
+	},
+	{
+		path:      "providers/delete",
+		operation: "DeleteProviderConfig",
+		summary:   "Delete the stored provider config",
+		request:   &proto.MfaDeleteProviderConfigDataRequest{},
+		response:  &proto.MfaDeleteProviderConfigDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.DeleteProviderConfig(ctx, req.(*proto.MfaDeleteProviderConfigDataRequest), res.(*proto.MfaDeleteProviderConfigDataResponse))
+		},
+	},
+	{
+		path:      "users/status",
+		operation: "GetUserStatus",
+		summary:   "Get the enrollment and lockout status of a user",
+		request:   &proto.MfaGetUserStatusDataRequest{},
+		response:  &proto.MfaGetUserStatusDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.GetUserStatus(ctx, req.(*proto.MfaGetUserStatusDataRequest), res.(*proto.MfaGetUserStatusDataResponse))
+		},
+	},
+	{
+		path:      "users/reset",
+		operation: "ResetEnrollment",
+		summary:   "Remove all factors of a user",
+		request:   &proto.MfaResetEnrollmentDataRequest{},
+		response:  &proto.MfaResetEnrollmentDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ResetEnrollment(ctx, req.(*proto.MfaResetEnrollmentDataRequest), res.(*proto.MfaResetEnrollmentDataResponse))
+		},
+	},
+	{
+		path:      "users/clear-lockout",
+		operation: "ClearLockout",
+		summary:   "Clear the lockout of a user",
+		request:   &proto.MfaClearLockoutDataRequest{},
+		response:  &proto.MfaClearLockoutDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.ClearLockout(ctx, req.(*proto.MfaClearLockoutDataRequest), res.(*proto.MfaClearLockoutDataResponse))
+		},
+	},
+	{
+		path:      "users/bypass-code",
+		operation: "IssueBypassCode",
+		summary:   "Issue a single-use emergency bypass code",
+		request:   &proto.MfaIssueBypassCodeDataRequest{},
+		response:  &proto.MfaIssueBypassCodeDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.IssueBypassCode(ctx, req.(*proto.MfaIssueBypassCodeDataRequest), res.(*proto.MfaIssueBypassCodeDataResponse))
+		},
+	},
+	{
+		path:      "account-recovery/request",
+		operation: "RequestAccountRecovery",
+		summary:   "Start the waiting period of an account recovery",
+		request:   &proto.MfaRequestAccountRecoveryDataRequest{},
+		response:  &proto.MfaRequestAccountRecoveryDataResponse{},
+		call: func(ctx context.Context, h proto.MfaServiceHandler, req, res protobuf.Message) error {
+			return h.RequestAccountRecovery(ctx, req.(*proto.MfaRequestAccountRecoveryDataRequest), res.(*proto.MfaRequestAccountRecoveryDataResponse))
+		},
+	},
+}
+
+type gatewayError struct {
+	Error *proto.Error
+}
+
+type gateway struct {
+	handler    proto.MfaServiceHandler
+	authorizer *Authorizer
+	logger     *zap.Logger
+	routes     map[string]*gatewayRoute
+	openAPI    []byte
+}
+
+// NewGateway exposes the RPC handler as a JSON API under GatewayPrefix. Requests
+// and responses use the protobuf JSON mapping with the field names of mfa.proto.
+// Calls are authorized by the authorizer unless it is nil.
+func NewGateway(handler proto.MfaServiceHandler, authorizer *Authorizer, logger *zap.Logger) http.Handler {
+	g := &gateway{
+		handler:    handler,
+		authorizer: authorizer,
+		logger:     logger,
+		routes:     map[string]*gatewayRoute{},
+		openAPI:    newOpenAPIDocument(gatewayRoutes),
+	}
+	for _, route := range gatewayRoutes {
+		g.routes[route.path] = route
+	}
+
+	return g
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, GatewayPrefix)
+
+	if path == gatewayOpenAPIPath {
+		if r.Method != http.MethodGet {
+			g.methodNotAllowed(w, http.MethodGet)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(g.openAPI)
+		return
+	}
+
+	route, ok := g.routes[path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		g.methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	ctx := httpCorrelationID(w, r)
+
+	req := reflect.New(reflect.TypeOf(route.request).Elem()).Interface().(protobuf.Message)
+	res := reflect.New(reflect.TypeOf(route.response).Elem()).Interface().(protobuf.Message)
+
+	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err := unmarshaler.Unmarshal(http.MaxBytesReader(w, r.Body, gatewayMaxBodySize), req); err != nil {
+		g.logger.Warn("Decode gateway request failed with error", zap.Error(err), zap.String("path", r.URL.Path))
+
+		g.writeError(w, http.StatusBadRequest, ErrorRequestBody)
+		return
+	}
+
+	if g.authorizer != nil {
+		var commonNames []string
+		if r.TLS != nil {
+			commonNames = verifiedCommonNames(r.TLS.VerifiedChains)
+		}
+
+		client := g.authorizer.authenticate(r.Header.Get(APIKeyHeader), commonNames, route.operation)
+		if client == nil {
+			g.writeError(w, http.StatusUnauthorized, ErrorUnauthenticated)
+			return
+		}
+		if err := g.authorizer.authorize(client, route.operation, req); err != nil {
+			g.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		ctx = ContextWithAuthClientID(ctx, client.ID)
+	}
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+	ctx, span := startHandlerSpan(ctx, route.operation, req)
+	err := route.call(ctx, g.handler, req, res)
+	endHandlerSpan(span, res, err)
+	if err == nil {
+		g.write(w, http.StatusOK, res)
+		return
+	}
+
+	if _, ok := err.(*requestError); ok {
+		g.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if e, ok := res.(interface{ GetError() *proto.Error }); ok && e.GetError() != nil {
+		g.write(w, http.StatusBadRequest, res)
+		return
+	}
+
+	g.logger.Error("Gateway call failed with error", zap.Error(err), zap.String("path", r.URL.Path))
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+func (g *gateway) write(w http.ResponseWriter, status int, res protobuf.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	marshaler := &jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	if err := marshaler.Marshal(w, res); err != nil {
+		g.logger.Error("Encode gateway response failed with error", zap.Error(err))
+	}
+}
+
+func (g *gateway) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(gatewayError{Error: &proto.Error{Message: message}}); err != nil {
+		g.logger.Error("Encode gateway error failed with error", zap.Error(err))
+	}
+}
+
+func (g *gateway) methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,96 @@
+package mfa
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (suite *ServiceTestSuite) serveGateway(method string, path string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, GatewayPrefix+path, strings.NewReader(body))
+	NewGateway(suite.service, nil, zap.L()).ServeHTTP(w, r)
+	return w
+}
+
+func (suite *ServiceTestSuite) TestGatewayToCreateAndCheck() {
+	w := suite.serveGateway(http.MethodPost, "create", fmt.Sprintf(`{"ProviderID":"%s","UserID":"%s","AppName":"test"}`, suite.ProviderID, suite.userID))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "application/json", w.Header().Get("Content-Type"))
+
+	created := &proto.MfaCreateDataResponse{}
+	assert.NoError(suite.T(), jsonpb.UnmarshalString(w.Body.String(), created))
+	assert.NotEmpty(suite.T(), created.SecretKey)
+	assert.NotEmpty(suite.T(), created.DeviceID)
+	assert.Equal(suite.T(), 10, len(created.RecoveryCode))
+
+	code, _ := totp.GenerateCode(created.SecretKey, time.Now())
+	w = suite.serveGateway(http.MethodPost, "check", fmt.Sprintf(`{"ProviderID":"%s","UserID":"%s","Code":"%s"}`, suite.ProviderID, suite.userID, code))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	checked := &proto.MfaCheckDataResponse{}
+	assert.NoError(suite.T(), jsonpb.UnmarshalString(w.Body.String(), checked))
+	assert.True(suite.T(), checked.Result)
+	assert.Equal(suite.T(), created.DeviceID, checked.DeviceID)
+}
+
+func (suite *ServiceTestSuite) TestGatewayToReturnErrorCodes() {
+	suite.createDevice("")
+
+	w := suite.serveGateway(http.MethodPost, "check", fmt.Sprintf(`{"ProviderID":"%s","UserID":"%s","Code":"000000"}`, suite.ProviderID, suite.userID))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"Result":false`)
+	assert.Contains(suite.T(), w.Body.String(), ErrorCodeInvalid)
+
+	w = suite.serveGateway(http.MethodPost, "check", fmt.Sprintf(`{"ProviderID":"%s","Code":"000000"}`, suite.ProviderID))
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	assert.JSONEq(suite.T(), fmt.Sprintf(`{"Error":{"Message":"%s"}}`, fmt.Sprintf(ErrorRequestPropertyRequired, "UserID")), w.Body.String())
+
+	w = suite.serveGateway(http.MethodPost, "check", fmt.Sprintf(`{"ProviderID":"%s","UserID":"%s0","Code":"000000"}`, suite.ProviderID, suite.userID))
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), ErrorSecretKeyNotExists)
+
+	w = suite.serveGateway(http.MethodPost, "check", `{"ProviderID":`)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), ErrorRequestBody)
+}
+
+func (suite *ServiceTestSuite) TestGatewayToRejectUnknownRoutes() {
+	w := suite.serveGateway(http.MethodPost, "unknown", `{}`)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	w = suite.serveGateway(http.MethodGet, "create", "")
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(suite.T(), http.MethodPost, w.Header().Get("Allow"))
+}
+
+func (suite *ServiceTestSuite) TestGatewayToServeOpenAPIDocument() {
+	w := suite.serveGateway(http.MethodGet, gatewayOpenAPIPath, "")
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	doc := struct {
+		OpenAPI    string                            `json:"openapi"`
+		Paths      map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]*openAPISchema `json:"schemas"`
+		} `json:"components"`
+	}{}
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(suite.T(), openAPIVersion, doc.OpenAPI)
+	assert.Equal(suite.T(), len(gatewayRoutes), len(doc.Paths))
+	assert.Contains(suite.T(), doc.Paths[GatewayPrefix+"check"], "post")
+
+	device := doc.Components.Schemas["Device"]
+	assert.NotNil(suite.T(), device)
+	assert.Equal(suite.T(), "string", device.Properties["CreatedAt"].Type)
+	assert.Equal(suite.T(), "#/components/schemas/Device", doc.Components.Schemas["MfaListDevicesDataResponse"].Properties["Devices"].Items.Ref)
+	assert.NotNil(suite.T(), doc.Components.Schemas["Error"])
+}
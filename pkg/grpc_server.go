@@ -0,0 +1,242 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type grpcServer struct {
+	handler proto.MfaServiceHandler
+	logger  *zap.Logger
+}
+
+// NewGRPCServer adapts the RPC handler to the standard gRPC server interface.
+// Validation errors are reported as InvalidArgument, errors returned along with
+// an error code in the response as FailedPrecondition with that code.
+func NewGRPCServer(handler proto.MfaServiceHandler, logger *zap.Logger) proto.MfaServiceServer {
+	return &grpcServer{handler: handler, logger: logger}
+}
+
+func (s *grpcServer) Create(ctx context.Context, req *proto.MfaCreateDataRequest) (*proto.MfaCreateDataResponse, error) {
+	res := &proto.MfaCreateDataResponse{}
+	if err := s.handler.Create(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) Check(ctx context.Context, req *proto.MfaCheckDataRequest) (*proto.MfaCheckDataResponse, error) {
+	res := &proto.MfaCheckDataResponse{}
+	if err := s.handler.Check(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) AddYubiKey(ctx context.Context, req *proto.MfaAddYubiKeyDataRequest) (*proto.MfaAddYubiKeyDataResponse, error) {
+	res := &proto.MfaAddYubiKeyDataResponse{}
+	if err := s.handler.AddYubiKey(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ListDevices(ctx context.Context, req *proto.MfaListDevicesDataRequest) (*proto.MfaListDevicesDataResponse, error) {
+	res := &proto.MfaListDevicesDataResponse{}
+	if err := s.handler.ListDevices(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) RenameDevice(ctx context.Context, req *proto.MfaRenameDeviceDataRequest) (*proto.MfaRenameDeviceDataResponse, error) {
+	res := &proto.MfaRenameDeviceDataResponse{}
+	if err := s.handler.RenameDevice(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) RemoveDevice(ctx context.Context, req *proto.MfaRemoveDeviceDataRequest) (*proto.MfaRemoveDeviceDataResponse, error) {
+	res := &proto.MfaRemoveDeviceDataResponse{}
+	if err := s.handler.RemoveDevice(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ValidateTrustedDevice(ctx context.Context, req *proto.MfaValidateTrustedDeviceDataRequest) (*proto.MfaValidateTrustedDeviceDataResponse, error) {
+	res := &proto.MfaValidateTrustedDeviceDataResponse{}
+	if err := s.handler.ValidateTrustedDevice(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ListTrustedDevices(ctx context.Context, req *proto.MfaListTrustedDevicesDataRequest) (*proto.MfaListTrustedDevicesDataResponse, error) {
+	res := &proto.MfaListTrustedDevicesDataResponse{}
+	if err := s.handler.ListTrustedDevices(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) RevokeTrustedDevice(ctx context.Context, req *proto.MfaRevokeTrustedDeviceDataRequest) (*proto.MfaRevokeTrustedDeviceDataResponse, error) {
+	res := &proto.MfaRevokeTrustedDeviceDataResponse{}
+	if err := s.handler.RevokeTrustedDevice(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) QueryAuditEvents(ctx context.Context, req *proto.MfaQueryAuditEventsDataRequest) (*proto.MfaQueryAuditEventsDataResponse, error) {
+	res := &proto.MfaQueryAuditEventsDataResponse{}
+	if err := s.handler.QueryAuditEvents(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) GetProviderConfig(ctx context.Context, req *proto.MfaGetProviderConfigDataRequest) (*proto.MfaGetProviderConfigDataResponse, error) {
+	res := &proto.MfaGetProviderConfigDataResponse{}
+	if err := s.handler.GetProviderConfig(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) SetProviderConfig(ctx context.Context, req *proto.MfaSetProviderConfigDataRequest) (*proto.MfaSetProviderConfigDataResponse, error) {
+	res := &proto.MfaSetProviderConfigDataResponse{}
+	if err := s.handler.SetProviderConfig(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ListProviderConfigs(ctx context.Context, req *proto.MfaListProviderConfigsDataRequest) (*proto.MfaListProviderConfigsDataResponse, error) {
+	res := &proto.MfaListProviderConfigsDataResponse{}
+	if err := s.handler.ListProviderConfigs(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) DeleteProviderConfig(ctx context.Context, req *proto.MfaDeleteProviderConfigDataRequest) (*proto.MfaDeleteProviderConfigDataResponse, error) {
+	res := &proto.MfaDeleteProviderConfigDataResponse{}
+	if err := s.handler.DeleteProviderConfig(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) GetUserStatus(ctx context.Context, req *proto.MfaGetUserStatusDataRequest) (*proto.MfaGetUserStatusDataResponse, error) {
+	res := &proto.MfaGetUserStatusDataResponse{}
+	if err := s.handler.GetUserStatus(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ResetEnrollment(ctx context.Context, req *proto.MfaResetEnrollmentDataRequest) (*proto.MfaResetEnrollmentDataResponse, error) {
+	res := &proto.MfaResetEnrollmentDataResponse{}
+	if err := s.handler.ResetEnrollment(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ClearLockout(ctx context.Context, req *proto.MfaClearLockoutDataRequest) (*proto.MfaClearLockoutDataResponse, error) {
+	res := &proto.MfaClearLockoutDataResponse{}
+	if err := s.handler.ClearLockout(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) IssueBypassCode(ctx context.Context, req *proto.MfaIssueBypassCodeDataRequest) (*proto.MfaIssueBypassCodeDataResponse, error) {
+	res := &proto.MfaIssueBypassCodeDataResponse{}
+	if err := s.handler.IssueBypassCode(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) RequestAccountRecovery(ctx context.Context, req *proto.MfaRequestAccountRecoveryDataRequest) (*proto.MfaRequestAccountRecoveryDataResponse, error) {
+	res := &proto.MfaRequestAccountRecoveryDataResponse{}
+	if err := s.handler.RequestAccountRecovery(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) RotateSecret(ctx context.Context, req *proto.MfaRotateSecretDataRequest) (*proto.MfaRotateSecretDataResponse, error) {
+	res := &proto.MfaRotateSecretDataResponse{}
+	if err := s.handler.RotateSecret(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ImportEnrollment(ctx context.Context, req *proto.MfaImportEnrollmentDataRequest) (*proto.MfaImportEnrollmentDataResponse, error) {
+	res := &proto.MfaImportEnrollmentDataResponse{}
+	if err := s.handler.ImportEnrollment(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ExportEnrollments(req *proto.MfaExportEnrollmentsDataRequest, stream proto.MfaService_ExportEnrollmentsServer) error {
+	if err := s.handler.ExportEnrollments(stream.Context(), req, &grpcExportStream{stream}); err != nil {
+		return s.status(err, nil)
+	}
+	return nil
+}
+
+func (s *grpcServer) RestoreEnrollments(ctx context.Context, req *proto.MfaRestoreEnrollmentsDataRequest) (*proto.MfaRestoreEnrollmentsDataResponse, error) {
+	res := &proto.MfaRestoreEnrollmentsDataResponse{}
+	if err := s.handler.RestoreEnrollments(ctx, req, res); err != nil {
+		return nil, s.status(err, nil)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ImportMigration(ctx context.Context, req *proto.MfaImportMigrationDataRequest) (*proto.MfaImportMigrationDataResponse, error) {
+	res := &proto.MfaImportMigrationDataResponse{}
+	if err := s.handler.ImportMigration(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+func (s *grpcServer) ExportMigration(ctx context.Context, req *proto.MfaExportMigrationDataRequest) (*proto.MfaExportMigrationDataResponse, error) {
+	res := &proto.MfaExportMigrationDataResponse{}
+	if err := s.handler.ExportMigration(ctx, req, res); err != nil {
+		return nil, s.status(err, res.Error)
+	}
+	return res, nil
+}
+
+// grpcExportStream adapts the gRPC stream to the stream of the RPC handler.
+type grpcExportStream struct {
+	proto.MfaService_ExportEnrollmentsServer
+}
+
+func (s *grpcExportStream) Close() error {
+	return nil
+}
+
+func (s *grpcServer) status(err error, resErr *proto.Error) error {
+	if _, ok := err.(*requestError); ok {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if resErr != nil {
+		return status.Error(codes.FailedPrecondition, resErr.Message)
+	}
+
+	s.logger.Error("gRPC call failed with error", zap.Error(err))
+
+	return status.Error(codes.Internal, codes.Internal.String())
+}
@@ -0,0 +1,58 @@
+package mfa
+
+import (
+	"context"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestGRPCServerToServeMfaService() {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	proto.RegisterMfaServiceServer(server, NewGRPCServer(suite.service, zap.L()))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	assert.NoError(suite.T(), err)
+	defer conn.Close()
+
+	client := proto.NewMfaServiceClient(conn)
+	created, err := client.Create(context.TODO(), &proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, AppName: "test", UserID: suite.userID})
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), created.SecretKey)
+
+	code, _ := totp.GenerateCode(created.SecretKey, time.Now())
+	checked, err := client.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), checked.Result)
+}
+
+func (suite *ServiceTestSuite) TestGRPCServerToReturnStatusCodes() {
+	server := NewGRPCServer(suite.service, zap.L())
+	suite.createDevice("")
+
+	res, err := server.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "000000"})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorCodeInvalid, res.Error.Message)
+
+	_, err = server.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, Code: "000000"})
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+	assert.Equal(suite.T(), fmt.Sprintf(ErrorRequestPropertyRequired, "UserID"), status.Convert(err).Message())
+
+	_, err = server.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID + "0", Code: "000000"})
+	assert.Equal(suite.T(), codes.FailedPrecondition, status.Code(err))
+	assert.Equal(suite.T(), ErrorSecretKeyNotExists, status.Convert(err).Message())
+}
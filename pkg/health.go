@@ -0,0 +1,158 @@
+package mfa
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pquerna/otp/totp"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	// ClockSkewPolicyFailClosed rejects codes while the clock is out of sync with the storage.
+	ClockSkewPolicyFailClosed = "fail_closed"
+	// ClockSkewPolicyIgnore only reports the skew in the health check.
+	ClockSkewPolicyIgnore = "ignore"
+
+	defaultClockSkewThreshold = 5 * time.Second
+
+	ErrorClockSkew = "Server clock is out of sync"
+)
+
+// StorageStatus is the result of a storage health check.
+type StorageStatus struct {
+	LatencySeconds   float64 `json:"latency_seconds"`
+	ClockSkewSeconds float64 `json:"clock_skew_seconds"`
+}
+
+const (
+	// SigningKeyOK is reported while the current signing key is within the rotation interval.
+	SigningKeyOK = "ok"
+	// SigningKeyMissing is reported until the first assertion generated a signing key.
+	SigningKeyMissing = "missing"
+	// SigningKeyStale is reported when the current signing key is overdue for rotation.
+	SigningKeyStale = "stale"
+)
+
+// SigningKeyStatus is the result of the signing key health check.
+type SigningKeyStatus struct {
+	Status     string `json:"status"`
+	KeyID      string `json:"kid,omitempty"`
+	AgeSeconds int64  `json:"age_seconds,omitempty"`
+}
+
+// clockState keeps the outcome of the last clock skew check for Check.
+type clockState struct {
+	mu     sync.RWMutex
+	skewed bool
+}
+
+func (c *clockState) set(skewed bool) {
+	c.mu.Lock()
+	c.skewed = skewed
+	c.mu.Unlock()
+}
+
+func (c *clockState) get() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.skewed
+}
+
+// CheckStorage pings the storage and compares the local time with the Redis
+// server time. It fails when the storage is unreachable or the skew exceeds the
+// threshold, with the fail closed policy Check rejects codes until the next
+// check succeeds.
+func (s *service) CheckStorage() (*StorageStatus, error) {
+	start := time.Now()
+	if err := s.redis.Ping().Err(); err != nil {
+		storageUp.Set(0)
+		s.logger.Error("Storage health check failed with error", zap.Error(err))
+
+		return nil, err
+	}
+	latency := time.Since(start)
+	storageUp.Set(1)
+	storageLatency.Set(latency.Seconds())
+
+	before := time.Now()
+	server, err := s.redis.Time().Result()
+	if err != nil {
+		s.logger.Error("Getting time from Redis failed with error", zap.Error(err))
+
+		return nil, err
+	}
+	// The server time is read about halfway through the round trip.
+	skew := before.Add(time.Since(before) / 2).Sub(server)
+	clockSkew.Set(skew.Seconds())
+
+	status := &StorageStatus{
+		LatencySeconds:   latency.Seconds(),
+		ClockSkewSeconds: skew.Seconds(),
+	}
+
+	threshold := s.opts().ClockSkewThreshold
+	skewed := skew > threshold || skew < -threshold
+	s.clock.set(skewed)
+	if skewed {
+		s.logger.Error("Clock skew exceeds the threshold", zap.Duration("skew", skew), zap.Duration("threshold", threshold))
+
+		return status, fmt.Errorf("clock skew of %s exceeds %s", skew, threshold)
+	}
+
+	return status, nil
+}
+
+// clockSkewed reports whether Check has to fail closed because of the clock skew.
+func (s *service) clockSkewed() bool {
+	return s.opts().ClockSkewPolicy == ClockSkewPolicyFailClosed && s.clock.get()
+}
+
+// CheckSigningKey reports the key step-up assertions are signed with. It does
+// not generate or remove keys, the key is rotated by the next assertion.
+func (s *service) CheckSigningKey() (*SigningKeyStatus, error) {
+	keys, err := s.signingKeys()
+	if err != nil {
+		s.logger.Error("Signing key health check failed with error", zap.Error(err))
+
+		return nil, err
+	}
+
+	rotation := s.opts().SigningKeyRotation
+	key := currentSigningKey(keys, rotation)
+	if key == nil {
+		return &SigningKeyStatus{Status: SigningKeyMissing}, nil
+	}
+
+	status := &SigningKeyStatus{
+		Status:     SigningKeyOK,
+		KeyID:      key.ID,
+		AgeSeconds: int64(signingKeyAge(key).Seconds()),
+	}
+	if signingKeyAge(keys[len(keys)-1]) >= rotation {
+		status.Status = SigningKeyStale
+	}
+
+	return status, nil
+}
+
+// SelfTest generates a TOTP secret and validates a code of it, so a broken
+// build or environment is not marked ready.
+func (s *service) SelfTest() error {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: ServiceName, AccountName: "self-test"})
+	if err != nil {
+		return err
+	}
+
+	code, err := totp.GenerateCode(key.Secret(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := validateTotp(code, key.Secret(), 0, 0, "", defaultTotpSkew, 0, time.Now()); !ok {
+		return errors.New("TOTP self test failed to validate a generated code")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestCheckStorageToReportClockSkew() {
+	status, err := suite.service.CheckStorage()
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 0, status.ClockSkewSeconds, defaultClockSkewThreshold.Seconds())
+	assert.True(suite.T(), status.LatencySeconds > 0)
+	assert.False(suite.T(), suite.service.clockSkewed())
+
+	suite.service.Reload(ClockSkewThreshold(0))
+	status, err = suite.service.CheckStorage()
+	if status.ClockSkewSeconds != 0 {
+		assert.Error(suite.T(), err)
+		assert.True(suite.T(), suite.service.clockSkewed())
+	}
+}
+
+func (suite *ServiceTestSuite) TestCheckToFailClosedOnClockSkew() {
+	device := suite.createDevice("")
+	suite.service.clock.set(true)
+
+	assert.False(suite.T(), suite.checkCode(device.SecretKey))
+
+	res := &proto.MfaCheckDataResponse{}
+	err := suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "123456"}, res)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ErrorClockSkew, res.Error.Message)
+
+	suite.service = NewService(suite.redis, zap.L(), ClockSkewPolicy(ClockSkewPolicyIgnore))
+	suite.service.clock.set(true)
+	assert.True(suite.T(), suite.checkCode(device.SecretKey))
+}
+
+func (suite *ServiceTestSuite) TestCheckSigningKeyToReturnActiveKey() {
+	key, err := suite.service.activeSigningKey()
+	assert.NoError(suite.T(), err)
+
+	status, err := suite.service.CheckSigningKey()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), SigningKeyOK, status.Status)
+	assert.Equal(suite.T(), key.ID, status.KeyID)
+}
+
+func (suite *ServiceTestSuite) TestCheckSigningKeyToNotGenerateKey() {
+	status, err := suite.service.CheckSigningKey()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), SigningKeyMissing, status.Status)
+	assert.Empty(suite.T(), status.KeyID)
+
+	count, err := suite.redis.HLen(mfaSigningKeyStorage).Result()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), count)
+}
+
+func (suite *ServiceTestSuite) TestCheckSigningKeyToReportStaleKey() {
+	suite.service = NewService(suite.redis, zap.L(), SigningKeyRotation(time.Hour))
+	key, err := suite.service.activeSigningKey()
+	assert.NoError(suite.T(), err)
+	suite.ageSigningKey(key.ID, time.Hour)
+
+	status, err := suite.service.CheckSigningKey()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), SigningKeyStale, status.Status)
+	assert.Equal(suite.T(), key.ID, status.KeyID)
+
+	count, err := suite.redis.HLen(mfaSigningKeyStorage).Result()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+}
+
+func (suite *ServiceTestSuite) TestSelfTestToValidateGeneratedCode() {
+	assert.NoError(suite.T(), suite.service.SelfTest())
+}
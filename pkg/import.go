@@ -0,0 +1,217 @@
+package mfa
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"go.uber.org/zap"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxImportRecords       = 1000
+	minImportSecretSize    = 10
+	recoveryCodeHashPrefix = "sha256:"
+
+	ErrorDeviceAlreadyExists = "Device already exists"
+)
+
+var errDeviceAlreadyExists = errors.New(ErrorDeviceAlreadyExists)
+
+// ImportEnrollment enrolls TOTP secrets migrated from another system. Records
+// are imported independently and the response reports the outcome of each one,
+// a secret the user already has is not imported again.
+func (s *service) ImportEnrollment(ctx context.Context, req *proto.MfaImportEnrollmentDataRequest, res *proto.MfaImportEnrollmentDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateImportEnrollmentRequest(req); err != nil {
+		s.logger.Error("Validate import enrollment request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	config, err := s.providerConfig(req.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	if !factorAllowed(config, MethodTotp) {
+		res.Error = &proto.Error{
+			Message: ErrorFactorNotAllowed,
+		}
+		return nil
+	}
+
+	for i, record := range req.Records {
+		result := &proto.ImportResult{Index: int32(i), UserID: record.UserID}
+
+		d, err := s.importRecord(req.ProviderID, AuthClientID(ctx), req.Actor, "import", record, config)
+		if err != nil {
+			if _, ok := err.(*requestError); !ok && err != errDeviceAlreadyExists {
+				s.logger.Error("Import enrollment failed with error", zap.Error(err), zap.String("userId", record.UserID))
+			}
+
+			result.Error = err.Error()
+			res.Failed++
+		} else {
+			result.Result = true
+			result.DeviceID = d.ID
+			res.Imported++
+		}
+
+		res.Results = append(res.Results, result)
+	}
+
+	s.logger.Info(
+		"Enrollments imported",
+		zap.String("providerId", req.ProviderID),
+		zap.String("actor", AuthClientID(ctx)),
+		zap.String("note", req.Actor),
+		zap.Int32("imported", res.Imported),
+		zap.Int32("failed", res.Failed),
+	)
+
+	return nil
+}
+
+// importRecord enrolls the device of the record, actor, note and reason are recorded in the audit event.
+func (s *service) importRecord(providerId string, actor string, note string, reason string, record *proto.ImportRecord, config *proto.ProviderConfig) (*device, error) {
+	d, err := parseImportRecord(record)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]interface{}, 0, len(record.RecoveryCodeHashes))
+	for _, hash := range record.RecoveryCodeHashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 {
+			return nil, newRequestError(ErrorRequestPropertyFormat, "RecoveryCodeHashes")
+		}
+		hashes = append(hashes, recoveryCodeHashPrefix+hash)
+	}
+	if len(hashes) > 0 && !factorAllowed(config, MethodRecoveryCode) {
+		return nil, errors.New(ErrorFactorNotAllowed)
+	}
+
+	devices, err := s.loadDevices(record.UserID, providerId)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range devices {
+		if existing.Secret == d.Secret {
+			return nil, errDeviceAlreadyExists
+		}
+	}
+
+	// The user already uses the secret, so there is nothing left to confirm.
+	d.ConfirmedAt = time.Now().Unix()
+	if d, err = s.addDevice(record.UserID, providerId, d); err != nil {
+		return nil, err
+	}
+
+	if len(hashes) > 0 {
+		if err = s.redis.SAdd(s.GetRecoveryStorageKey(record.UserID, providerId), hashes...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentCreated,
+		UserID:     record.UserID,
+		ProviderID: providerId,
+		Method:     MethodTotp,
+		DeviceID:   d.ID,
+		Actor:      actor,
+		Reason:     reason,
+		Note:       note,
+	})
+
+	return d, nil
+}
+
+// parseImportRecord returns the device of the record with the TOTP parameters
+// validated, the parameters of an otpauth URI take precedence over the fields.
+func parseImportRecord(record *proto.ImportRecord) (*device, error) {
+	if record.UserID == "" {
+		return nil, newRequestError(ErrorRequestPropertyRequired, "UserID")
+	}
+
+	d := &device{
+		Name:      record.DeviceName,
+		Secret:    record.Secret,
+		Algorithm: record.Algorithm,
+		Digits:    int(record.Digits),
+		Period:    int(record.Period),
+	}
+
+	if record.URI != "" {
+		u, err := url.Parse(record.URI)
+		if err != nil || u.Scheme != "otpauth" || u.Host != "totp" {
+			return nil, newRequestError(ErrorRequestPropertyFormat, "URI")
+		}
+
+		query := u.Query()
+		d.Secret = query.Get("secret")
+		d.Algorithm = query.Get("algorithm")
+		for name, value := range map[string]*int{"digits": &d.Digits, "period": &d.Period} {
+			if query.Get(name) == "" {
+				*value = 0
+				continue
+			}
+			if *value, err = strconv.Atoi(query.Get(name)); err != nil {
+				return nil, newRequestError(ErrorRequestPropertyFormat, "URI")
+			}
+		}
+	}
+
+	if d.Secret == "" {
+		return nil, newRequestError(ErrorRequestPropertyRequired, "Secret")
+	}
+	d.Secret = strings.TrimRight(strings.ToUpper(strings.Replace(d.Secret, " ", "", -1)), "=")
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(d.Secret)
+	if err != nil || len(secret) < minImportSecretSize {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "Secret")
+	}
+
+	if d.Algorithm == "" {
+		d.Algorithm = defaultTotpAlgorithm
+	}
+	d.Algorithm = strings.ToUpper(d.Algorithm)
+	if _, ok := totpAlgorithms[d.Algorithm]; !ok {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "Algorithm")
+	}
+
+	if d.Digits == 0 {
+		d.Digits = defaultTotpDigits
+	}
+	if d.Digits != 6 && d.Digits != 8 {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "Digits")
+	}
+
+	if d.Period == 0 {
+		d.Period = defaultTotpPeriod
+	}
+	if d.Period < 0 {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "Period")
+	}
+
+	return d, nil
+}
+
+func (s *service) validateImportEnrollmentRequest(req *proto.MfaImportEnrollmentDataRequest) error {
+	if req.ProviderID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "ProviderID")
+	}
+	if len(req.Records) == 0 {
+		return newRequestError(ErrorRequestPropertyRequired, "Records")
+	}
+	if len(req.Records) > maxImportRecords {
+		return newRequestError(ErrorRequestPropertyFormat, "Records")
+	}
+	return nil
+}
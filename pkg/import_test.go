@@ -0,0 +1,112 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"time"
+)
+
+const importSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func (suite *ServiceTestSuite) importEnrollment(records ...*proto.ImportRecord) *proto.MfaImportEnrollmentDataResponse {
+	res := &proto.MfaImportEnrollmentDataResponse{}
+	req := &proto.MfaImportEnrollmentDataRequest{ProviderID: suite.ProviderID, Actor: "migration", Records: records}
+	err := suite.service.ImportEnrollment(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+func (suite *ServiceTestSuite) TestImportEnrollmentToAcceptImportedSecrets() {
+	res := suite.importEnrollment(
+		&proto.ImportRecord{UserID: suite.userID, URI: "otpauth://totp/Old:alice?secret=" + importSecret + "&algorithm=SHA256&digits=8&period=60"},
+		&proto.ImportRecord{UserID: suite.userID, Secret: "jbsw y3dp ehpk 3pxp", DeviceName: "tablet"},
+	)
+	assert.Nil(suite.T(), res.Error)
+	assert.Equal(suite.T(), int32(2), res.Imported)
+	assert.Equal(suite.T(), int32(0), res.Failed)
+	assert.True(suite.T(), res.Results[0].Result)
+	assert.NotEmpty(suite.T(), res.Results[0].DeviceID)
+
+	code, _ := totp.GenerateCodeCustom(importSecret, time.Now(), totp.ValidateOpts{Period: 60, Digits: otp.DigitsEight, Algorithm: otp.AlgorithmSHA256})
+	check := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, check)
+	assert.True(suite.T(), check.Result)
+	assert.Equal(suite.T(), res.Results[0].DeviceID, check.DeviceID)
+
+	code, _ = totp.GenerateCode("JBSWY3DPEHPK3PXP", time.Now())
+	check = &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, check)
+	assert.True(suite.T(), check.Result)
+	assert.Equal(suite.T(), "tablet", check.DeviceName)
+}
+
+func (suite *ServiceTestSuite) TestImportEnrollmentToReportInvalidRecords() {
+	suite.importEnrollment(&proto.ImportRecord{UserID: suite.userID, Secret: importSecret})
+
+	res := suite.importEnrollment(
+		&proto.ImportRecord{Secret: importSecret},
+		&proto.ImportRecord{UserID: suite.userID, URI: "https://example.com"},
+		&proto.ImportRecord{UserID: suite.userID, Secret: "not base32!"},
+		&proto.ImportRecord{UserID: suite.userID, Secret: importSecret, Algorithm: "MD5"},
+		&proto.ImportRecord{UserID: suite.userID, Secret: importSecret, Digits: 7},
+		&proto.ImportRecord{UserID: suite.userID, Secret: importSecret, RecoveryCodeHashes: []string{"abc"}},
+		&proto.ImportRecord{UserID: suite.userID, Secret: importSecret},
+	)
+	assert.Equal(suite.T(), int32(0), res.Imported)
+	assert.Equal(suite.T(), int32(7), res.Failed)
+
+	var errors []string
+	for i, result := range res.Results {
+		assert.Equal(suite.T(), int32(i), result.Index)
+		assert.False(suite.T(), result.Result)
+		errors = append(errors, result.Error)
+	}
+	assert.Equal(suite.T(), []string{
+		"UserID is required field",
+		"URI has invalid format",
+		"Secret has invalid format",
+		"Algorithm has invalid format",
+		"Digits has invalid format",
+		"RecoveryCodeHashes has invalid format",
+		ErrorDeviceAlreadyExists,
+	}, errors)
+}
+
+func (suite *ServiceTestSuite) TestImportEnrollmentToAcceptRecoveryCodeHashesOnce() {
+	res := suite.importEnrollment(&proto.ImportRecord{
+		UserID:             suite.userID,
+		Secret:             importSecret,
+		RecoveryCodeHashes: []string{sha256Hex("old-code-1"), sha256Hex("old-code-2")},
+	})
+	assert.Equal(suite.T(), int32(1), res.Imported)
+
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "old-code-1"}
+	check := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), req, check)
+	assert.True(suite.T(), check.Result)
+	assert.Equal(suite.T(), MethodRecoveryCode, check.Method)
+
+	check = &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), req, check)
+	assert.False(suite.T(), check.Result)
+
+	check = &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: recoveryCodeHashPrefix + sha256Hex("old-code-2")}, check)
+	assert.False(suite.T(), check.Result)
+}
+
+func (suite *ServiceTestSuite) TestImportEnrollmentToReturnErrorRequestData() {
+	reqs := map[string]*proto.MfaImportEnrollmentDataRequest{
+		"ProviderID is required field": {Records: []*proto.ImportRecord{{UserID: suite.userID}}},
+		"Records is required field":    {ProviderID: suite.ProviderID},
+		"Records has invalid format":   {ProviderID: suite.ProviderID, Records: make([]*proto.ImportRecord, maxImportRecords+1)},
+	}
+	for message, req := range reqs {
+		err := suite.service.ImportEnrollment(context.TODO(), req, &proto.MfaImportEnrollmentDataResponse{})
+		assert.EqualError(suite.T(), err, message)
+	}
+}
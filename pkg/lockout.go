@@ -0,0 +1,97 @@
+package mfa
+
+import (
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	mfaFailureStoragePattern = "mfa_failures_%s_%s"
+	mfaLockoutStoragePattern = "mfa_lockout_%s_%s"
+
+	ErrorLockedOut = "Too many failed attempts, try again later"
+)
+
+// lockedOut reports whether checks of the user are rejected after too many failures.
+func (s *service) lockedOut(userId string, providerId string) (bool, error) {
+	exists, err := s.redis.Exists(s.GetLockoutStorageKey(userId, providerId)).Result()
+	if err != nil {
+		s.logger.Error("Getting lockout from Redis failed with error", zap.Error(err))
+
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+// trackFailures counts invalid codes within the lockout duration and locks the
+// user out once the provider threshold is reached. A valid code resets the count.
+func (s *service) trackFailures(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse, config *proto.ProviderConfig) {
+	key := s.GetFailureStorageKey(req.UserID, req.ProviderID)
+
+	if res.Result {
+		if err := s.redis.Del(key).Err(); err != nil {
+			s.logger.Error("Reset failed attempts in Redis failed with error", zap.Error(err))
+		}
+		return
+	}
+
+	if config.LockoutThreshold <= 0 || res.Error == nil || res.Error.Message != ErrorCodeInvalid {
+		return
+	}
+
+	duration := time.Duration(config.LockoutDuration) * time.Second
+
+	var failures *redis.IntCmd
+	_, err := s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		failures = pipe.Incr(key)
+		pipe.Expire(key, duration)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Count failed attempts in Redis failed with error", zap.Error(err))
+
+		return
+	}
+
+	if failures.Val() < int64(config.LockoutThreshold) {
+		return
+	}
+
+	until := time.Now().Add(duration)
+	_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Set(s.GetLockoutStorageKey(req.UserID, req.ProviderID), until.Unix(), duration)
+		pipe.Del(key)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Lock out user in Redis failed with error", zap.Error(err))
+
+		return
+	}
+
+	s.logger.Warn(
+		"User locked out after failed attempts",
+		zap.String("userId", req.UserID),
+		zap.String("providerId", req.ProviderID),
+		zap.Int64("failures", failures.Val()),
+	)
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditLockout,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		ExpiresAt:  until.Unix(),
+	})
+}
+
+func (s *service) GetFailureStorageKey(userId string, providerId string) string {
+	return fmt.Sprintf(mfaFailureStoragePattern, userId, providerId)
+}
+
+func (s *service) GetLockoutStorageKey(userId string, providerId string) string {
+	return fmt.Sprintf(mfaLockoutStoragePattern, userId, providerId)
+}
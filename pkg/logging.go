@@ -0,0 +1,206 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	grpcMetadata "google.golang.org/grpc/metadata"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	CorrelationIDHeader = "X-Correlation-Id"
+
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+
+	redacted = "[REDACTED]"
+
+	// userIdHashLength is the length of the hex prefix of the hash logged instead of a user ID.
+	userIdHashLength = 16
+	maxCorrelationID = 128
+)
+
+var (
+	// sensitiveLogKeys are the normalized field names whose values are never logged.
+	sensitiveLogKeys = map[string]bool{
+		"code":          true,
+		"codes":         true,
+		"secret":        true,
+		"recoverycode":  true,
+		"recoverycodes": true,
+		"bypasscode":    true,
+		"otp":           true,
+		"token":         true,
+		"assertion":     true,
+		"password":      true,
+		"passphrase":    true,
+		"apikey":        true,
+		"authorization": true,
+	}
+
+	// sensitiveLogValues match codes and secrets in messages and errors: bypass
+	// codes, JWTs, YubiKey OTPs and base32 secrets and recovery codes.
+	sensitiveLogValues = regexp.MustCompile(`(?i:BYPASS-[A-Z2-7-]+)|\beyJ[\w-]*\.[\w-]+\.[\w-]*|\b[cbdefghijklnrtuv]{32,48}\b|\b[A-Z2-7]{16,}\b`)
+
+	validCorrelationID = regexp.MustCompile(`^[\w.:-]+$`)
+)
+
+type correlationIDKey struct{}
+
+// NewLogger returns a logger of the level in the JSON or console format. Codes,
+// secrets and tokens are redacted from every entry and user IDs are replaced
+// by their hash unless the level is debug.
+func NewLogger(level string, format string) (*zap.Logger, error) {
+	config := zap.NewProductionConfig()
+
+	if level != "" {
+		if err := config.Level.UnmarshalText([]byte(level)); err != nil {
+			return nil, err
+		}
+	}
+
+	switch format {
+	case "", LogFormatJSON:
+	case LogFormatConsole:
+		config.Encoding = LogFormatConsole
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	hashUserIDs := config.Level.Level() > zapcore.DebugLevel
+
+	return config.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newRedactingCore(core, hashUserIDs)
+	}))
+}
+
+// redactingCore rewrites the entries before they reach the wrapped core.
+type redactingCore struct {
+	zapcore.Core
+	hashUserIDs bool
+}
+
+func newRedactingCore(core zapcore.Core, hashUserIDs bool) zapcore.Core {
+	return &redactingCore{Core: core, hashUserIDs: hashUserIDs}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redact(fields)), hashUserIDs: c.hashUserIDs}
+}
+
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = redactValue(ent.Message)
+	return c.Core.Write(ent, c.redact(fields))
+}
+
+func (c *redactingCore) redact(fields []zapcore.Field) []zapcore.Field {
+	redactedFields := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		key := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(f.Key))
+
+		switch {
+		case sensitiveLogKeys[key]:
+			f = zap.String(f.Key, redacted)
+		case f.Type == zapcore.StringType && strings.HasSuffix(key, "userid") && c.hashUserIDs:
+			f = zap.String(f.Key, hashUserID(f.String))
+		case f.Type == zapcore.StringType:
+			f = zap.String(f.Key, redactValue(f.String))
+		case f.Type == zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok && err != nil {
+				f = zap.String(f.Key, redactValue(err.Error()))
+			}
+		}
+
+		redactedFields[i] = f
+	}
+	return redactedFields
+}
+
+func redactValue(value string) string {
+	return sensitiveLogValues.ReplaceAllString(value, redacted)
+}
+
+// hashUserID returns a prefix of the SHA-256 hash of the user ID, it still
+// relates the entries of a user without revealing the ID.
+func hashUserID(userId string) string {
+	if userId == "" {
+		return ""
+	}
+	return sha256Hex(userId)[:userIdHashLength]
+}
+
+// ContextWithCorrelationID returns a context carrying the correlation ID logged
+// with the entries of the call.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of the context.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// correlationID keeps the ID passed by the caller when it is valid, otherwise
+// a new one is generated.
+func correlationID(id string) string {
+	if id != "" && len(id) <= maxCorrelationID && validCorrelationID.MatchString(id) {
+		return id
+	}
+
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// CorrelationHandlerWrapper adds the correlation ID of the go-micro metadata to
+// the context of every call of the micro server.
+func CorrelationHandlerWrapper() server.HandlerWrapper {
+	return func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			md, _ := metadata.FromContext(ctx)
+			id := correlationID(microCarrier(md).Get(CorrelationIDHeader))
+
+			return fn(ContextWithCorrelationID(ctx, id), req, rsp)
+		}
+	}
+}
+
+// CorrelationUnaryInterceptor adds the correlation ID of the gRPC metadata to the
+// context of every call and returns it in the response header.
+func CorrelationUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := grpcMetadata.FromIncomingContext(ctx)
+		id := correlationID(grpcCarrier(md).Get(CorrelationIDHeader))
+		_ = grpc.SetHeader(ctx, grpcMetadata.Pairs(CorrelationIDHeader, id))
+
+		return handler(ContextWithCorrelationID(ctx, id), req)
+	}
+}
+
+// httpCorrelationID adds the correlation ID of the request header to the
+// context and returns it in the response header.
+func httpCorrelationID(w http.ResponseWriter, r *http.Request) context.Context {
+	id := correlationID(r.Header.Get(CorrelationIDHeader))
+	w.Header().Set(CorrelationIDHeader, id)
+
+	return ContextWithCorrelationID(r.Context(), id)
+}
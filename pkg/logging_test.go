@@ -0,0 +1,100 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"strings"
+)
+
+func newObservedLogger(hashUserIDs bool) (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(newRedactingCore(core, hashUserIDs)), logs
+}
+
+func (suite *ServiceTestSuite) TestRedactingLoggerToRedactSecrets() {
+	logger, logs := newObservedLogger(true)
+
+	logger.With(zap.String("token", "eyJhbGciOiJFUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln")).Error(
+		"Check of BYPASS-ABCD-EFGH-IJKL-MNOP failed",
+		zap.String("code", "123456"),
+		zap.String("Secret", "JBSWY3DPEHPK3PXP"),
+		zap.Error(errors.New("recovery code ABCDEFGHIJKLMNOP is invalid")),
+		zap.String("reason", "otp ccccccbcgujhingjrdejhgfnuetrgigvejhhgbkugded replayed"),
+		zap.String("userId", suite.userID),
+		zap.String("providerId", suite.ProviderID),
+	)
+
+	entries := logs.All()
+	assert.Len(suite.T(), entries, 1)
+	assert.Equal(suite.T(), "Check of [REDACTED] failed", entries[0].Message)
+
+	fields := entries[0].ContextMap()
+	assert.Equal(suite.T(), redacted, fields["token"])
+	assert.Equal(suite.T(), redacted, fields["code"])
+	assert.Equal(suite.T(), redacted, fields["Secret"])
+	assert.Equal(suite.T(), "recovery code [REDACTED] is invalid", fields["error"])
+	assert.Equal(suite.T(), "otp [REDACTED] replayed", fields["reason"])
+	assert.Equal(suite.T(), hashUserID(suite.userID), fields["userId"])
+	assert.NotContains(suite.T(), fields["userId"], suite.userID)
+	assert.Equal(suite.T(), suite.ProviderID, fields["providerId"])
+}
+
+func (suite *ServiceTestSuite) TestRedactingLoggerToKeepUserIdsInDebugMode() {
+	logger, logs := newObservedLogger(false)
+	logger.Info("Enrolled", zap.String("targetUserId", suite.userID), zap.String("code", "123456"))
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(suite.T(), suite.userID, fields["targetUserId"])
+	assert.Equal(suite.T(), redacted, fields["code"])
+}
+
+func (suite *ServiceTestSuite) TestNewLoggerToValidateLevelAndFormat() {
+	_, err := NewLogger("debug", LogFormatConsole)
+	assert.NoError(suite.T(), err)
+	_, err = NewLogger("", "")
+	assert.NoError(suite.T(), err)
+
+	_, err = NewLogger("verbose", LogFormatJSON)
+	assert.Error(suite.T(), err)
+	_, err = NewLogger("info", "xml")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ServiceTestSuite) TestCorrelationHandlerWrapperToPassCorrelationId() {
+	var id string
+	fn := CorrelationHandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		id = CorrelationID(ctx)
+		return nil
+	})
+	call := func(md metadata.Metadata) string {
+		_ = fn(metadata.NewContext(context.TODO(), md), &testRequest{method: "MfaService.Check"}, nil)
+		return id
+	}
+
+	assert.Equal(suite.T(), "req-1", call(metadata.Metadata{CorrelationIDHeader: "req-1"}))
+	assert.Equal(suite.T(), "req-2", call(metadata.Metadata{strings.ToLower(CorrelationIDHeader): "req-2"}))
+
+	generated := call(metadata.Metadata{})
+	assert.Len(suite.T(), generated, 32)
+	assert.NotEqual(suite.T(), generated, call(metadata.Metadata{}))
+	assert.Len(suite.T(), call(metadata.Metadata{CorrelationIDHeader: "bad id\n"}), 32)
+}
+
+func (suite *ServiceTestSuite) TestServiceToLogCorrelationId() {
+	logger, logs := newObservedLogger(true)
+	service := NewService(suite.redis, logger)
+
+	ctx := ContextWithCorrelationID(context.TODO(), "req-1")
+	err := service.Check(ctx, &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, &proto.MfaCheckDataResponse{})
+	assert.Error(suite.T(), err)
+
+	entries := logs.FilterField(zap.String("correlationId", "req-1")).All()
+	assert.NotEmpty(suite.T(), entries)
+}
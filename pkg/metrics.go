@@ -0,0 +1,299 @@
+package mfa
+
+import (
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	metricsNamespace = "mfa"
+
+	metricResultSuccess = "success"
+	metricResultError   = "error"
+
+	// maxMetricProviders limits the provider label values, later providers are reported as metricProviderOther.
+	maxMetricProviders  = 100
+	metricProviderOther = "other"
+
+	mfaLowRecoveryCodesStoragePattern = "mfa_low_recovery_codes_%s"
+	mfaLowRecoveryCodesProviders      = "mfa_low_recovery_codes_providers"
+	defaultLowRecoveryCodes           = 3
+)
+
+var (
+	userLifecycleEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "user_lifecycle_events_total",
+		Help:      "User lifecycle events handled by the service.",
+	}, []string{"event", "result"})
+
+	userLifecycleEnrollments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "user_lifecycle_enrollments_total",
+		Help:      "Enrollments removed or moved to another user by user lifecycle events.",
+	}, []string{"event"})
+
+	totpDrift = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "totp_drift_steps",
+		Help:      "Time step offset of valid TOTP codes, the clock drift of the authenticators.",
+		Buckets:   prometheus.LinearBuckets(-5, 1, 11),
+	}, []string{"provider"})
+
+	outboxDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "outbox_dropped_total",
+		Help:      "Events dropped because the outbox was full.",
+	})
+
+	storageUp = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "storage_up",
+		Help:      "Whether the storage answered the last health check.",
+	})
+
+	storageLatency = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "storage_latency_seconds",
+		Help:      "Round trip time of the last storage health check.",
+	})
+
+	clockSkew = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "clock_skew_seconds",
+		Help:      "Difference between the local time and the storage time.",
+	})
+
+	enrollments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "enrollments_total",
+		Help:      "Factors enrolled by method.",
+	}, []string{"provider", "method"})
+
+	verifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "verifications_total",
+		Help:      "Checked codes by method and outcome.",
+	}, []string{"provider", "method", "outcome"})
+
+	verificationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "verification_duration_seconds",
+		Help:      "Time taken by Check.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "method"})
+
+	recoveryCodesUsed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "recovery_codes_used_total",
+		Help:      "Recovery codes consumed by users.",
+	}, []string{"provider"})
+
+	lockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "lockouts_total",
+		Help:      "Users locked out after too many failed attempts.",
+	}, []string{"provider"})
+
+	replayRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "replay_rejections_total",
+		Help:      "Codes rejected because they were already used.",
+	}, []string{"provider", "method"})
+
+	storageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Time taken by storage operations.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+
+	configReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "config_reloads_total",
+		Help:      "Reloads of the config file by result.",
+	}, []string{"result"})
+
+	configLastReload = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "config_last_reload_success_timestamp_seconds",
+		Help:      "Time of the last successful reload of the config file.",
+	})
+
+	lowRecoveryCodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "users_low_recovery_codes"),
+		"Users with fewer remaining recovery codes than the threshold.",
+		[]string{"provider"},
+		nil,
+	)
+
+	// verificationOutcomes are the outcome labels of the check errors, other errors are reported as "error".
+	verificationOutcomes = map[string]string{
+		ErrorCodeInvalid:        "invalid_code",
+		ErrorLockedOut:          "locked_out",
+		ErrorYubiKeyReplayed:    "replayed",
+		ErrorFactorNotAllowed:   "factor_not_allowed",
+		ErrorSecretKeyNotExists: "not_enrolled",
+		ErrorYubiKeyNotExists:   "not_enrolled",
+		ErrorClockSkew:          "clock_skew",
+	}
+
+	metricProviders = &providerLabels{seen: map[string]bool{}}
+)
+
+// providerLabels bounds the cardinality of the provider label.
+type providerLabels struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (p *providerLabels) label(providerId string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.seen[providerId] {
+		return providerId
+	}
+	if len(p.seen) >= maxMetricProviders {
+		return metricProviderOther
+	}
+	p.seen[providerId] = true
+
+	return providerId
+}
+
+func observeDrift(providerId string, offset int) {
+	totpDrift.WithLabelValues(metricProviders.label(providerId)).Observe(float64(offset))
+}
+
+// observeAuditEvent updates the domain metrics, every metric has a matching audit event.
+func observeAuditEvent(event *proto.AuditEvent) {
+	provider := metricProviders.label(event.ProviderID)
+
+	switch event.Type {
+	case AuditEnrollmentCreated:
+		// Enrollments moved by a user merge have no method and are not new.
+		if event.Method != "" {
+			enrollments.WithLabelValues(provider, event.Method).Inc()
+		}
+	case AuditVerificationSucceeded:
+		verifications.WithLabelValues(provider, event.Method, metricResultSuccess).Inc()
+	case AuditVerificationFailed:
+		outcome, ok := verificationOutcomes[event.Reason]
+		if !ok {
+			outcome = metricResultError
+		}
+		verifications.WithLabelValues(provider, event.Method, outcome).Inc()
+		if event.Reason == ErrorYubiKeyReplayed {
+			replayRejections.WithLabelValues(provider, event.Method).Inc()
+		}
+	case AuditRecoveryCodeUsed:
+		recoveryCodesUsed.WithLabelValues(provider).Inc()
+	case AuditLockout:
+		lockouts.WithLabelValues(provider).Inc()
+	}
+}
+
+func observeVerification(providerId string, method string, start time.Time) {
+	verificationDuration.WithLabelValues(metricProviders.label(providerId), method).Observe(time.Since(start).Seconds())
+}
+
+// InstrumentStorage observes the duration of every command of the client by
+// command name, pipelines are observed as a whole.
+func InstrumentStorage(client *redis.Client) {
+	client.WrapProcess(func(process func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			start := time.Now()
+			err := process(cmd)
+			storageDuration.WithLabelValues(cmd.Name()).Observe(time.Since(start).Seconds())
+			return err
+		}
+	})
+	client.WrapProcessPipeline(func(process func(cmds []redis.Cmder) error) func(cmds []redis.Cmder) error {
+		return func(cmds []redis.Cmder) error {
+			start := time.Now()
+			err := process(cmds)
+			storageDuration.WithLabelValues("pipeline").Observe(time.Since(start).Seconds())
+			return err
+		}
+	})
+}
+
+// trackRecoveryCodes records whether the user has fewer recovery codes left
+// than the threshold. Users of providers without recovery codes and users
+// without any enrollment are not counted.
+func (s *service) trackRecoveryCodes(userId string, providerId string) {
+	key := fmt.Sprintf(mfaLowRecoveryCodesStoragePattern, providerId)
+
+	low := false
+	config, err := s.providerConfig(providerId)
+	if err == nil && factorAllowed(config, MethodRecoveryCode) {
+		var remaining int64
+		if remaining, err = s.redis.SCard(s.GetRecoveryStorageKey(userId, providerId)).Result(); err == nil && remaining < int64(s.opts().LowRecoveryCodes) {
+			low, err = s.hasEnrollment(userId, providerId)
+		}
+	}
+	if err != nil {
+		s.logger.Error("Tracking recovery codes failed with error", zap.Error(err), zap.String("userId", userId))
+
+		return
+	}
+
+	_, err = s.redis.Pipelined(func(pipe redis.Pipeliner) error {
+		if low {
+			pipe.SAdd(key, userId)
+			pipe.SAdd(mfaLowRecoveryCodesProviders, providerId)
+		} else {
+			pipe.SRem(key, userId)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Tracking recovery codes in Redis failed with error", zap.Error(err), zap.String("userId", userId))
+	}
+}
+
+// MetricsCollector reports the metrics read from the storage on every scrape.
+func (s *service) MetricsCollector() prometheus.Collector {
+	return &storageCollector{service: s}
+}
+
+type storageCollector struct {
+	service *service
+}
+
+func (c *storageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lowRecoveryCodesDesc
+}
+
+func (c *storageCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.service
+
+	providers, err := s.redis.SMembers(mfaLowRecoveryCodesProviders).Result()
+	if err != nil {
+		s.logger.Error("Getting metric providers from Redis failed with error", zap.Error(err))
+
+		return
+	}
+
+	users := map[string]int64{}
+	for _, providerId := range providers {
+		count, err := s.redis.SCard(fmt.Sprintf(mfaLowRecoveryCodesStoragePattern, providerId)).Result()
+		if err != nil {
+			s.logger.Error("Getting low recovery code users from Redis failed with error", zap.Error(err))
+
+			return
+		}
+		users[metricProviders.label(providerId)] += count
+	}
+
+	for provider, count := range users {
+		ch <- prometheus.MustNewConstMetric(lowRecoveryCodesDesc, prometheus.GaugeValue, float64(count), provider)
+	}
+}
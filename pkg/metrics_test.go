@@ -0,0 +1,60 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"strings"
+)
+
+func (suite *ServiceTestSuite) TestMetricsToCountVerificationsByOutcome() {
+	invalid := verifications.WithLabelValues(suite.ProviderID, MethodTotp, "invalid_code")
+	success := verifications.WithLabelValues(suite.ProviderID, MethodTotp, metricResultSuccess)
+	enrolled := enrollments.WithLabelValues(suite.ProviderID, MethodTotp)
+	invalidBefore, successBefore := testutil.ToFloat64(invalid), testutil.ToFloat64(success)
+	enrolledBefore := testutil.ToFloat64(enrolled)
+
+	device := suite.createDevice("")
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "000000"}, &proto.MfaCheckDataResponse{})
+	assert.True(suite.T(), suite.checkCode(device.SecretKey))
+
+	assert.Equal(suite.T(), invalidBefore+1, testutil.ToFloat64(invalid))
+	assert.Equal(suite.T(), successBefore+1, testutil.ToFloat64(success))
+	assert.Equal(suite.T(), enrolledBefore+1, testutil.ToFloat64(enrolled))
+}
+
+func (suite *ServiceTestSuite) TestMetricsToReportUsersWithLowRecoveryCodes() {
+	suite.service = NewService(suite.redis, zap.L(), LowRecoveryCodes(10))
+	device := suite.createDevice("")
+	collector := suite.service.MetricsCollector()
+
+	assert.NoError(suite.T(), testutil.CollectAndCompare(collector, strings.NewReader("")))
+	used := testutil.ToFloat64(recoveryCodesUsed.WithLabelValues(suite.ProviderID))
+
+	res := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: device.RecoveryCode[0]}, res)
+	assert.True(suite.T(), res.Result)
+
+	expected := `
+# HELP mfa_users_low_recovery_codes Users with fewer remaining recovery codes than the threshold.
+# TYPE mfa_users_low_recovery_codes gauge
+mfa_users_low_recovery_codes{provider="` + suite.ProviderID + `"} 1
+`
+	assert.NoError(suite.T(), testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+	assert.Equal(suite.T(), used+1, testutil.ToFloat64(recoveryCodesUsed.WithLabelValues(suite.ProviderID)))
+
+	_ = suite.service.RemoveDevice(context.TODO(), &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: device.DeviceID}, &proto.MfaRemoveDeviceDataResponse{})
+	assert.NoError(suite.T(), testutil.CollectAndCompare(collector, strings.NewReader(strings.Replace(expected, "} 1", "} 0", 1))))
+}
+
+func (suite *ServiceTestSuite) TestMetricsToLimitProviderLabels() {
+	labels := &providerLabels{seen: map[string]bool{}}
+	for i := 0; i < maxMetricProviders; i++ {
+		labels.label(strings.Repeat("p", i+1))
+	}
+
+	assert.Equal(suite.T(), "p", labels.label("p"))
+	assert.Equal(suite.T(), metricProviderOther, labels.label("new"))
+}
@@ -0,0 +1,332 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/pquerna/otp"
+	"go.uber.org/zap"
+	"net/url"
+	"strings"
+)
+
+const (
+	migrationScheme  = "otpauth-migration"
+	migrationHost    = "offline"
+	migrationVersion = 1
+	maxMigrationURI  = 64 * 1024
+
+	ErrorMigrationIssuerMismatch = "Account issuer does not match the provider"
+	ErrorMigrationHotp           = "HOTP accounts are not supported"
+)
+
+var (
+	errMigrationIssuerMismatch = errors.New(ErrorMigrationIssuerMismatch)
+	errMigrationHotp           = errors.New(ErrorMigrationHotp)
+
+	migrationAlgorithms = map[proto.MigrationPayload_Algorithm]string{
+		proto.MigrationPayload_ALGORITHM_UNSPECIFIED: "SHA1",
+		proto.MigrationPayload_SHA1:                  "SHA1",
+		proto.MigrationPayload_SHA256:                "SHA256",
+		proto.MigrationPayload_SHA512:                "SHA512",
+	}
+	migrationDigits = map[proto.MigrationPayload_DigitCount]int32{
+		proto.MigrationPayload_DIGIT_COUNT_UNSPECIFIED: 6,
+		proto.MigrationPayload_SIX:                     6,
+		proto.MigrationPayload_EIGHT:                   8,
+	}
+)
+
+// ImportMigration enrolls the accounts of a Google Authenticator export QR code
+// for the user. When the provider has an issuer only its accounts are imported,
+// the response reports the outcome of each account of the payload.
+func (s *service) ImportMigration(ctx context.Context, req *proto.MfaImportMigrationDataRequest, res *proto.MfaImportMigrationDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate import migration request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	payload, err := parseMigrationURI(req.URI)
+	if err != nil {
+		s.logger.Error("Validate import migration request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	config, err := s.providerConfig(req.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	if !factorAllowed(config, MethodTotp) {
+		res.Error = &proto.Error{
+			Message: ErrorFactorNotAllowed,
+		}
+		return nil
+	}
+
+	for i, params := range payload.Parameters {
+		result := &proto.ImportResult{Index: int32(i), UserID: req.UserID}
+
+		var d *device
+		record, err := migrationRecord(req.UserID, params, config)
+		if err == nil {
+			d, err = s.importRecord(req.ProviderID, AuthClientID(ctx), "", "migration", record, config)
+		}
+		if err != nil {
+			if _, ok := err.(*requestError); !ok && err != errDeviceAlreadyExists && err != errMigrationIssuerMismatch && err != errMigrationHotp {
+				s.logger.Error("Import migration failed with error", zap.Error(err), zap.String("userId", req.UserID))
+			}
+
+			result.Error = err.Error()
+			res.Failed++
+		} else {
+			result.Result = true
+			result.DeviceID = d.ID
+			res.Imported++
+		}
+
+		res.Results = append(res.Results, result)
+	}
+
+	return nil
+}
+
+// ExportMigration returns a Google Authenticator export QR code with the confirmed
+// devices of the user. The secrets are only returned for a code passing Check.
+// Devices with a period other than 30 seconds or digits other than 6 or 8 can
+// not be represented in the payload and are left out.
+func (s *service) ExportMigration(ctx context.Context, req *proto.MfaExportMigrationDataRequest, res *proto.MfaExportMigrationDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate export migration request failed with error", zap.Error(err))
+
+		return err
+	}
+	if req.Code == "" {
+		err := newRequestError(ErrorRequestPropertyRequired, "Code")
+		s.logger.Error("Validate export migration request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	config, err := s.providerConfig(req.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	if config.Issuer == "" && req.AppName == "" {
+		err := newRequestError(ErrorRequestPropertyRequired, "AppName")
+		s.logger.Error("Validate export migration request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if !factorAllowed(config, MethodTotp) {
+		res.Error = &proto.Error{
+			Message: ErrorFactorNotAllowed,
+		}
+		return nil
+	}
+
+	devices, err := s.loadDevices(req.UserID, req.ProviderID)
+	if err != nil {
+		s.logger.Error("Getting devices from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	issuer := req.AppName
+	if config.Issuer != "" {
+		issuer = config.Issuer
+	}
+	name := req.UserID
+	if req.Email != "" {
+		name = req.Email
+	}
+
+	var batchId [4]byte
+	if _, err := rand.Read(batchId[:]); err != nil {
+		return err
+	}
+	payload := &proto.MigrationPayload{
+		Version:   migrationVersion,
+		BatchSize: 1,
+		BatchID:   int32(binary.BigEndian.Uint32(batchId[:]) >> 1),
+	}
+
+	for _, d := range devices {
+		if d.ConfirmedAt == 0 || (d.Period != 0 && d.Period != defaultTotpPeriod) || (d.Digits != 0 && d.Digits != 6 && d.Digits != 8) {
+			continue
+		}
+
+		params, err := migrationParameters(d, name, issuer)
+		if err != nil {
+			s.logger.Error("Export migration failed with error", zap.Error(err), zap.String("deviceId", d.ID))
+
+			return err
+		}
+		payload.Parameters = append(payload.Parameters, params)
+	}
+
+	if len(payload.Parameters) == 0 {
+		res.Error = &proto.Error{
+			Message: ErrorDeviceNotExists,
+		}
+		return nil
+	}
+
+	// The code goes through Check, so it is subject to the lockout and audited.
+	check := &proto.MfaCheckDataResponse{}
+	if err = s.Check(ctx, &proto.MfaCheckDataRequest{ProviderID: req.ProviderID, UserID: req.UserID, Code: req.Code}, check); err != nil {
+		return err
+	}
+	if !check.Result {
+		res.Error = check.Error
+		if res.Error == nil {
+			res.Error = &proto.Error{
+				Message: ErrorCodeInvalid,
+			}
+		}
+		return nil
+	}
+
+	data, err := protobuf.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	res.URI = migrationScheme + "://" + migrationHost + "?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(data))
+	res.Exported = int32(len(payload.Parameters))
+
+	key, err := otp.NewKeyFromURL(res.URI)
+	if err != nil {
+		return err
+	}
+
+	qrSize := req.QrSize
+	if qrSize == 0 {
+		qrSize = config.QrSize
+	}
+	if res.ImageBased, err = s.generateBase64QrCode(key, int(qrSize), config.QrForeground, config.QrBackground); err != nil {
+		s.logger.Error("Generate base 64 qr code with error", zap.Error(err))
+
+		return err
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditMigrationExported,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     MethodTotp,
+	})
+
+	return nil
+}
+
+// parseMigrationURI decodes the payload of an otpauth-migration URI.
+func parseMigrationURI(uri string) (*proto.MigrationPayload, error) {
+	if uri == "" {
+		return nil, newRequestError(ErrorRequestPropertyRequired, "URI")
+	}
+	if len(uri) > maxMigrationURI {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "URI")
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != migrationScheme || u.Host != migrationHost {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "URI")
+	}
+
+	// Scanners often leave "+" unescaped, the query decodes it as a space.
+	data := strings.Replace(u.Query().Get("data"), " ", "+", -1)
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		raw, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+	}
+	if err != nil || len(raw) == 0 {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "URI")
+	}
+
+	payload := &proto.MigrationPayload{}
+	if err = protobuf.Unmarshal(raw, payload); err != nil {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "URI")
+	}
+
+	return payload, nil
+}
+
+// migrationRecord converts an account of a migration payload to an import record.
+func migrationRecord(userId string, params *proto.MigrationPayload_OtpParameters, config *proto.ProviderConfig) (*proto.ImportRecord, error) {
+	if params.Type == proto.MigrationPayload_HOTP {
+		return nil, errMigrationHotp
+	}
+
+	issuer, name := params.Issuer, params.Name
+	if i := strings.Index(name, ":"); i >= 0 {
+		if issuer == "" {
+			issuer = strings.TrimSpace(name[:i])
+		}
+		name = strings.TrimSpace(name[i+1:])
+	}
+	if config.Issuer != "" && !strings.EqualFold(issuer, config.Issuer) {
+		return nil, errMigrationIssuerMismatch
+	}
+
+	algorithm, ok := migrationAlgorithms[params.Algorithm]
+	if !ok {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "Algorithm")
+	}
+	digits, ok := migrationDigits[params.Digits]
+	if !ok {
+		return nil, newRequestError(ErrorRequestPropertyFormat, "Digits")
+	}
+
+	return &proto.ImportRecord{
+		UserID:     userId,
+		Secret:     base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(params.Secret),
+		Algorithm:  algorithm,
+		Digits:     digits,
+		DeviceName: name,
+	}, nil
+}
+
+// migrationParameters converts the device to an account of a migration payload.
+func migrationParameters(d *device, name string, issuer string) (*proto.MigrationPayload_OtpParameters, error) {
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(d.Secret, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	params := &proto.MigrationPayload_OtpParameters{
+		Secret:    secret,
+		Name:      name,
+		Issuer:    issuer,
+		Algorithm: proto.MigrationPayload_SHA1,
+		Digits:    proto.MigrationPayload_SIX,
+		Type:      proto.MigrationPayload_TOTP,
+	}
+
+	switch d.Algorithm {
+	case "", "SHA1":
+	case "SHA256":
+		params.Algorithm = proto.MigrationPayload_SHA256
+	case "SHA512":
+		params.Algorithm = proto.MigrationPayload_SHA512
+	default:
+		return nil, errors.New("unsupported algorithm " + d.Algorithm)
+	}
+	if d.Digits == 8 {
+		params.Digits = proto.MigrationPayload_EIGHT
+	}
+
+	return params, nil
+}
@@ -0,0 +1,119 @@
+package mfa
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+func migrationURI(params ...*proto.MigrationPayload_OtpParameters) string {
+	data, _ := protobuf.Marshal(&proto.MigrationPayload{Parameters: params, Version: 1, BatchSize: 1})
+	return "otpauth-migration://offline?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(data))
+}
+
+func (suite *ServiceTestSuite) importMigration(uri string) *proto.MfaImportMigrationDataResponse {
+	res := &proto.MfaImportMigrationDataResponse{}
+	req := &proto.MfaImportMigrationDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, URI: uri}
+	err := suite.service.ImportMigration(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+func (suite *ServiceTestSuite) TestImportMigrationToEnrollTotpAccounts() {
+	secret, _ := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(importSecret)
+	res := suite.importMigration(migrationURI(
+		&proto.MigrationPayload_OtpParameters{Secret: secret, Name: "test:alice", Type: proto.MigrationPayload_TOTP},
+		&proto.MigrationPayload_OtpParameters{Secret: secret, Name: "alice", Type: proto.MigrationPayload_HOTP},
+	))
+	assert.Nil(suite.T(), res.Error)
+	assert.Equal(suite.T(), int32(1), res.Imported)
+	assert.Equal(suite.T(), int32(1), res.Failed)
+	assert.Equal(suite.T(), ErrorMigrationHotp, res.Results[1].Error)
+
+	assert.True(suite.T(), suite.checkCode(importSecret))
+
+	devices, _ := suite.service.loadDevices(suite.userID, suite.ProviderID)
+	assert.Len(suite.T(), devices, 1)
+	assert.Equal(suite.T(), "alice", devices[0].Name)
+}
+
+func (suite *ServiceTestSuite) TestImportMigrationToRejectAccountsOfAnotherIssuer() {
+	suite.setProviderConfig(&proto.ProviderConfig{Issuer: "Provider"})
+
+	secret, _ := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(importSecret)
+	res := suite.importMigration(migrationURI(
+		&proto.MigrationPayload_OtpParameters{Secret: secret, Name: "Other:alice", Type: proto.MigrationPayload_TOTP},
+		&proto.MigrationPayload_OtpParameters{Secret: secret, Name: "alice", Issuer: "provider", Type: proto.MigrationPayload_TOTP},
+	))
+	assert.Equal(suite.T(), ErrorMigrationIssuerMismatch, res.Results[0].Error)
+	assert.True(suite.T(), res.Results[1].Result)
+}
+
+func (suite *ServiceTestSuite) TestImportMigrationToRejectInvalidUri() {
+	for _, uri := range []string{"otpauth://totp/test?secret=" + importSecret, "otpauth-migration://offline?data=%%%", "otpauth-migration://offline?data=AAAA"} {
+		err := suite.service.ImportMigration(context.TODO(), &proto.MfaImportMigrationDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, URI: uri}, &proto.MfaImportMigrationDataResponse{})
+		assert.Error(suite.T(), err, uri)
+	}
+}
+
+func (suite *ServiceTestSuite) TestExportMigrationToRoundTripDevices() {
+	suite.importEnrollment(
+		&proto.ImportRecord{UserID: suite.userID, Secret: importSecret, Algorithm: "SHA256", Digits: 8},
+		&proto.ImportRecord{UserID: suite.userID, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY", Period: 60},
+	)
+	suite.createDevice("unconfirmed")
+	code, _ := totp.GenerateCodeCustom("GEZDGNBVGY3TQOJQGEZDGNBVGY", time.Now(), totp.ValidateOpts{Period: 60, Digits: otp.DigitsSix})
+
+	res := &proto.MfaExportMigrationDataResponse{}
+	req := &proto.MfaExportMigrationDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, AppName: "test", Email: "alice@example.com", Code: code}
+	err := suite.service.ExportMigration(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), res.Error)
+	assert.Equal(suite.T(), int32(1), res.Exported)
+	assert.NotEmpty(suite.T(), res.ImageBased)
+
+	payload, err := parseMigrationURI(res.URI)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), payload.Parameters, 1)
+	assert.Equal(suite.T(), "alice@example.com", payload.Parameters[0].Name)
+	assert.Equal(suite.T(), "test", payload.Parameters[0].Issuer)
+	assert.Equal(suite.T(), proto.MigrationPayload_SHA256, payload.Parameters[0].Algorithm)
+	assert.Equal(suite.T(), proto.MigrationPayload_EIGHT, payload.Parameters[0].Digits)
+
+	record, err := migrationRecord("bob", payload.Parameters[0], &proto.ProviderConfig{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), importSecret, record.Secret)
+}
+
+func (suite *ServiceTestSuite) TestExportMigrationToRequireDevices() {
+	res := &proto.MfaExportMigrationDataResponse{}
+	req := &proto.MfaExportMigrationDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, AppName: "test", Code: "123456"}
+	err := suite.service.ExportMigration(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ErrorDeviceNotExists, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestExportMigrationToRequireValidCode() {
+	device := suite.createDevice("")
+	suite.checkCode(device.SecretKey)
+
+	req := &proto.MfaExportMigrationDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, AppName: "test"}
+	err := suite.service.ExportMigration(context.TODO(), req, &proto.MfaExportMigrationDataResponse{})
+	assert.Regexp(suite.T(), regexp.MustCompile("Code is required field"), err)
+
+	res := &proto.MfaExportMigrationDataResponse{}
+	req.Code = "000000"
+	err = suite.service.ExportMigration(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ErrorCodeInvalid, res.Error.Message)
+	assert.Empty(suite.T(), res.URI)
+}
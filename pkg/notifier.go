@@ -0,0 +1,49 @@
+package mfa
+
+import (
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers account recovery notifications to the user, for example by
+// email or SMS.
+type Notifier interface {
+	Notify(notification *proto.RecoveryNotification) error
+}
+
+type logNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogNotifier returns a notifier only logging notifications, it is used when
+// no other notifier is set.
+func NewLogNotifier(logger *zap.Logger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) Notify(notification *proto.RecoveryNotification) error {
+	n.logger.Info(
+		"Account recovery notification",
+		zap.String("type", notification.Type),
+		zap.String("userId", notification.UserID),
+		zap.String("providerId", notification.ProviderID),
+		zap.Int64("completesAt", notification.CompletesAt),
+	)
+
+	return nil
+}
+
+type brokerNotifier struct {
+	outbox *Outbox
+}
+
+// NewBrokerNotifier returns a notifier publishing notifications to the broker
+// through the outbox for a service delivering them to users, a publisher for
+// proto.RecoveryNotification must be registered in it.
+func NewBrokerNotifier(outbox *Outbox) Notifier {
+	return &brokerNotifier{outbox: outbox}
+}
+
+func (n *brokerNotifier) Notify(notification *proto.RecoveryNotification) error {
+	return n.outbox.Enqueue(notification)
+}
@@ -0,0 +1,128 @@
+package mfa
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const openAPIVersion = "3.0.3"
+
+type openAPISchema struct {
+	Ref        string                    `json:"$ref,omitempty"`
+	Type       string                    `json:"type,omitempty"`
+	Format     string                    `json:"format,omitempty"`
+	Items      *openAPISchema            `json:"items,omitempty"`
+	Properties map[string]*openAPISchema `json:"properties,omitempty"`
+}
+
+// newOpenAPIDocument generates the OpenAPI document of the gateway from its
+// routes and the protobuf messages they accept and return.
+func newOpenAPIDocument(routes []*gatewayRoute) []byte {
+	schemas := map[string]*openAPISchema{}
+	schemas["GatewayError"] = openAPIMessageSchema(reflect.TypeOf(gatewayError{}), schemas)
+	paths := map[string]interface{}{}
+
+	for _, route := range routes {
+		request := openAPIFieldSchema(reflect.TypeOf(route.request), schemas)
+		response := openAPIFieldSchema(reflect.TypeOf(route.response), schemas)
+
+		paths[GatewayPrefix+route.path] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"summary":     route.summary,
+				"operationId": route.operation,
+				"requestBody": map[string]interface{}{
+					"required": true,
+					"content":  openAPIContent(request),
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "OK", "content": openAPIContent(response)},
+					"400": map[string]interface{}{
+						"description": "Invalid request or the operation failed",
+						"content":     openAPIContent(&openAPISchema{Ref: "#/components/schemas/GatewayError"}),
+					},
+					"401": map[string]interface{}{"description": "Caller is not authenticated"},
+					"403": map[string]interface{}{"description": "Caller is not allowed to call the operation for the provider"},
+					"500": map[string]interface{}{"description": "Internal server error"},
+				},
+			},
+		}
+	}
+
+	doc, _ := json.Marshal(map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":   ServiceName,
+			"version": Version,
+		},
+		"paths":    paths,
+		"security": []map[string][]string{{"apiKey": {}}},
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"apiKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": APIKeyHeader},
+			},
+		},
+	})
+
+	return doc
+}
+
+func openAPIContent(schema *openAPISchema) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// openAPIMessageSchema describes the fields of a message by their names in mfa.proto.
+func openAPIMessageSchema(t reflect.Type, schemas map[string]*openAPISchema) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.HasPrefix(f.Name, "XXX_") {
+			continue
+		}
+
+		name := f.Name
+		for _, opt := range strings.Split(f.Tag.Get("protobuf"), ",") {
+			if strings.HasPrefix(opt, "name=") {
+				name = strings.TrimPrefix(opt, "name=")
+			}
+		}
+		schema.Properties[name] = openAPIFieldSchema(f.Type, schemas)
+	}
+
+	return schema
+}
+
+// openAPIFieldSchema follows the protobuf JSON mapping, so 64-bit integers are strings.
+func openAPIFieldSchema(t reflect.Type, schemas map[string]*openAPISchema) *openAPISchema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		name := t.Elem().Name()
+		if _, ok := schemas[name]; !ok {
+			// Registered before walking the fields to stop on recursive messages.
+			schemas[name] = nil
+			schemas[name] = openAPIMessageSchema(t.Elem(), schemas)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: openAPIFieldSchema(t.Elem(), schemas)}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int32, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "string", Format: "int64"}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	}
+
+	return &openAPISchema{Type: "string"}
+}
@@ -0,0 +1,188 @@
+package mfa
+
+import (
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"time"
+)
+
+const (
+	defaultTrustedDeviceTTL        = 30 * 24 * time.Hour
+	defaultAssertionTTL            = 5 * time.Minute
+	defaultSigningKeyRotation      = 24 * time.Hour
+	defaultAccountRecoveryDelay    = 72 * time.Hour
+	defaultAccountRecoveryReminder = 24 * time.Hour
+	defaultSecretRotationGrace     = 7 * 24 * time.Hour
+)
+
+type Options struct {
+	// TrustedDeviceSecret signs remember-device tokens, tokens are not issued without it.
+	TrustedDeviceSecret []byte
+	// TrustedDeviceTTL is the lifetime of remember-device tokens.
+	TrustedDeviceTTL time.Duration
+	// AssertionIssuer is the "iss" claim of step-up assertions.
+	AssertionIssuer string
+	// AssertionTTL is the lifetime of step-up assertions.
+	AssertionTTL time.Duration
+	// SigningKeyRotation is how long a signing key is used before a new one is generated.
+	SigningKeyRotation time.Duration
+	// SigningKeySecret encrypts the signing keys in the storage, they are stored unencrypted without it.
+	SigningKeySecret []byte
+	// AuditSink receives audit events, events are kept in Redis by default.
+	AuditSink AuditSink
+	// Outbox publishes domain events to the broker, events are not published without it.
+	Outbox *Outbox
+	// ProviderConfigs are used for providers without a config in the storage.
+	ProviderConfigs map[string]*proto.ProviderConfig
+	// AccountRecoveryDelay is the waiting period before a requested account recovery resets the enrollment.
+	AccountRecoveryDelay time.Duration
+	// AccountRecoveryReminder is how often the user is reminded of a pending account recovery.
+	AccountRecoveryReminder time.Duration
+	// Notifier delivers account recovery notifications, they are only logged by default.
+	Notifier Notifier
+	// SecretRotationGrace is how long the old secret of a device is accepted after rotation.
+	SecretRotationGrace time.Duration
+	// ClockSkewThreshold is the largest accepted difference between the local and the storage time.
+	ClockSkewThreshold time.Duration
+	// ClockSkewPolicy decides whether Check fails closed while the skew exceeds the threshold.
+	ClockSkewPolicy string
+	// LowRecoveryCodes is the number of remaining recovery codes below which a user is reported by the metrics.
+	LowRecoveryCodes int
+}
+
+type Option func(*Options)
+
+func newOptions(opts ...Option) Options {
+	opt := Options{
+		TrustedDeviceTTL:        defaultTrustedDeviceTTL,
+		AssertionIssuer:         ServiceName,
+		AssertionTTL:            defaultAssertionTTL,
+		SigningKeyRotation:      defaultSigningKeyRotation,
+		ProviderConfigs:         map[string]*proto.ProviderConfig{},
+		AccountRecoveryDelay:    defaultAccountRecoveryDelay,
+		AccountRecoveryReminder: defaultAccountRecoveryReminder,
+		SecretRotationGrace:     defaultSecretRotationGrace,
+		ClockSkewThreshold:      defaultClockSkewThreshold,
+		ClockSkewPolicy:         ClockSkewPolicyFailClosed,
+		LowRecoveryCodes:        defaultLowRecoveryCodes,
+	}
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	return opt
+}
+
+// TrustedDeviceSecret sets the key used to sign remember-device tokens.
+func TrustedDeviceSecret(secret []byte) Option {
+	return func(o *Options) {
+		o.TrustedDeviceSecret = secret
+	}
+}
+
+// TrustedDeviceTTL sets the default lifetime of remember-device tokens.
+func TrustedDeviceTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.TrustedDeviceTTL = ttl
+	}
+}
+
+// AssertionIssuer sets the issuer of step-up assertions.
+func AssertionIssuer(issuer string) Option {
+	return func(o *Options) {
+		o.AssertionIssuer = issuer
+	}
+}
+
+// AssertionTTL sets the lifetime of step-up assertions.
+func AssertionTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.AssertionTTL = ttl
+	}
+}
+
+// SigningKeyRotation sets how often the assertion signing key is rotated.
+func SigningKeyRotation(interval time.Duration) Option {
+	return func(o *Options) {
+		o.SigningKeyRotation = interval
+	}
+}
+
+// SigningKeySecret sets the key the signing keys are encrypted with in the storage.
+func SigningKeySecret(secret []byte) Option {
+	return func(o *Options) {
+		o.SigningKeySecret = secret
+	}
+}
+
+// Audit sets the sink of audit events.
+func Audit(sink AuditSink) Option {
+	return func(o *Options) {
+		o.AuditSink = sink
+	}
+}
+
+// EventOutbox sets the outbox used to publish domain events.
+func EventOutbox(outbox *Outbox) Option {
+	return func(o *Options) {
+		o.Outbox = outbox
+	}
+}
+
+// ProviderConfigs sets the provider configs used when the storage has none.
+func ProviderConfigs(configs ...*proto.ProviderConfig) Option {
+	return func(o *Options) {
+		for _, c := range configs {
+			o.ProviderConfigs[c.ProviderID] = c
+		}
+	}
+}
+
+// AccountRecoveryDelay sets the waiting period of account recovery.
+func AccountRecoveryDelay(delay time.Duration) Option {
+	return func(o *Options) {
+		o.AccountRecoveryDelay = delay
+	}
+}
+
+// AccountRecoveryReminder sets how often the user is reminded of a pending account recovery.
+func AccountRecoveryReminder(interval time.Duration) Option {
+	return func(o *Options) {
+		o.AccountRecoveryReminder = interval
+	}
+}
+
+// RecoveryNotifier sets the notifier of account recovery.
+func RecoveryNotifier(notifier Notifier) Option {
+	return func(o *Options) {
+		o.Notifier = notifier
+	}
+}
+
+// SecretRotationGrace sets how long the old secret of a device is accepted after rotation.
+func SecretRotationGrace(grace time.Duration) Option {
+	return func(o *Options) {
+		o.SecretRotationGrace = grace
+	}
+}
+
+// ClockSkewThreshold sets the largest accepted clock skew against the storage.
+func ClockSkewThreshold(threshold time.Duration) Option {
+	return func(o *Options) {
+		o.ClockSkewThreshold = threshold
+	}
+}
+
+// ClockSkewPolicy sets what Check does while the clock skew exceeds the threshold.
+func ClockSkewPolicy(policy string) Option {
+	return func(o *Options) {
+		o.ClockSkewPolicy = policy
+	}
+}
+
+// LowRecoveryCodes sets the number of remaining recovery codes below which a user is reported by the metrics.
+func LowRecoveryCodes(count int) Option {
+	return func(o *Options) {
+		o.LowRecoveryCodes = count
+	}
+}
@@ -0,0 +1,353 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/micro/go-micro"
+	"go.uber.org/zap"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	mfaOutboxStorage                  = "mfa_outbox"
+	mfaOutboxProcessingStoragePattern = "mfa_outbox_processing_%s"
+	mfaOutboxLeaseStoragePattern      = "mfa_outbox_lease_%s"
+	mfaOutboxInstancesStorage         = "mfa_outbox_instances"
+	outboxPollTimeout                 = time.Second
+	outboxMinBackoff                  = 100 * time.Millisecond
+	outboxMaxBackoff                  = 30 * time.Second
+	outboxUnhealthyAfter              = time.Minute
+	// outboxLeaseTTL is how long the messages in processing of an instance are
+	// left to it after its last lease renewal.
+	outboxLeaseTTL = 30 * time.Second
+	// outboxMaxPending bounds the messages waiting in the outbox.
+	outboxMaxPending = 100000
+	outboxIDSize     = 8
+)
+
+var ErrOutboxFull = errors.New("outbox is full")
+
+// Outbox stores messages in Redis before they are published to the broker,
+// so a message is not lost while the broker is unavailable.
+//
+// Messages are delivered at least once: every instance moves the message it
+// publishes to its own processing list and renews a lease while it runs, the
+// list of an instance whose lease expired is returned to the outbox, so a
+// message may be published again by another instance. Messages may be lost:
+// they are enqueued after the change they report is stored, and Enqueue fails
+// when Redis is unavailable or outboxMaxPending messages are waiting.
+type Outbox struct {
+	redis      *redis.Client
+	logger     *zap.Logger
+	publishers map[string]micro.Publisher
+	// id names the processing list and the lease of the instance.
+	id         string
+	maxPending int64
+
+	mu           sync.Mutex
+	failingSince time.Time
+	lastError    error
+}
+
+// OutboxStatus is the result of the broker health check.
+type OutboxStatus struct {
+	Pending      int64  `json:"pending"`
+	FailingSince int64  `json:"failing_since,omitempty"`
+	LastError    string `json:"last_error,omitempty"`
+}
+
+type outboxMessage struct {
+	Type    string `json:"type"`
+	Payload []byte `json:"payload"`
+}
+
+func NewOutbox(redis *redis.Client, logger *zap.Logger) *Outbox {
+	id := make([]byte, outboxIDSize)
+	_, _ = rand.Read(id)
+
+	return &Outbox{
+		redis:      redis,
+		logger:     logger,
+		publishers: map[string]micro.Publisher{},
+		id:         hex.EncodeToString(id),
+		maxPending: outboxMaxPending,
+	}
+}
+
+// Register sets the publisher for messages of the same type as msg.
+func (o *Outbox) Register(msg protobuf.Message, publisher micro.Publisher) {
+	o.publishers[protobuf.MessageName(msg)] = publisher
+}
+
+// Enqueue stores the message in the outbox. Messages without a registered
+// publisher are dropped, ErrOutboxFull is returned while outboxMaxPending
+// messages are waiting.
+func (o *Outbox) Enqueue(msg protobuf.Message) error {
+	name := protobuf.MessageName(msg)
+	if _, ok := o.publishers[name]; !ok {
+		return nil
+	}
+
+	payload, err := protobuf.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&outboxMessage{Type: name, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	pending, err := o.redis.LLen(mfaOutboxStorage).Result()
+	if err != nil {
+		return err
+	}
+	if pending >= o.maxPending {
+		outboxDropped.Inc()
+		return ErrOutboxFull
+	}
+
+	return o.redis.LPush(mfaOutboxStorage, data).Err()
+}
+
+// Run relays messages from the outbox to the broker until the context is done.
+// It renews the lease of the instance and returns the messages of instances
+// whose lease expired to the outbox every third of outboxLeaseTTL.
+func (o *Outbox) Run(ctx context.Context) {
+	var renewed time.Time
+
+	backoff := outboxMinBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if time.Since(renewed) >= outboxLeaseTTL/3 {
+			if err := o.renewLease(); err != nil {
+				o.logger.Error("Renewing outbox lease failed with error", zap.Error(err))
+			} else {
+				renewed = time.Now()
+				o.reclaim()
+			}
+		}
+
+		processed, err := o.relay(ctx, outboxPollTimeout)
+		o.track(err)
+		if err != nil {
+			o.logger.Error("Relaying outbox message failed with error", zap.Error(err), zap.Duration("retry", backoff))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			if backoff *= 2; backoff > outboxMaxBackoff {
+				backoff = outboxMaxBackoff
+			}
+			continue
+		}
+
+		if processed {
+			backoff = outboxMinBackoff
+		}
+	}
+}
+
+// Flush publishes the messages left in the outbox, it stops on the first failure.
+func (o *Outbox) Flush(ctx context.Context) error {
+	for {
+		processed, err := o.relay(ctx, 0)
+		if err != nil || !processed {
+			o.track(err)
+			return err
+		}
+	}
+}
+
+// relay moves one message to the processing list of the instance, publishes it
+// and removes it from the list. A message failed to publish is returned to the
+// outbox.
+func (o *Outbox) relay(ctx context.Context, timeout time.Duration) (bool, error) {
+	processing := o.processingStorage(o.id)
+
+	var data string
+	var err error
+	if timeout > 0 {
+		data, err = o.redis.BRPopLPush(mfaOutboxStorage, processing, timeout).Result()
+	} else {
+		data, err = o.redis.RPopLPush(mfaOutboxStorage, processing).Result()
+	}
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err = o.publish(ctx, data); err != nil {
+		_, rerr := o.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.LRem(processing, 1, data)
+			pipe.RPush(mfaOutboxStorage, data)
+			return nil
+		})
+		if rerr != nil {
+			o.logger.Error("Returning message to outbox failed with error", zap.Error(rerr))
+		}
+		return false, err
+	}
+
+	return true, o.redis.LRem(processing, 1, data).Err()
+}
+
+// publish sends the message to the broker. Messages which can not be decoded
+// are dropped, they would never be published.
+func (o *Outbox) publish(ctx context.Context, data string) error {
+	m := &outboxMessage{}
+	if err := json.Unmarshal([]byte(data), m); err != nil {
+		o.logger.Error("Dropping malformed outbox message", zap.Error(err))
+
+		return nil
+	}
+
+	publisher, ok := o.publishers[m.Type]
+	if !ok {
+		o.logger.Warn("Dropping outbox message without publisher", zap.String("type", m.Type))
+
+		return nil
+	}
+
+	t := protobuf.MessageType(m.Type)
+	if t == nil {
+		o.logger.Error("Dropping outbox message of unknown type", zap.String("type", m.Type))
+
+		return nil
+	}
+
+	msg := reflect.New(t.Elem()).Interface().(protobuf.Message)
+	if err := protobuf.Unmarshal(m.Payload, msg); err != nil {
+		o.logger.Error("Dropping malformed outbox message", zap.String("type", m.Type), zap.Error(err))
+
+		return nil
+	}
+
+	return publisher.Publish(ctx, msg)
+}
+
+// Status reports the messages waiting in the outbox. It fails once publishing
+// has been failing for longer than a minute, messages are kept until then.
+func (o *Outbox) Status() (*OutboxStatus, error) {
+	pending, err := o.redis.LLen(mfaOutboxStorage).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	status := &OutboxStatus{Pending: pending}
+	if o.lastError == nil {
+		return status, nil
+	}
+
+	status.FailingSince = o.failingSince.Unix()
+	status.LastError = o.lastError.Error()
+	if time.Since(o.failingSince) > outboxUnhealthyAfter {
+		return status, fmt.Errorf("publishing to the broker is failing since %s", o.failingSince.Format(time.RFC3339))
+	}
+
+	return status, nil
+}
+
+// track records the result of the last relay for Status.
+func (o *Outbox) track(err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err == nil {
+		o.lastError = nil
+		return
+	}
+	if o.lastError == nil {
+		o.failingSince = time.Now()
+	}
+	o.lastError = err
+}
+
+func (o *Outbox) processingStorage(id string) string {
+	return fmt.Sprintf(mfaOutboxProcessingStoragePattern, id)
+}
+
+func (o *Outbox) leaseStorage(id string) string {
+	return fmt.Sprintf(mfaOutboxLeaseStoragePattern, id)
+}
+
+// renewLease registers the instance and extends its lease by outboxLeaseTTL.
+func (o *Outbox) renewLease() error {
+	_, err := o.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(mfaOutboxInstancesStorage, o.id)
+		pipe.Set(o.leaseStorage(o.id), time.Now().Unix(), outboxLeaseTTL)
+		return nil
+	})
+
+	return err
+}
+
+// reclaim returns the messages in processing of the instances whose lease
+// expired to the outbox, an instance which stopped or lost Redis for longer
+// than outboxLeaseTTL.
+func (o *Outbox) reclaim() {
+	ids, err := o.redis.SMembers(mfaOutboxInstancesStorage).Result()
+	if err != nil {
+		o.logger.Error("Reclaiming outbox messages failed with error", zap.Error(err))
+
+		return
+	}
+
+	for _, id := range ids {
+		if id == o.id {
+			continue
+		}
+
+		alive, err := o.redis.Exists(o.leaseStorage(id)).Result()
+		if err != nil {
+			o.logger.Error("Reclaiming outbox messages failed with error", zap.Error(err))
+
+			return
+		}
+		if alive > 0 {
+			continue
+		}
+
+		count := 0
+		for {
+			err = o.redis.RPopLPush(o.processingStorage(id), mfaOutboxStorage).Err()
+			if err != nil {
+				break
+			}
+			count++
+		}
+		if err != redis.Nil {
+			o.logger.Error("Reclaiming outbox messages failed with error", zap.Error(err), zap.String("instance", id))
+
+			continue
+		}
+
+		if err = o.redis.SRem(mfaOutboxInstancesStorage, id).Err(); err != nil {
+			o.logger.Error("Removing outbox instance failed with error", zap.Error(err), zap.String("instance", id))
+		}
+		if count > 0 {
+			o.logger.Warn("Outbox messages of a stopped instance returned to the outbox", zap.String("instance", id), zap.Int("count", count))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/micro/go-micro/client"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"time"
+)
+
+type testPublisher struct {
+	err      error
+	messages []interface{}
+}
+
+func (p *testPublisher) Publish(ctx context.Context, msg interface{}, opts ...client.PublishOption) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func (suite *ServiceTestSuite) TestOutboxToPublishDomainEvents() {
+	enrolled := &testPublisher{}
+	removed := &testPublisher{}
+	outbox := NewOutbox(suite.redis, zap.L())
+	outbox.Register(&proto.MfaEnrolled{}, enrolled)
+	outbox.Register(&proto.MfaRemoved{}, removed)
+
+	suite.service = NewService(suite.redis, zap.L(), EventOutbox(outbox))
+	res1 := suite.createDevice("")
+	_ = suite.service.RemoveDevice(context.TODO(), &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: res1.DeviceID}, &proto.MfaRemoveDeviceDataResponse{})
+
+	assert.NoError(suite.T(), outbox.Flush(context.TODO()))
+
+	assert.Equal(suite.T(), 1, len(enrolled.messages))
+	msg := enrolled.messages[0].(*proto.MfaEnrolled)
+	assert.Equal(suite.T(), suite.userID, msg.UserID)
+	assert.Equal(suite.T(), suite.ProviderID, msg.ProviderID)
+	assert.Equal(suite.T(), res1.DeviceID, msg.DeviceID)
+
+	assert.Equal(suite.T(), 1, len(removed.messages))
+	assert.Equal(suite.T(), res1.DeviceID, removed.messages[0].(*proto.MfaRemoved).DeviceID)
+}
+
+func (suite *ServiceTestSuite) TestOutboxToKeepEventsWhileBrokerUnavailable() {
+	publisher := &testPublisher{err: errors.New("broker unavailable")}
+	outbox := NewOutbox(suite.redis, zap.L())
+	outbox.Register(&proto.MfaEnrolled{}, publisher)
+
+	suite.service = NewService(suite.redis, zap.L(), EventOutbox(outbox))
+	suite.createDevice("")
+
+	assert.Error(suite.T(), outbox.Flush(context.TODO()))
+	assert.Empty(suite.T(), publisher.messages)
+
+	size, _ := suite.redis.LLen(mfaOutboxStorage).Result()
+	assert.Equal(suite.T(), int64(1), size)
+	size, _ = suite.redis.LLen(outbox.processingStorage(outbox.id)).Result()
+	assert.Equal(suite.T(), int64(0), size)
+
+	publisher.err = nil
+	assert.NoError(suite.T(), outbox.Flush(context.TODO()))
+	assert.Equal(suite.T(), 1, len(publisher.messages))
+
+	size, _ = suite.redis.LLen(mfaOutboxStorage).Result()
+	assert.Equal(suite.T(), int64(0), size)
+}
+
+func (suite *ServiceTestSuite) TestOutboxStatusToFailWhenBrokerUnavailableTooLong() {
+	publisher := &testPublisher{err: errors.New("broker unavailable")}
+	outbox := NewOutbox(suite.redis, zap.L())
+	outbox.Register(&proto.MfaEnrolled{}, publisher)
+
+	suite.service = NewService(suite.redis, zap.L(), EventOutbox(outbox))
+	suite.createDevice("")
+	assert.Error(suite.T(), outbox.Flush(context.TODO()))
+
+	status, err := outbox.Status()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), status.Pending)
+	assert.Equal(suite.T(), "broker unavailable", status.LastError)
+
+	outbox.failingSince = time.Now().Add(-outboxUnhealthyAfter - time.Second)
+	_, err = outbox.Status()
+	assert.Error(suite.T(), err)
+
+	publisher.err = nil
+	assert.NoError(suite.T(), outbox.Flush(context.TODO()))
+	status, err = outbox.Status()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), status.Pending)
+	assert.Empty(suite.T(), status.LastError)
+}
+
+func (suite *ServiceTestSuite) TestOutboxToReclaimMessagesOfExpiredInstances() {
+	stopped := NewOutbox(suite.redis, zap.L())
+	running := NewOutbox(suite.redis, zap.L())
+	for _, o := range []*Outbox{stopped, running} {
+		assert.NoError(suite.T(), o.renewLease())
+		assert.NoError(suite.T(), suite.redis.LPush(o.processingStorage(o.id), o.id).Err())
+		defer suite.redis.Del(o.processingStorage(o.id), o.leaseStorage(o.id))
+	}
+	assert.NoError(suite.T(), suite.redis.Del(stopped.leaseStorage(stopped.id)).Err())
+
+	outbox := NewOutbox(suite.redis, zap.L())
+	outbox.reclaim()
+
+	messages, err := suite.redis.LRange(mfaOutboxStorage, 0, -1).Result()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{stopped.id}, messages)
+
+	size, _ := suite.redis.LLen(running.processingStorage(running.id)).Result()
+	assert.Equal(suite.T(), int64(1), size)
+
+	ids, err := suite.redis.SMembers(mfaOutboxInstancesStorage).Result()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{running.id}, ids)
+}
+
+func (suite *ServiceTestSuite) TestOutboxEnqueueToFailWhenFull() {
+	outbox := NewOutbox(suite.redis, zap.L())
+	outbox.Register(&proto.MfaEnrolled{}, &testPublisher{})
+	outbox.maxPending = 1
+
+	dropped := testutil.ToFloat64(outboxDropped)
+	assert.NoError(suite.T(), outbox.Enqueue(&proto.MfaEnrolled{UserID: suite.userID}))
+	assert.Equal(suite.T(), ErrOutboxFull, outbox.Enqueue(&proto.MfaEnrolled{UserID: suite.userID}))
+	assert.Equal(suite.T(), dropped+1, testutil.ToFloat64(outboxDropped))
+
+	size, _ := suite.redis.LLen(mfaOutboxStorage).Result()
+	assert.Equal(suite.T(), int64(1), size)
+}
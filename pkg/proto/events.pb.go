@@ -0,0 +1,675 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: events.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type MfaEnrolled struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Method               string   `protobuf:"bytes,3,opt,name=Method,proto3" json:"Method,omitempty"`
+	DeviceID             string   `protobuf:"bytes,4,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,5,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaEnrolled) Reset()         { *m = MfaEnrolled{} }
+func (m *MfaEnrolled) String() string { return proto.CompactTextString(m) }
+func (*MfaEnrolled) ProtoMessage()    {}
+func (*MfaEnrolled) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{0}
+}
+func (m *MfaEnrolled) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaEnrolled.Unmarshal(m, b)
+}
+func (m *MfaEnrolled) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaEnrolled.Marshal(b, m, deterministic)
+}
+func (dst *MfaEnrolled) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaEnrolled.Merge(dst, src)
+}
+func (m *MfaEnrolled) XXX_Size() int {
+	return xxx_messageInfo_MfaEnrolled.Size(m)
+}
+func (m *MfaEnrolled) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaEnrolled.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaEnrolled proto.InternalMessageInfo
+
+func (m *MfaEnrolled) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaEnrolled) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaEnrolled) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *MfaEnrolled) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *MfaEnrolled) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type MfaRemoved struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Method               string   `protobuf:"bytes,3,opt,name=Method,proto3" json:"Method,omitempty"`
+	DeviceID             string   `protobuf:"bytes,4,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,5,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRemoved) Reset()         { *m = MfaRemoved{} }
+func (m *MfaRemoved) String() string { return proto.CompactTextString(m) }
+func (*MfaRemoved) ProtoMessage()    {}
+func (*MfaRemoved) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{1}
+}
+func (m *MfaRemoved) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRemoved.Unmarshal(m, b)
+}
+func (m *MfaRemoved) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRemoved.Marshal(b, m, deterministic)
+}
+func (dst *MfaRemoved) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRemoved.Merge(dst, src)
+}
+func (m *MfaRemoved) XXX_Size() int {
+	return xxx_messageInfo_MfaRemoved.Size(m)
+}
+func (m *MfaRemoved) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRemoved.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRemoved proto.InternalMessageInfo
+
+func (m *MfaRemoved) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaRemoved) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaRemoved) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *MfaRemoved) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *MfaRemoved) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type RecoveryCodeUsed struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,3,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecoveryCodeUsed) Reset()         { *m = RecoveryCodeUsed{} }
+func (m *RecoveryCodeUsed) String() string { return proto.CompactTextString(m) }
+func (*RecoveryCodeUsed) ProtoMessage()    {}
+func (*RecoveryCodeUsed) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{2}
+}
+func (m *RecoveryCodeUsed) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecoveryCodeUsed.Unmarshal(m, b)
+}
+func (m *RecoveryCodeUsed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecoveryCodeUsed.Marshal(b, m, deterministic)
+}
+func (dst *RecoveryCodeUsed) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecoveryCodeUsed.Merge(dst, src)
+}
+func (m *RecoveryCodeUsed) XXX_Size() int {
+	return xxx_messageInfo_RecoveryCodeUsed.Size(m)
+}
+func (m *RecoveryCodeUsed) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecoveryCodeUsed.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecoveryCodeUsed proto.InternalMessageInfo
+
+func (m *RecoveryCodeUsed) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *RecoveryCodeUsed) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *RecoveryCodeUsed) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type VerificationFailed struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Method               string   `protobuf:"bytes,3,opt,name=Method,proto3" json:"Method,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,5,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerificationFailed) Reset()         { *m = VerificationFailed{} }
+func (m *VerificationFailed) String() string { return proto.CompactTextString(m) }
+func (*VerificationFailed) ProtoMessage()    {}
+func (*VerificationFailed) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{3}
+}
+func (m *VerificationFailed) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerificationFailed.Unmarshal(m, b)
+}
+func (m *VerificationFailed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerificationFailed.Marshal(b, m, deterministic)
+}
+func (dst *VerificationFailed) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerificationFailed.Merge(dst, src)
+}
+func (m *VerificationFailed) XXX_Size() int {
+	return xxx_messageInfo_VerificationFailed.Size(m)
+}
+func (m *VerificationFailed) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerificationFailed.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerificationFailed proto.InternalMessageInfo
+
+func (m *VerificationFailed) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *VerificationFailed) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *VerificationFailed) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *VerificationFailed) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *VerificationFailed) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type LockedOut struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Until                int64    `protobuf:"varint,3,opt,name=Until,proto3" json:"Until,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,4,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LockedOut) Reset()         { *m = LockedOut{} }
+func (m *LockedOut) String() string { return proto.CompactTextString(m) }
+func (*LockedOut) ProtoMessage()    {}
+func (*LockedOut) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{4}
+}
+func (m *LockedOut) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockedOut.Unmarshal(m, b)
+}
+func (m *LockedOut) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockedOut.Marshal(b, m, deterministic)
+}
+func (dst *LockedOut) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockedOut.Merge(dst, src)
+}
+func (m *LockedOut) XXX_Size() int {
+	return xxx_messageInfo_LockedOut.Size(m)
+}
+func (m *LockedOut) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockedOut.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockedOut proto.InternalMessageInfo
+
+func (m *LockedOut) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *LockedOut) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *LockedOut) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *LockedOut) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type BypassCodeIssued struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Actor                string   `protobuf:"bytes,3,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	ExpiresAt            int64    `protobuf:"varint,5,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,6,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BypassCodeIssued) Reset()         { *m = BypassCodeIssued{} }
+func (m *BypassCodeIssued) String() string { return proto.CompactTextString(m) }
+func (*BypassCodeIssued) ProtoMessage()    {}
+func (*BypassCodeIssued) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{5}
+}
+func (m *BypassCodeIssued) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BypassCodeIssued.Unmarshal(m, b)
+}
+func (m *BypassCodeIssued) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BypassCodeIssued.Marshal(b, m, deterministic)
+}
+func (dst *BypassCodeIssued) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BypassCodeIssued.Merge(dst, src)
+}
+func (m *BypassCodeIssued) XXX_Size() int {
+	return xxx_messageInfo_BypassCodeIssued.Size(m)
+}
+func (m *BypassCodeIssued) XXX_DiscardUnknown() {
+	xxx_messageInfo_BypassCodeIssued.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BypassCodeIssued proto.InternalMessageInfo
+
+func (m *BypassCodeIssued) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *BypassCodeIssued) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *BypassCodeIssued) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *BypassCodeIssued) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *BypassCodeIssued) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *BypassCodeIssued) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type BypassCodeUsed struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,3,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BypassCodeUsed) Reset()         { *m = BypassCodeUsed{} }
+func (m *BypassCodeUsed) String() string { return proto.CompactTextString(m) }
+func (*BypassCodeUsed) ProtoMessage()    {}
+func (*BypassCodeUsed) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{6}
+}
+func (m *BypassCodeUsed) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BypassCodeUsed.Unmarshal(m, b)
+}
+func (m *BypassCodeUsed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BypassCodeUsed.Marshal(b, m, deterministic)
+}
+func (dst *BypassCodeUsed) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BypassCodeUsed.Merge(dst, src)
+}
+func (m *BypassCodeUsed) XXX_Size() int {
+	return xxx_messageInfo_BypassCodeUsed.Size(m)
+}
+func (m *BypassCodeUsed) XXX_DiscardUnknown() {
+	xxx_messageInfo_BypassCodeUsed.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BypassCodeUsed proto.InternalMessageInfo
+
+func (m *BypassCodeUsed) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *BypassCodeUsed) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *BypassCodeUsed) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type RecoveryNotification struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,3,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	CompletesAt          int64    `protobuf:"varint,4,opt,name=CompletesAt,proto3" json:"CompletesAt,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,5,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecoveryNotification) Reset()         { *m = RecoveryNotification{} }
+func (m *RecoveryNotification) String() string { return proto.CompactTextString(m) }
+func (*RecoveryNotification) ProtoMessage()    {}
+func (*RecoveryNotification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{7}
+}
+func (m *RecoveryNotification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecoveryNotification.Unmarshal(m, b)
+}
+func (m *RecoveryNotification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecoveryNotification.Marshal(b, m, deterministic)
+}
+func (dst *RecoveryNotification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecoveryNotification.Merge(dst, src)
+}
+func (m *RecoveryNotification) XXX_Size() int {
+	return xxx_messageInfo_RecoveryNotification.Size(m)
+}
+func (m *RecoveryNotification) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecoveryNotification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecoveryNotification proto.InternalMessageInfo
+
+func (m *RecoveryNotification) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *RecoveryNotification) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *RecoveryNotification) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *RecoveryNotification) GetCompletesAt() int64 {
+	if m != nil {
+		return m.CompletesAt
+	}
+	return 0
+}
+
+func (m *RecoveryNotification) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type UserDeleted struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserDeleted) Reset()         { *m = UserDeleted{} }
+func (m *UserDeleted) String() string { return proto.CompactTextString(m) }
+func (*UserDeleted) ProtoMessage()    {}
+func (*UserDeleted) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{8}
+}
+func (m *UserDeleted) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserDeleted.Unmarshal(m, b)
+}
+func (m *UserDeleted) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserDeleted.Marshal(b, m, deterministic)
+}
+func (dst *UserDeleted) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserDeleted.Merge(dst, src)
+}
+func (m *UserDeleted) XXX_Size() int {
+	return xxx_messageInfo_UserDeleted.Size(m)
+}
+func (m *UserDeleted) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserDeleted.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserDeleted proto.InternalMessageInfo
+
+func (m *UserDeleted) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+type UserMerged struct {
+	SourceUserID         string   `protobuf:"bytes,1,opt,name=SourceUserID,proto3" json:"SourceUserID,omitempty"`
+	TargetUserID         string   `protobuf:"bytes,2,opt,name=TargetUserID,proto3" json:"TargetUserID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserMerged) Reset()         { *m = UserMerged{} }
+func (m *UserMerged) String() string { return proto.CompactTextString(m) }
+func (*UserMerged) ProtoMessage()    {}
+func (*UserMerged) Descriptor() ([]byte, []int) {
+	return fileDescriptor_events_48d7c35f7ba48150, []int{9}
+}
+func (m *UserMerged) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserMerged.Unmarshal(m, b)
+}
+func (m *UserMerged) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserMerged.Marshal(b, m, deterministic)
+}
+func (dst *UserMerged) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserMerged.Merge(dst, src)
+}
+func (m *UserMerged) XXX_Size() int {
+	return xxx_messageInfo_UserMerged.Size(m)
+}
+func (m *UserMerged) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserMerged.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserMerged proto.InternalMessageInfo
+
+func (m *UserMerged) GetSourceUserID() string {
+	if m != nil {
+		return m.SourceUserID
+	}
+	return ""
+}
+
+func (m *UserMerged) GetTargetUserID() string {
+	if m != nil {
+		return m.TargetUserID
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*MfaEnrolled)(nil), "proto.MfaEnrolled")
+	proto.RegisterType((*MfaRemoved)(nil), "proto.MfaRemoved")
+	proto.RegisterType((*RecoveryCodeUsed)(nil), "proto.RecoveryCodeUsed")
+	proto.RegisterType((*VerificationFailed)(nil), "proto.VerificationFailed")
+	proto.RegisterType((*LockedOut)(nil), "proto.LockedOut")
+	proto.RegisterType((*BypassCodeIssued)(nil), "proto.BypassCodeIssued")
+	proto.RegisterType((*BypassCodeUsed)(nil), "proto.BypassCodeUsed")
+	proto.RegisterType((*RecoveryNotification)(nil), "proto.RecoveryNotification")
+	proto.RegisterType((*UserDeleted)(nil), "proto.UserDeleted")
+	proto.RegisterType((*UserMerged)(nil), "proto.UserMerged")
+}
+
+func init() { proto.RegisterFile("events.proto", fileDescriptor_events_48d7c35f7ba48150) }
+
+var fileDescriptor_events_48d7c35f7ba48150 = []byte{
+	// 400 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0x5d, 0x8b, 0xda, 0x40,
+	0x14, 0x65, 0x8c, 0x09, 0xf5, 0x2a, 0x45, 0x06, 0x91, 0x50, 0x4a, 0x91, 0x40, 0xc1, 0xa7, 0xbe,
+	0xf4, 0x17, 0x58, 0x63, 0x41, 0x68, 0xda, 0x92, 0x6a, 0xdf, 0xa7, 0xc9, 0x8d, 0x0e, 0x8d, 0x99,
+	0x30, 0x33, 0x66, 0xd7, 0x3f, 0xb2, 0x0b, 0xfb, 0xb8, 0xbf, 0x61, 0x7f, 0xe0, 0x92, 0x8f, 0x55,
+	0xe3, 0xb2, 0x0a, 0x2b, 0xc2, 0x3e, 0x65, 0xce, 0xb9, 0x77, 0x38, 0x67, 0xce, 0xbd, 0x04, 0x3a,
+	0x98, 0x61, 0xa2, 0xd5, 0x97, 0x54, 0x0a, 0x2d, 0xa8, 0x59, 0x7c, 0x9c, 0x5b, 0x02, 0x6d, 0x2f,
+	0x62, 0x93, 0x44, 0x8a, 0x38, 0xc6, 0x90, 0xf6, 0xc1, 0x9a, 0x2b, 0x94, 0x53, 0xd7, 0x26, 0x03,
+	0x32, 0x6c, 0xf9, 0x15, 0xa2, 0x9f, 0x00, 0x7e, 0x4b, 0x91, 0xf1, 0xb0, 0xa8, 0x35, 0x8a, 0xda,
+	0x1e, 0x93, 0xdf, 0xf3, 0x50, 0x2f, 0x45, 0x68, 0x1b, 0xe5, 0xbd, 0x12, 0xd1, 0x0f, 0xf0, 0xce,
+	0xc5, 0x8c, 0x07, 0x38, 0x75, 0xed, 0x66, 0x51, 0xd9, 0x62, 0xfa, 0x11, 0x5a, 0x63, 0x89, 0x4c,
+	0x63, 0x38, 0xd2, 0xb6, 0x39, 0x20, 0x43, 0xc3, 0xdf, 0x11, 0xce, 0x0d, 0x01, 0xf0, 0x22, 0xe6,
+	0xe3, 0x4a, 0x64, 0x6f, 0xca, 0xd8, 0x12, 0xba, 0x3e, 0x06, 0x22, 0x43, 0xb9, 0x19, 0x8b, 0x10,
+	0xe7, 0xea, 0x0c, 0x77, 0x35, 0x25, 0xe3, 0x50, 0xe9, 0x8e, 0x00, 0xfd, 0x8b, 0x92, 0x47, 0x3c,
+	0x60, 0x9a, 0x8b, 0xe4, 0x3b, 0xe3, 0x97, 0x98, 0x51, 0x1f, 0x2c, 0x1f, 0x99, 0x12, 0x49, 0x15,
+	0x44, 0x85, 0x4e, 0xc4, 0x70, 0x05, 0xad, 0x1f, 0x22, 0xf8, 0x8f, 0xe1, 0xaf, 0xb5, 0x7e, 0xb5,
+	0xa5, 0x1e, 0x98, 0xf3, 0x44, 0xf3, 0xb8, 0x7a, 0x7b, 0x09, 0xea, 0xc2, 0xcd, 0x43, 0xe1, 0x07,
+	0x02, 0xdd, 0x6f, 0x9b, 0x94, 0x29, 0x95, 0xc7, 0x3f, 0x55, 0x6a, 0x7d, 0x46, 0x26, 0x3d, 0x30,
+	0x47, 0x81, 0x16, 0xb2, 0x8a, 0xa4, 0x04, 0xc7, 0x12, 0x99, 0x5c, 0xa7, 0x5c, 0xa2, 0xda, 0x25,
+	0xb2, 0x25, 0xea, 0xb6, 0xad, 0x43, 0xdb, 0x11, 0xbc, 0xdf, 0xb9, 0xbe, 0xe0, 0xd2, 0xdc, 0x13,
+	0xe8, 0x3d, 0xed, 0xe7, 0x4f, 0xa1, 0xb7, 0xcb, 0x43, 0x29, 0x34, 0x67, 0x9b, 0x14, 0x2b, 0xb1,
+	0xe2, 0xbc, 0x67, 0xa1, 0x71, 0xc4, 0x82, 0xf1, 0xcc, 0xc2, 0x00, 0xda, 0x63, 0xb1, 0x4a, 0x63,
+	0xd4, 0x45, 0x14, 0xe5, 0x8c, 0xf6, 0xa9, 0x13, 0xcb, 0xf3, 0x19, 0xda, 0xb9, 0x92, 0x8b, 0x79,
+	0xfb, 0x8b, 0x49, 0x38, 0x33, 0x80, 0xfc, 0xe4, 0xa1, 0x5c, 0x60, 0x48, 0x1d, 0xe8, 0xfc, 0x11,
+	0x6b, 0x19, 0x60, 0xad, 0xb7, 0xc6, 0xe5, 0x3d, 0x33, 0x26, 0x17, 0xa8, 0x6b, 0xcf, 0xaa, 0x71,
+	0xff, 0xac, 0xe2, 0xd7, 0xf7, 0xf5, 0x71, 0x00, 0xde, 0xf1, 0x5d, 0xd4, 0x11, 0x05, 0x00, 0x00,
+}
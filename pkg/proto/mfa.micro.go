@@ -12,6 +12,62 @@ It has these top-level messages:
 	MfaCreateDataResponse
 	MfaCheckDataRequest
 	MfaCheckDataResponse
+	MfaAddYubiKeyDataRequest
+	MfaAddYubiKeyDataResponse
+	MfaListDevicesDataRequest
+	MfaListDevicesDataResponse
+	MfaRenameDeviceDataRequest
+	MfaRenameDeviceDataResponse
+	MfaRemoveDeviceDataRequest
+	MfaRemoveDeviceDataResponse
+	Device
+	MfaValidateTrustedDeviceDataRequest
+	MfaValidateTrustedDeviceDataResponse
+	MfaListTrustedDevicesDataRequest
+	MfaListTrustedDevicesDataResponse
+	MfaRevokeTrustedDeviceDataRequest
+	MfaRevokeTrustedDeviceDataResponse
+	TrustedDevice
+	MfaQueryAuditEventsDataRequest
+	MfaQueryAuditEventsDataResponse
+	AuditEvent
+	ProviderConfig
+	MfaGetProviderConfigDataRequest
+	MfaGetProviderConfigDataResponse
+	MfaSetProviderConfigDataRequest
+	MfaSetProviderConfigDataResponse
+	MfaListProviderConfigsDataRequest
+	MfaListProviderConfigsDataResponse
+	MfaDeleteProviderConfigDataRequest
+	MfaDeleteProviderConfigDataResponse
+	MfaGetUserStatusDataRequest
+	MfaGetUserStatusDataResponse
+	MfaResetEnrollmentDataRequest
+	MfaResetEnrollmentDataResponse
+	MfaClearLockoutDataRequest
+	MfaClearLockoutDataResponse
+	MfaIssueBypassCodeDataRequest
+	MfaIssueBypassCodeDataResponse
+	MfaRequestAccountRecoveryDataRequest
+	MfaRequestAccountRecoveryDataResponse
+	MfaRotateSecretDataRequest
+	MfaRotateSecretDataResponse
+	MfaImportEnrollmentDataRequest
+	MfaImportEnrollmentDataResponse
+	ImportRecord
+	ImportResult
+	MfaExportEnrollmentsDataRequest
+	ArchiveChunk
+	MfaRestoreEnrollmentsDataRequest
+	MfaRestoreEnrollmentsDataResponse
+	Enrollment
+	EnrollmentDevice
+	EnrollmentYubiKey
+	RestoreResult
+	MfaImportMigrationDataRequest
+	MfaImportMigrationDataResponse
+	MfaExportMigrationDataRequest
+	MfaExportMigrationDataResponse
 	Error
 */
 package proto
@@ -47,6 +103,29 @@ var _ server.Option
 type MfaService interface {
 	Create(ctx context.Context, in *MfaCreateDataRequest, opts ...client.CallOption) (*MfaCreateDataResponse, error)
 	Check(ctx context.Context, in *MfaCheckDataRequest, opts ...client.CallOption) (*MfaCheckDataResponse, error)
+	AddYubiKey(ctx context.Context, in *MfaAddYubiKeyDataRequest, opts ...client.CallOption) (*MfaAddYubiKeyDataResponse, error)
+	ListDevices(ctx context.Context, in *MfaListDevicesDataRequest, opts ...client.CallOption) (*MfaListDevicesDataResponse, error)
+	RenameDevice(ctx context.Context, in *MfaRenameDeviceDataRequest, opts ...client.CallOption) (*MfaRenameDeviceDataResponse, error)
+	RemoveDevice(ctx context.Context, in *MfaRemoveDeviceDataRequest, opts ...client.CallOption) (*MfaRemoveDeviceDataResponse, error)
+	ValidateTrustedDevice(ctx context.Context, in *MfaValidateTrustedDeviceDataRequest, opts ...client.CallOption) (*MfaValidateTrustedDeviceDataResponse, error)
+	ListTrustedDevices(ctx context.Context, in *MfaListTrustedDevicesDataRequest, opts ...client.CallOption) (*MfaListTrustedDevicesDataResponse, error)
+	RevokeTrustedDevice(ctx context.Context, in *MfaRevokeTrustedDeviceDataRequest, opts ...client.CallOption) (*MfaRevokeTrustedDeviceDataResponse, error)
+	QueryAuditEvents(ctx context.Context, in *MfaQueryAuditEventsDataRequest, opts ...client.CallOption) (*MfaQueryAuditEventsDataResponse, error)
+	GetProviderConfig(ctx context.Context, in *MfaGetProviderConfigDataRequest, opts ...client.CallOption) (*MfaGetProviderConfigDataResponse, error)
+	SetProviderConfig(ctx context.Context, in *MfaSetProviderConfigDataRequest, opts ...client.CallOption) (*MfaSetProviderConfigDataResponse, error)
+	ListProviderConfigs(ctx context.Context, in *MfaListProviderConfigsDataRequest, opts ...client.CallOption) (*MfaListProviderConfigsDataResponse, error)
+	DeleteProviderConfig(ctx context.Context, in *MfaDeleteProviderConfigDataRequest, opts ...client.CallOption) (*MfaDeleteProviderConfigDataResponse, error)
+	GetUserStatus(ctx context.Context, in *MfaGetUserStatusDataRequest, opts ...client.CallOption) (*MfaGetUserStatusDataResponse, error)
+	ResetEnrollment(ctx context.Context, in *MfaResetEnrollmentDataRequest, opts ...client.CallOption) (*MfaResetEnrollmentDataResponse, error)
+	ClearLockout(ctx context.Context, in *MfaClearLockoutDataRequest, opts ...client.CallOption) (*MfaClearLockoutDataResponse, error)
+	IssueBypassCode(ctx context.Context, in *MfaIssueBypassCodeDataRequest, opts ...client.CallOption) (*MfaIssueBypassCodeDataResponse, error)
+	RequestAccountRecovery(ctx context.Context, in *MfaRequestAccountRecoveryDataRequest, opts ...client.CallOption) (*MfaRequestAccountRecoveryDataResponse, error)
+	RotateSecret(ctx context.Context, in *MfaRotateSecretDataRequest, opts ...client.CallOption) (*MfaRotateSecretDataResponse, error)
+	ImportEnrollment(ctx context.Context, in *MfaImportEnrollmentDataRequest, opts ...client.CallOption) (*MfaImportEnrollmentDataResponse, error)
+	ExportEnrollments(ctx context.Context, in *MfaExportEnrollmentsDataRequest, opts ...client.CallOption) (MfaService_ExportEnrollmentsService, error)
+	RestoreEnrollments(ctx context.Context, in *MfaRestoreEnrollmentsDataRequest, opts ...client.CallOption) (*MfaRestoreEnrollmentsDataResponse, error)
+	ImportMigration(ctx context.Context, in *MfaImportMigrationDataRequest, opts ...client.CallOption) (*MfaImportMigrationDataResponse, error)
+	ExportMigration(ctx context.Context, in *MfaExportMigrationDataRequest, opts ...client.CallOption) (*MfaExportMigrationDataResponse, error)
 }
 
 type mfaService struct {
@@ -87,17 +166,327 @@ func (c *mfaService) Check(ctx context.Context, in *MfaCheckDataRequest, opts ..
 	return out, nil
 }
 
+func (c *mfaService) AddYubiKey(ctx context.Context, in *MfaAddYubiKeyDataRequest, opts ...client.CallOption) (*MfaAddYubiKeyDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.AddYubiKey", in)
+	out := new(MfaAddYubiKeyDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ListDevices(ctx context.Context, in *MfaListDevicesDataRequest, opts ...client.CallOption) (*MfaListDevicesDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ListDevices", in)
+	out := new(MfaListDevicesDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) RenameDevice(ctx context.Context, in *MfaRenameDeviceDataRequest, opts ...client.CallOption) (*MfaRenameDeviceDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.RenameDevice", in)
+	out := new(MfaRenameDeviceDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) RemoveDevice(ctx context.Context, in *MfaRemoveDeviceDataRequest, opts ...client.CallOption) (*MfaRemoveDeviceDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.RemoveDevice", in)
+	out := new(MfaRemoveDeviceDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ValidateTrustedDevice(ctx context.Context, in *MfaValidateTrustedDeviceDataRequest, opts ...client.CallOption) (*MfaValidateTrustedDeviceDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ValidateTrustedDevice", in)
+	out := new(MfaValidateTrustedDeviceDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ListTrustedDevices(ctx context.Context, in *MfaListTrustedDevicesDataRequest, opts ...client.CallOption) (*MfaListTrustedDevicesDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ListTrustedDevices", in)
+	out := new(MfaListTrustedDevicesDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) RevokeTrustedDevice(ctx context.Context, in *MfaRevokeTrustedDeviceDataRequest, opts ...client.CallOption) (*MfaRevokeTrustedDeviceDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.RevokeTrustedDevice", in)
+	out := new(MfaRevokeTrustedDeviceDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) QueryAuditEvents(ctx context.Context, in *MfaQueryAuditEventsDataRequest, opts ...client.CallOption) (*MfaQueryAuditEventsDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.QueryAuditEvents", in)
+	out := new(MfaQueryAuditEventsDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) GetProviderConfig(ctx context.Context, in *MfaGetProviderConfigDataRequest, opts ...client.CallOption) (*MfaGetProviderConfigDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.GetProviderConfig", in)
+	out := new(MfaGetProviderConfigDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) SetProviderConfig(ctx context.Context, in *MfaSetProviderConfigDataRequest, opts ...client.CallOption) (*MfaSetProviderConfigDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.SetProviderConfig", in)
+	out := new(MfaSetProviderConfigDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ListProviderConfigs(ctx context.Context, in *MfaListProviderConfigsDataRequest, opts ...client.CallOption) (*MfaListProviderConfigsDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ListProviderConfigs", in)
+	out := new(MfaListProviderConfigsDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) DeleteProviderConfig(ctx context.Context, in *MfaDeleteProviderConfigDataRequest, opts ...client.CallOption) (*MfaDeleteProviderConfigDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.DeleteProviderConfig", in)
+	out := new(MfaDeleteProviderConfigDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) GetUserStatus(ctx context.Context, in *MfaGetUserStatusDataRequest, opts ...client.CallOption) (*MfaGetUserStatusDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.GetUserStatus", in)
+	out := new(MfaGetUserStatusDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ResetEnrollment(ctx context.Context, in *MfaResetEnrollmentDataRequest, opts ...client.CallOption) (*MfaResetEnrollmentDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ResetEnrollment", in)
+	out := new(MfaResetEnrollmentDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ClearLockout(ctx context.Context, in *MfaClearLockoutDataRequest, opts ...client.CallOption) (*MfaClearLockoutDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ClearLockout", in)
+	out := new(MfaClearLockoutDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) IssueBypassCode(ctx context.Context, in *MfaIssueBypassCodeDataRequest, opts ...client.CallOption) (*MfaIssueBypassCodeDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.IssueBypassCode", in)
+	out := new(MfaIssueBypassCodeDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) RequestAccountRecovery(ctx context.Context, in *MfaRequestAccountRecoveryDataRequest, opts ...client.CallOption) (*MfaRequestAccountRecoveryDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.RequestAccountRecovery", in)
+	out := new(MfaRequestAccountRecoveryDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) RotateSecret(ctx context.Context, in *MfaRotateSecretDataRequest, opts ...client.CallOption) (*MfaRotateSecretDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.RotateSecret", in)
+	out := new(MfaRotateSecretDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ImportEnrollment(ctx context.Context, in *MfaImportEnrollmentDataRequest, opts ...client.CallOption) (*MfaImportEnrollmentDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ImportEnrollment", in)
+	out := new(MfaImportEnrollmentDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ExportEnrollments(ctx context.Context, in *MfaExportEnrollmentsDataRequest, opts ...client.CallOption) (MfaService_ExportEnrollmentsService, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ExportEnrollments", &MfaExportEnrollmentsDataRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &mfaServiceExportEnrollments{stream}, nil
+}
+
+type MfaService_ExportEnrollmentsService interface {
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*ArchiveChunk, error)
+}
+
+type mfaServiceExportEnrollments struct {
+	stream client.Stream
+}
+
+func (x *mfaServiceExportEnrollments) Close() error {
+	return x.stream.Close()
+}
+
+func (x *mfaServiceExportEnrollments) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *mfaServiceExportEnrollments) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *mfaServiceExportEnrollments) Recv() (*ArchiveChunk, error) {
+	m := new(ArchiveChunk)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mfaService) RestoreEnrollments(ctx context.Context, in *MfaRestoreEnrollmentsDataRequest, opts ...client.CallOption) (*MfaRestoreEnrollmentsDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.RestoreEnrollments", in)
+	out := new(MfaRestoreEnrollmentsDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ImportMigration(ctx context.Context, in *MfaImportMigrationDataRequest, opts ...client.CallOption) (*MfaImportMigrationDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ImportMigration", in)
+	out := new(MfaImportMigrationDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaService) ExportMigration(ctx context.Context, in *MfaExportMigrationDataRequest, opts ...client.CallOption) (*MfaExportMigrationDataResponse, error) {
+	req := c.c.NewRequest(c.name, "MfaService.ExportMigration", in)
+	out := new(MfaExportMigrationDataResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for MfaService service
 
 type MfaServiceHandler interface {
 	Create(context.Context, *MfaCreateDataRequest, *MfaCreateDataResponse) error
 	Check(context.Context, *MfaCheckDataRequest, *MfaCheckDataResponse) error
+	AddYubiKey(context.Context, *MfaAddYubiKeyDataRequest, *MfaAddYubiKeyDataResponse) error
+	ListDevices(context.Context, *MfaListDevicesDataRequest, *MfaListDevicesDataResponse) error
+	RenameDevice(context.Context, *MfaRenameDeviceDataRequest, *MfaRenameDeviceDataResponse) error
+	RemoveDevice(context.Context, *MfaRemoveDeviceDataRequest, *MfaRemoveDeviceDataResponse) error
+	ValidateTrustedDevice(context.Context, *MfaValidateTrustedDeviceDataRequest, *MfaValidateTrustedDeviceDataResponse) error
+	ListTrustedDevices(context.Context, *MfaListTrustedDevicesDataRequest, *MfaListTrustedDevicesDataResponse) error
+	RevokeTrustedDevice(context.Context, *MfaRevokeTrustedDeviceDataRequest, *MfaRevokeTrustedDeviceDataResponse) error
+	QueryAuditEvents(context.Context, *MfaQueryAuditEventsDataRequest, *MfaQueryAuditEventsDataResponse) error
+	GetProviderConfig(context.Context, *MfaGetProviderConfigDataRequest, *MfaGetProviderConfigDataResponse) error
+	SetProviderConfig(context.Context, *MfaSetProviderConfigDataRequest, *MfaSetProviderConfigDataResponse) error
+	ListProviderConfigs(context.Context, *MfaListProviderConfigsDataRequest, *MfaListProviderConfigsDataResponse) error
+	DeleteProviderConfig(context.Context, *MfaDeleteProviderConfigDataRequest, *MfaDeleteProviderConfigDataResponse) error
+	GetUserStatus(context.Context, *MfaGetUserStatusDataRequest, *MfaGetUserStatusDataResponse) error
+	ResetEnrollment(context.Context, *MfaResetEnrollmentDataRequest, *MfaResetEnrollmentDataResponse) error
+	ClearLockout(context.Context, *MfaClearLockoutDataRequest, *MfaClearLockoutDataResponse) error
+	IssueBypassCode(context.Context, *MfaIssueBypassCodeDataRequest, *MfaIssueBypassCodeDataResponse) error
+	RequestAccountRecovery(context.Context, *MfaRequestAccountRecoveryDataRequest, *MfaRequestAccountRecoveryDataResponse) error
+	RotateSecret(context.Context, *MfaRotateSecretDataRequest, *MfaRotateSecretDataResponse) error
+	ImportEnrollment(context.Context, *MfaImportEnrollmentDataRequest, *MfaImportEnrollmentDataResponse) error
+	ExportEnrollments(context.Context, *MfaExportEnrollmentsDataRequest, MfaService_ExportEnrollmentsStream) error
+	RestoreEnrollments(context.Context, *MfaRestoreEnrollmentsDataRequest, *MfaRestoreEnrollmentsDataResponse) error
+	ImportMigration(context.Context, *MfaImportMigrationDataRequest, *MfaImportMigrationDataResponse) error
+	ExportMigration(context.Context, *MfaExportMigrationDataRequest, *MfaExportMigrationDataResponse) error
 }
 
 func RegisterMfaServiceHandler(s server.Server, hdlr MfaServiceHandler, opts ...server.HandlerOption) error {
 	type mfaService interface {
 		Create(ctx context.Context, in *MfaCreateDataRequest, out *MfaCreateDataResponse) error
 		Check(ctx context.Context, in *MfaCheckDataRequest, out *MfaCheckDataResponse) error
+		AddYubiKey(ctx context.Context, in *MfaAddYubiKeyDataRequest, out *MfaAddYubiKeyDataResponse) error
+		ListDevices(ctx context.Context, in *MfaListDevicesDataRequest, out *MfaListDevicesDataResponse) error
+		RenameDevice(ctx context.Context, in *MfaRenameDeviceDataRequest, out *MfaRenameDeviceDataResponse) error
+		RemoveDevice(ctx context.Context, in *MfaRemoveDeviceDataRequest, out *MfaRemoveDeviceDataResponse) error
+		ValidateTrustedDevice(ctx context.Context, in *MfaValidateTrustedDeviceDataRequest, out *MfaValidateTrustedDeviceDataResponse) error
+		ListTrustedDevices(ctx context.Context, in *MfaListTrustedDevicesDataRequest, out *MfaListTrustedDevicesDataResponse) error
+		RevokeTrustedDevice(ctx context.Context, in *MfaRevokeTrustedDeviceDataRequest, out *MfaRevokeTrustedDeviceDataResponse) error
+		QueryAuditEvents(ctx context.Context, in *MfaQueryAuditEventsDataRequest, out *MfaQueryAuditEventsDataResponse) error
+		GetProviderConfig(ctx context.Context, in *MfaGetProviderConfigDataRequest, out *MfaGetProviderConfigDataResponse) error
+		SetProviderConfig(ctx context.Context, in *MfaSetProviderConfigDataRequest, out *MfaSetProviderConfigDataResponse) error
+		ListProviderConfigs(ctx context.Context, in *MfaListProviderConfigsDataRequest, out *MfaListProviderConfigsDataResponse) error
+		DeleteProviderConfig(ctx context.Context, in *MfaDeleteProviderConfigDataRequest, out *MfaDeleteProviderConfigDataResponse) error
+		GetUserStatus(ctx context.Context, in *MfaGetUserStatusDataRequest, out *MfaGetUserStatusDataResponse) error
+		ResetEnrollment(ctx context.Context, in *MfaResetEnrollmentDataRequest, out *MfaResetEnrollmentDataResponse) error
+		ClearLockout(ctx context.Context, in *MfaClearLockoutDataRequest, out *MfaClearLockoutDataResponse) error
+		IssueBypassCode(ctx context.Context, in *MfaIssueBypassCodeDataRequest, out *MfaIssueBypassCodeDataResponse) error
+		RequestAccountRecovery(ctx context.Context, in *MfaRequestAccountRecoveryDataRequest, out *MfaRequestAccountRecoveryDataResponse) error
+		RotateSecret(ctx context.Context, in *MfaRotateSecretDataRequest, out *MfaRotateSecretDataResponse) error
+		ImportEnrollment(ctx context.Context, in *MfaImportEnrollmentDataRequest, out *MfaImportEnrollmentDataResponse) error
+		ExportEnrollments(ctx context.Context, stream server.Stream) error
+		RestoreEnrollments(ctx context.Context, in *MfaRestoreEnrollmentsDataRequest, out *MfaRestoreEnrollmentsDataResponse) error
+		ImportMigration(ctx context.Context, in *MfaImportMigrationDataRequest, out *MfaImportMigrationDataResponse) error
+		ExportMigration(ctx context.Context, in *MfaExportMigrationDataRequest, out *MfaExportMigrationDataResponse) error
 	}
 	type MfaService struct {
 		mfaService
@@ -117,3 +506,126 @@ func (h *mfaServiceHandler) Create(ctx context.Context, in *MfaCreateDataRequest
 func (h *mfaServiceHandler) Check(ctx context.Context, in *MfaCheckDataRequest, out *MfaCheckDataResponse) error {
 	return h.MfaServiceHandler.Check(ctx, in, out)
 }
+
+func (h *mfaServiceHandler) AddYubiKey(ctx context.Context, in *MfaAddYubiKeyDataRequest, out *MfaAddYubiKeyDataResponse) error {
+	return h.MfaServiceHandler.AddYubiKey(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ListDevices(ctx context.Context, in *MfaListDevicesDataRequest, out *MfaListDevicesDataResponse) error {
+	return h.MfaServiceHandler.ListDevices(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) RenameDevice(ctx context.Context, in *MfaRenameDeviceDataRequest, out *MfaRenameDeviceDataResponse) error {
+	return h.MfaServiceHandler.RenameDevice(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) RemoveDevice(ctx context.Context, in *MfaRemoveDeviceDataRequest, out *MfaRemoveDeviceDataResponse) error {
+	return h.MfaServiceHandler.RemoveDevice(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ValidateTrustedDevice(ctx context.Context, in *MfaValidateTrustedDeviceDataRequest, out *MfaValidateTrustedDeviceDataResponse) error {
+	return h.MfaServiceHandler.ValidateTrustedDevice(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ListTrustedDevices(ctx context.Context, in *MfaListTrustedDevicesDataRequest, out *MfaListTrustedDevicesDataResponse) error {
+	return h.MfaServiceHandler.ListTrustedDevices(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) RevokeTrustedDevice(ctx context.Context, in *MfaRevokeTrustedDeviceDataRequest, out *MfaRevokeTrustedDeviceDataResponse) error {
+	return h.MfaServiceHandler.RevokeTrustedDevice(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) QueryAuditEvents(ctx context.Context, in *MfaQueryAuditEventsDataRequest, out *MfaQueryAuditEventsDataResponse) error {
+	return h.MfaServiceHandler.QueryAuditEvents(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) GetProviderConfig(ctx context.Context, in *MfaGetProviderConfigDataRequest, out *MfaGetProviderConfigDataResponse) error {
+	return h.MfaServiceHandler.GetProviderConfig(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) SetProviderConfig(ctx context.Context, in *MfaSetProviderConfigDataRequest, out *MfaSetProviderConfigDataResponse) error {
+	return h.MfaServiceHandler.SetProviderConfig(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ListProviderConfigs(ctx context.Context, in *MfaListProviderConfigsDataRequest, out *MfaListProviderConfigsDataResponse) error {
+	return h.MfaServiceHandler.ListProviderConfigs(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) DeleteProviderConfig(ctx context.Context, in *MfaDeleteProviderConfigDataRequest, out *MfaDeleteProviderConfigDataResponse) error {
+	return h.MfaServiceHandler.DeleteProviderConfig(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) GetUserStatus(ctx context.Context, in *MfaGetUserStatusDataRequest, out *MfaGetUserStatusDataResponse) error {
+	return h.MfaServiceHandler.GetUserStatus(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ResetEnrollment(ctx context.Context, in *MfaResetEnrollmentDataRequest, out *MfaResetEnrollmentDataResponse) error {
+	return h.MfaServiceHandler.ResetEnrollment(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ClearLockout(ctx context.Context, in *MfaClearLockoutDataRequest, out *MfaClearLockoutDataResponse) error {
+	return h.MfaServiceHandler.ClearLockout(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) IssueBypassCode(ctx context.Context, in *MfaIssueBypassCodeDataRequest, out *MfaIssueBypassCodeDataResponse) error {
+	return h.MfaServiceHandler.IssueBypassCode(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) RequestAccountRecovery(ctx context.Context, in *MfaRequestAccountRecoveryDataRequest, out *MfaRequestAccountRecoveryDataResponse) error {
+	return h.MfaServiceHandler.RequestAccountRecovery(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) RotateSecret(ctx context.Context, in *MfaRotateSecretDataRequest, out *MfaRotateSecretDataResponse) error {
+	return h.MfaServiceHandler.RotateSecret(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ImportEnrollment(ctx context.Context, in *MfaImportEnrollmentDataRequest, out *MfaImportEnrollmentDataResponse) error {
+	return h.MfaServiceHandler.ImportEnrollment(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ExportEnrollments(ctx context.Context, stream server.Stream) error {
+	m := new(MfaExportEnrollmentsDataRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.MfaServiceHandler.ExportEnrollments(ctx, m, &mfaServiceExportEnrollmentsStream{stream})
+}
+
+type MfaService_ExportEnrollmentsStream interface {
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*ArchiveChunk) error
+}
+
+type mfaServiceExportEnrollmentsStream struct {
+	stream server.Stream
+}
+
+func (x *mfaServiceExportEnrollmentsStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *mfaServiceExportEnrollmentsStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *mfaServiceExportEnrollmentsStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *mfaServiceExportEnrollmentsStream) Send(m *ArchiveChunk) error {
+	return x.stream.Send(m)
+}
+
+func (h *mfaServiceHandler) RestoreEnrollments(ctx context.Context, in *MfaRestoreEnrollmentsDataRequest, out *MfaRestoreEnrollmentsDataResponse) error {
+	return h.MfaServiceHandler.RestoreEnrollments(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ImportMigration(ctx context.Context, in *MfaImportMigrationDataRequest, out *MfaImportMigrationDataResponse) error {
+	return h.MfaServiceHandler.ImportMigration(ctx, in, out)
+}
+
+func (h *mfaServiceHandler) ExportMigration(ctx context.Context, in *MfaExportMigrationDataRequest, out *MfaExportMigrationDataResponse) error {
+	return h.MfaServiceHandler.ExportMigration(ctx, in, out)
+}
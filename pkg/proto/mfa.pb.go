@@ -7,6 +7,11 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
@@ -24,6 +29,7 @@ type MfaCreateDataRequest struct {
 	AppName              string   `protobuf:"bytes,3,opt,name=AppName,proto3" json:"AppName,omitempty"`
 	Email                string   `protobuf:"bytes,4,opt,name=Email,proto3" json:"Email,omitempty"`
 	QrSize               int32    `protobuf:"varint,5,opt,name=QrSize,proto3" json:"QrSize,omitempty"`
+	DeviceName           string   `protobuf:"bytes,6,opt,name=DeviceName,proto3" json:"DeviceName,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -33,7 +39,7 @@ func (m *MfaCreateDataRequest) Reset()         { *m = MfaCreateDataRequest{} }
 func (m *MfaCreateDataRequest) String() string { return proto.CompactTextString(m) }
 func (*MfaCreateDataRequest) ProtoMessage()    {}
 func (*MfaCreateDataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_mfa_8f3acb8e3f367f98, []int{0}
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{0}
 }
 func (m *MfaCreateDataRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MfaCreateDataRequest.Unmarshal(m, b)
@@ -88,12 +94,21 @@ func (m *MfaCreateDataRequest) GetQrSize() int32 {
 	return 0
 }
 
+func (m *MfaCreateDataRequest) GetDeviceName() string {
+	if m != nil {
+		return m.DeviceName
+	}
+	return ""
+}
+
 type MfaCreateDataResponse struct {
 	SecretKey            string   `protobuf:"bytes,1,opt,name=SecretKey,proto3" json:"SecretKey,omitempty"`
 	URL                  string   `protobuf:"bytes,2,opt,name=URL,proto3" json:"URL,omitempty"`
 	QrCodeURL            string   `protobuf:"bytes,3,opt,name=QrCodeURL,proto3" json:"QrCodeURL,omitempty"`
 	ImageBased           string   `protobuf:"bytes,4,opt,name=ImageBased,proto3" json:"ImageBased,omitempty"`
 	RecoveryCode         []string `protobuf:"bytes,5,rep,name=RecoveryCode,proto3" json:"RecoveryCode,omitempty"`
+	DeviceID             string   `protobuf:"bytes,6,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Error                *Error   `protobuf:"bytes,7,opt,name=Error,proto3" json:"Error,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -103,7 +118,7 @@ func (m *MfaCreateDataResponse) Reset()         { *m = MfaCreateDataResponse{} }
 func (m *MfaCreateDataResponse) String() string { return proto.CompactTextString(m) }
 func (*MfaCreateDataResponse) ProtoMessage()    {}
 func (*MfaCreateDataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_mfa_8f3acb8e3f367f98, []int{1}
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{1}
 }
 func (m *MfaCreateDataResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MfaCreateDataResponse.Unmarshal(m, b)
@@ -158,10 +173,28 @@ func (m *MfaCreateDataResponse) GetRecoveryCode() []string {
 	return nil
 }
 
+func (m *MfaCreateDataResponse) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *MfaCreateDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
 type MfaCheckDataRequest struct {
 	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
 	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
 	Code                 string   `protobuf:"bytes,3,opt,name=Code,proto3" json:"Code,omitempty"`
+	RememberDevice       bool     `protobuf:"varint,4,opt,name=RememberDevice,proto3" json:"RememberDevice,omitempty"`
+	Fingerprint          string   `protobuf:"bytes,5,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
+	TrustedDeviceName    string   `protobuf:"bytes,6,opt,name=TrustedDeviceName,proto3" json:"TrustedDeviceName,omitempty"`
+	Assertion            bool     `protobuf:"varint,7,opt,name=Assertion,proto3" json:"Assertion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -171,7 +204,7 @@ func (m *MfaCheckDataRequest) Reset()         { *m = MfaCheckDataRequest{} }
 func (m *MfaCheckDataRequest) String() string { return proto.CompactTextString(m) }
 func (*MfaCheckDataRequest) ProtoMessage()    {}
 func (*MfaCheckDataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_mfa_8f3acb8e3f367f98, []int{2}
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{2}
 }
 func (m *MfaCheckDataRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MfaCheckDataRequest.Unmarshal(m, b)
@@ -212,9 +245,42 @@ func (m *MfaCheckDataRequest) GetCode() string {
 	return ""
 }
 
+func (m *MfaCheckDataRequest) GetRememberDevice() bool {
+	if m != nil {
+		return m.RememberDevice
+	}
+	return false
+}
+
+func (m *MfaCheckDataRequest) GetFingerprint() string {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return ""
+}
+
+func (m *MfaCheckDataRequest) GetTrustedDeviceName() string {
+	if m != nil {
+		return m.TrustedDeviceName
+	}
+	return ""
+}
+
+func (m *MfaCheckDataRequest) GetAssertion() bool {
+	if m != nil {
+		return m.Assertion
+	}
+	return false
+}
+
 type MfaCheckDataResponse struct {
 	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
 	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	DeviceID             string   `protobuf:"bytes,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	DeviceName           string   `protobuf:"bytes,4,opt,name=DeviceName,proto3" json:"DeviceName,omitempty"`
+	TrustedDeviceToken   string   `protobuf:"bytes,5,opt,name=TrustedDeviceToken,proto3" json:"TrustedDeviceToken,omitempty"`
+	Method               string   `protobuf:"bytes,6,opt,name=Method,proto3" json:"Method,omitempty"`
+	Assertion            string   `protobuf:"bytes,7,opt,name=Assertion,proto3" json:"Assertion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -224,7 +290,7 @@ func (m *MfaCheckDataResponse) Reset()         { *m = MfaCheckDataResponse{} }
 func (m *MfaCheckDataResponse) String() string { return proto.CompactTextString(m) }
 func (*MfaCheckDataResponse) ProtoMessage()    {}
 func (*MfaCheckDataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_mfa_8f3acb8e3f367f98, []int{3}
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{3}
 }
 func (m *MfaCheckDataResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MfaCheckDataResponse.Unmarshal(m, b)
@@ -258,78 +324,4563 @@ func (m *MfaCheckDataResponse) GetError() *Error {
 	return nil
 }
 
-type Error struct {
-	Message              string   `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+func (m *MfaCheckDataResponse) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *MfaCheckDataResponse) GetDeviceName() string {
+	if m != nil {
+		return m.DeviceName
+	}
+	return ""
+}
+
+func (m *MfaCheckDataResponse) GetTrustedDeviceToken() string {
+	if m != nil {
+		return m.TrustedDeviceToken
+	}
+	return ""
+}
+
+func (m *MfaCheckDataResponse) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *MfaCheckDataResponse) GetAssertion() string {
+	if m != nil {
+		return m.Assertion
+	}
+	return ""
+}
+
+type MfaAddYubiKeyDataRequest struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string   `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	PublicID             string   `protobuf:"bytes,3,opt,name=PublicID,proto3" json:"PublicID,omitempty"`
+	PrivateID            string   `protobuf:"bytes,4,opt,name=PrivateID,proto3" json:"PrivateID,omitempty"`
+	AesKey               string   `protobuf:"bytes,5,opt,name=AesKey,proto3" json:"AesKey,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Error) Reset()         { *m = Error{} }
-func (m *Error) String() string { return proto.CompactTextString(m) }
-func (*Error) ProtoMessage()    {}
-func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_mfa_8f3acb8e3f367f98, []int{4}
+func (m *MfaAddYubiKeyDataRequest) Reset()         { *m = MfaAddYubiKeyDataRequest{} }
+func (m *MfaAddYubiKeyDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaAddYubiKeyDataRequest) ProtoMessage()    {}
+func (*MfaAddYubiKeyDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{4}
 }
-func (m *Error) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Error.Unmarshal(m, b)
+func (m *MfaAddYubiKeyDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaAddYubiKeyDataRequest.Unmarshal(m, b)
 }
-func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+func (m *MfaAddYubiKeyDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaAddYubiKeyDataRequest.Marshal(b, m, deterministic)
 }
-func (dst *Error) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Error.Merge(dst, src)
+func (dst *MfaAddYubiKeyDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaAddYubiKeyDataRequest.Merge(dst, src)
 }
-func (m *Error) XXX_Size() int {
-	return xxx_messageInfo_Error.Size(m)
+func (m *MfaAddYubiKeyDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaAddYubiKeyDataRequest.Size(m)
 }
-func (m *Error) XXX_DiscardUnknown() {
-	xxx_messageInfo_Error.DiscardUnknown(m)
+func (m *MfaAddYubiKeyDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaAddYubiKeyDataRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Error proto.InternalMessageInfo
+var xxx_messageInfo_MfaAddYubiKeyDataRequest proto.InternalMessageInfo
 
-func (m *Error) GetMessage() string {
+func (m *MfaAddYubiKeyDataRequest) GetUserID() string {
 	if m != nil {
-		return m.Message
+		return m.UserID
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*MfaCreateDataRequest)(nil), "proto.MfaCreateDataRequest")
-	proto.RegisterType((*MfaCreateDataResponse)(nil), "proto.MfaCreateDataResponse")
-	proto.RegisterType((*MfaCheckDataRequest)(nil), "proto.MfaCheckDataRequest")
-	proto.RegisterType((*MfaCheckDataResponse)(nil), "proto.MfaCheckDataResponse")
-	proto.RegisterType((*Error)(nil), "proto.Error")
+func (m *MfaAddYubiKeyDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaAddYubiKeyDataRequest) GetPublicID() string {
+	if m != nil {
+		return m.PublicID
+	}
+	return ""
+}
+
+func (m *MfaAddYubiKeyDataRequest) GetPrivateID() string {
+	if m != nil {
+		return m.PrivateID
+	}
+	return ""
+}
+
+func (m *MfaAddYubiKeyDataRequest) GetAesKey() string {
+	if m != nil {
+		return m.AesKey
+	}
+	return ""
+}
+
+type MfaAddYubiKeyDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaAddYubiKeyDataResponse) Reset()         { *m = MfaAddYubiKeyDataResponse{} }
+func (m *MfaAddYubiKeyDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaAddYubiKeyDataResponse) ProtoMessage()    {}
+func (*MfaAddYubiKeyDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{5}
+}
+func (m *MfaAddYubiKeyDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaAddYubiKeyDataResponse.Unmarshal(m, b)
+}
+func (m *MfaAddYubiKeyDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaAddYubiKeyDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaAddYubiKeyDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaAddYubiKeyDataResponse.Merge(dst, src)
+}
+func (m *MfaAddYubiKeyDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaAddYubiKeyDataResponse.Size(m)
+}
+func (m *MfaAddYubiKeyDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaAddYubiKeyDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaAddYubiKeyDataResponse proto.InternalMessageInfo
+
+func (m *MfaAddYubiKeyDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaAddYubiKeyDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaListDevicesDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaListDevicesDataRequest) Reset()         { *m = MfaListDevicesDataRequest{} }
+func (m *MfaListDevicesDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaListDevicesDataRequest) ProtoMessage()    {}
+func (*MfaListDevicesDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{6}
+}
+func (m *MfaListDevicesDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaListDevicesDataRequest.Unmarshal(m, b)
+}
+func (m *MfaListDevicesDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaListDevicesDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaListDevicesDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaListDevicesDataRequest.Merge(dst, src)
+}
+func (m *MfaListDevicesDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaListDevicesDataRequest.Size(m)
+}
+func (m *MfaListDevicesDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaListDevicesDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaListDevicesDataRequest proto.InternalMessageInfo
+
+func (m *MfaListDevicesDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaListDevicesDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+type MfaListDevicesDataResponse struct {
+	Devices              []*Device `protobuf:"bytes,1,rep,name=Devices,proto3" json:"Devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *MfaListDevicesDataResponse) Reset()         { *m = MfaListDevicesDataResponse{} }
+func (m *MfaListDevicesDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaListDevicesDataResponse) ProtoMessage()    {}
+func (*MfaListDevicesDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{7}
+}
+func (m *MfaListDevicesDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaListDevicesDataResponse.Unmarshal(m, b)
+}
+func (m *MfaListDevicesDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaListDevicesDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaListDevicesDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaListDevicesDataResponse.Merge(dst, src)
+}
+func (m *MfaListDevicesDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaListDevicesDataResponse.Size(m)
+}
+func (m *MfaListDevicesDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaListDevicesDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaListDevicesDataResponse proto.InternalMessageInfo
+
+func (m *MfaListDevicesDataResponse) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+type MfaRenameDeviceDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	DeviceID             string   `protobuf:"bytes,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Name                 string   `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRenameDeviceDataRequest) Reset()         { *m = MfaRenameDeviceDataRequest{} }
+func (m *MfaRenameDeviceDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaRenameDeviceDataRequest) ProtoMessage()    {}
+func (*MfaRenameDeviceDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{8}
+}
+func (m *MfaRenameDeviceDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRenameDeviceDataRequest.Unmarshal(m, b)
+}
+func (m *MfaRenameDeviceDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRenameDeviceDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaRenameDeviceDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRenameDeviceDataRequest.Merge(dst, src)
+}
+func (m *MfaRenameDeviceDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaRenameDeviceDataRequest.Size(m)
+}
+func (m *MfaRenameDeviceDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRenameDeviceDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRenameDeviceDataRequest proto.InternalMessageInfo
+
+func (m *MfaRenameDeviceDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaRenameDeviceDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaRenameDeviceDataRequest) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *MfaRenameDeviceDataRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type MfaRenameDeviceDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRenameDeviceDataResponse) Reset()         { *m = MfaRenameDeviceDataResponse{} }
+func (m *MfaRenameDeviceDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaRenameDeviceDataResponse) ProtoMessage()    {}
+func (*MfaRenameDeviceDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{9}
+}
+func (m *MfaRenameDeviceDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRenameDeviceDataResponse.Unmarshal(m, b)
+}
+func (m *MfaRenameDeviceDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRenameDeviceDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaRenameDeviceDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRenameDeviceDataResponse.Merge(dst, src)
+}
+func (m *MfaRenameDeviceDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaRenameDeviceDataResponse.Size(m)
+}
+func (m *MfaRenameDeviceDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRenameDeviceDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRenameDeviceDataResponse proto.InternalMessageInfo
+
+func (m *MfaRenameDeviceDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaRenameDeviceDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaRemoveDeviceDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	DeviceID             string   `protobuf:"bytes,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRemoveDeviceDataRequest) Reset()         { *m = MfaRemoveDeviceDataRequest{} }
+func (m *MfaRemoveDeviceDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaRemoveDeviceDataRequest) ProtoMessage()    {}
+func (*MfaRemoveDeviceDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{10}
+}
+func (m *MfaRemoveDeviceDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRemoveDeviceDataRequest.Unmarshal(m, b)
+}
+func (m *MfaRemoveDeviceDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRemoveDeviceDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaRemoveDeviceDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRemoveDeviceDataRequest.Merge(dst, src)
+}
+func (m *MfaRemoveDeviceDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaRemoveDeviceDataRequest.Size(m)
+}
+func (m *MfaRemoveDeviceDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRemoveDeviceDataRequest.DiscardUnknown(m)
 }
 
-func init() { proto.RegisterFile("mfa.proto", fileDescriptor_mfa_8f3acb8e3f367f98) }
-
-var fileDescriptor_mfa_8f3acb8e3f367f98 = []byte{
-	// 370 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0xcb, 0x4e, 0xc2, 0x40,
-	0x14, 0xb5, 0x40, 0xab, 0xbd, 0xb2, 0x30, 0x23, 0x92, 0x06, 0x88, 0xc1, 0x59, 0xb1, 0x62, 0x81,
-	0x5f, 0x20, 0x8f, 0x05, 0x51, 0x8c, 0x0c, 0xe1, 0x03, 0xc6, 0x72, 0xc1, 0x46, 0x6a, 0xeb, 0x4c,
-	0x21, 0xc1, 0x0f, 0xd1, 0x6f, 0xf0, 0x2f, 0xcd, 0x3c, 0x48, 0x0b, 0xb2, 0xea, 0x9c, 0x73, 0x6e,
-	0xef, 0x3d, 0x73, 0xee, 0x80, 0x1f, 0x2f, 0x79, 0x37, 0x15, 0x49, 0x96, 0x10, 0x57, 0x7f, 0xe8,
-	0xb7, 0x03, 0xb5, 0xc9, 0x92, 0x0f, 0x04, 0xf2, 0x0c, 0x87, 0x3c, 0xe3, 0x0c, 0x3f, 0x37, 0x28,
-	0x33, 0x52, 0x07, 0x6f, 0x2e, 0x51, 0x8c, 0x87, 0x81, 0xd3, 0x76, 0x3a, 0x3e, 0xb3, 0x88, 0xdc,
-	0x02, 0xbc, 0x88, 0x64, 0x1b, 0x2d, 0xb4, 0x56, 0xd2, 0x5a, 0x81, 0x21, 0x01, 0x9c, 0x3f, 0xa4,
-	0xe9, 0x33, 0x8f, 0x31, 0x28, 0x6b, 0x71, 0x0f, 0x49, 0x0d, 0xdc, 0x51, 0xcc, 0xa3, 0x75, 0x50,
-	0xd1, 0xbc, 0x01, 0x6a, 0xce, 0x54, 0xcc, 0xa2, 0x2f, 0x0c, 0xdc, 0xb6, 0xd3, 0x71, 0x99, 0x45,
-	0xf4, 0xd7, 0x81, 0x9b, 0x23, 0x63, 0x32, 0x4d, 0x3e, 0x24, 0x92, 0x16, 0xf8, 0x33, 0x0c, 0x05,
-	0x66, 0x8f, 0xb8, 0xb3, 0xe6, 0x72, 0x82, 0x5c, 0x41, 0x79, 0xce, 0x9e, 0xac, 0x31, 0x75, 0x54,
-	0xf5, 0x53, 0x31, 0x48, 0x16, 0xa8, 0x78, 0xe3, 0x29, 0x27, 0xd4, 0x7d, 0xc6, 0x31, 0x5f, 0x61,
-	0x9f, 0x4b, 0x5c, 0x58, 0x6b, 0x05, 0x86, 0x50, 0xa8, 0x32, 0x0c, 0x93, 0x2d, 0x8a, 0x9d, 0xfa,
-	0x25, 0x70, 0xdb, 0xe5, 0x8e, 0xcf, 0x0e, 0x38, 0xca, 0xe1, 0x5a, 0x59, 0x7d, 0xc3, 0xf0, 0xbd,
-	0x18, 0xe1, 0x61, 0x54, 0xce, 0xbf, 0xa8, 0xf2, 0x88, 0x4b, 0x07, 0x11, 0x13, 0xa8, 0xe8, 0x51,
-	0xc6, 0xab, 0x3e, 0x53, 0x66, 0xd6, 0x94, 0x8f, 0xb0, 0x61, 0xd4, 0xc1, 0x63, 0x28, 0x37, 0xeb,
-	0x4c, 0xf7, 0xbf, 0x60, 0x16, 0x11, 0x0a, 0xee, 0x48, 0x88, 0x44, 0xe8, 0xd6, 0x97, 0xbd, 0xaa,
-	0xd9, 0x7a, 0x57, 0x73, 0xcc, 0x48, 0xf4, 0xce, 0xd6, 0xa8, 0x9d, 0x4d, 0x50, 0x4a, 0xbe, 0x42,
-	0xeb, 0x72, 0x0f, 0x7b, 0x3f, 0x0e, 0xc0, 0x64, 0xc9, 0x67, 0x28, 0xb6, 0x51, 0x88, 0x64, 0x04,
-	0x9e, 0x59, 0x08, 0x69, 0xda, 0x86, 0xa7, 0xde, 0x4e, 0xa3, 0x75, 0x5a, 0x34, 0x96, 0xe9, 0x19,
-	0xe9, 0x83, 0xab, 0x6f, 0x42, 0x1a, 0x85, 0xc2, 0xa3, 0xf4, 0x1a, 0xcd, 0x93, 0xda, 0xbe, 0xc7,
-	0xab, 0xa7, 0xd5, 0xfb, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x7e, 0x9c, 0xfd, 0x06, 0xd3, 0x02,
-	0x00, 0x00,
+var xxx_messageInfo_MfaRemoveDeviceDataRequest proto.InternalMessageInfo
+
+func (m *MfaRemoveDeviceDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaRemoveDeviceDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaRemoveDeviceDataRequest) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+type MfaRemoveDeviceDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRemoveDeviceDataResponse) Reset()         { *m = MfaRemoveDeviceDataResponse{} }
+func (m *MfaRemoveDeviceDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaRemoveDeviceDataResponse) ProtoMessage()    {}
+func (*MfaRemoveDeviceDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{11}
+}
+func (m *MfaRemoveDeviceDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRemoveDeviceDataResponse.Unmarshal(m, b)
+}
+func (m *MfaRemoveDeviceDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRemoveDeviceDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaRemoveDeviceDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRemoveDeviceDataResponse.Merge(dst, src)
+}
+func (m *MfaRemoveDeviceDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaRemoveDeviceDataResponse.Size(m)
+}
+func (m *MfaRemoveDeviceDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRemoveDeviceDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRemoveDeviceDataResponse proto.InternalMessageInfo
+
+func (m *MfaRemoveDeviceDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaRemoveDeviceDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type Device struct {
+	ID        string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	CreatedAt int64  `protobuf:"varint,3,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	// Drift is the estimated clock drift of the authenticator in time steps.
+	Drift                int32    `protobuf:"varint,4,opt,name=Drift,proto3" json:"Drift,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Device) Reset()         { *m = Device{} }
+func (m *Device) String() string { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()    {}
+func (*Device) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{12}
+}
+func (m *Device) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Device.Unmarshal(m, b)
+}
+func (m *Device) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Device.Marshal(b, m, deterministic)
+}
+func (dst *Device) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Device.Merge(dst, src)
+}
+func (m *Device) XXX_Size() int {
+	return xxx_messageInfo_Device.Size(m)
+}
+func (m *Device) XXX_DiscardUnknown() {
+	xxx_messageInfo_Device.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Device proto.InternalMessageInfo
+
+func (m *Device) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *Device) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Device) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Device) GetDrift() int32 {
+	if m != nil {
+		return m.Drift
+	}
+	return 0
+}
+
+type MfaValidateTrustedDeviceDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Token                string   `protobuf:"bytes,3,opt,name=Token,proto3" json:"Token,omitempty"`
+	Fingerprint          string   `protobuf:"bytes,4,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaValidateTrustedDeviceDataRequest) Reset()         { *m = MfaValidateTrustedDeviceDataRequest{} }
+func (m *MfaValidateTrustedDeviceDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaValidateTrustedDeviceDataRequest) ProtoMessage()    {}
+func (*MfaValidateTrustedDeviceDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{13}
+}
+func (m *MfaValidateTrustedDeviceDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaValidateTrustedDeviceDataRequest.Unmarshal(m, b)
+}
+func (m *MfaValidateTrustedDeviceDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaValidateTrustedDeviceDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaValidateTrustedDeviceDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaValidateTrustedDeviceDataRequest.Merge(dst, src)
+}
+func (m *MfaValidateTrustedDeviceDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaValidateTrustedDeviceDataRequest.Size(m)
+}
+func (m *MfaValidateTrustedDeviceDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaValidateTrustedDeviceDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaValidateTrustedDeviceDataRequest proto.InternalMessageInfo
+
+func (m *MfaValidateTrustedDeviceDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaValidateTrustedDeviceDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaValidateTrustedDeviceDataRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *MfaValidateTrustedDeviceDataRequest) GetFingerprint() string {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return ""
+}
+
+type MfaValidateTrustedDeviceDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaValidateTrustedDeviceDataResponse) Reset()         { *m = MfaValidateTrustedDeviceDataResponse{} }
+func (m *MfaValidateTrustedDeviceDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaValidateTrustedDeviceDataResponse) ProtoMessage()    {}
+func (*MfaValidateTrustedDeviceDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{14}
+}
+func (m *MfaValidateTrustedDeviceDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaValidateTrustedDeviceDataResponse.Unmarshal(m, b)
+}
+func (m *MfaValidateTrustedDeviceDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaValidateTrustedDeviceDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaValidateTrustedDeviceDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaValidateTrustedDeviceDataResponse.Merge(dst, src)
+}
+func (m *MfaValidateTrustedDeviceDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaValidateTrustedDeviceDataResponse.Size(m)
+}
+func (m *MfaValidateTrustedDeviceDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaValidateTrustedDeviceDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaValidateTrustedDeviceDataResponse proto.InternalMessageInfo
+
+func (m *MfaValidateTrustedDeviceDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaValidateTrustedDeviceDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaListTrustedDevicesDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaListTrustedDevicesDataRequest) Reset()         { *m = MfaListTrustedDevicesDataRequest{} }
+func (m *MfaListTrustedDevicesDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaListTrustedDevicesDataRequest) ProtoMessage()    {}
+func (*MfaListTrustedDevicesDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{15}
+}
+func (m *MfaListTrustedDevicesDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaListTrustedDevicesDataRequest.Unmarshal(m, b)
+}
+func (m *MfaListTrustedDevicesDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaListTrustedDevicesDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaListTrustedDevicesDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaListTrustedDevicesDataRequest.Merge(dst, src)
+}
+func (m *MfaListTrustedDevicesDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaListTrustedDevicesDataRequest.Size(m)
+}
+func (m *MfaListTrustedDevicesDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaListTrustedDevicesDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaListTrustedDevicesDataRequest proto.InternalMessageInfo
+
+func (m *MfaListTrustedDevicesDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaListTrustedDevicesDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+type MfaListTrustedDevicesDataResponse struct {
+	Devices              []*TrustedDevice `protobuf:"bytes,1,rep,name=Devices,proto3" json:"Devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *MfaListTrustedDevicesDataResponse) Reset()         { *m = MfaListTrustedDevicesDataResponse{} }
+func (m *MfaListTrustedDevicesDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaListTrustedDevicesDataResponse) ProtoMessage()    {}
+func (*MfaListTrustedDevicesDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{16}
+}
+func (m *MfaListTrustedDevicesDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaListTrustedDevicesDataResponse.Unmarshal(m, b)
+}
+func (m *MfaListTrustedDevicesDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaListTrustedDevicesDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaListTrustedDevicesDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaListTrustedDevicesDataResponse.Merge(dst, src)
+}
+func (m *MfaListTrustedDevicesDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaListTrustedDevicesDataResponse.Size(m)
+}
+func (m *MfaListTrustedDevicesDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaListTrustedDevicesDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaListTrustedDevicesDataResponse proto.InternalMessageInfo
+
+func (m *MfaListTrustedDevicesDataResponse) GetDevices() []*TrustedDevice {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+type MfaRevokeTrustedDeviceDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ID                   string   `protobuf:"bytes,3,opt,name=ID,proto3" json:"ID,omitempty"`
+	All                  bool     `protobuf:"varint,4,opt,name=All,proto3" json:"All,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRevokeTrustedDeviceDataRequest) Reset()         { *m = MfaRevokeTrustedDeviceDataRequest{} }
+func (m *MfaRevokeTrustedDeviceDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaRevokeTrustedDeviceDataRequest) ProtoMessage()    {}
+func (*MfaRevokeTrustedDeviceDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{17}
+}
+func (m *MfaRevokeTrustedDeviceDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRevokeTrustedDeviceDataRequest.Unmarshal(m, b)
+}
+func (m *MfaRevokeTrustedDeviceDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRevokeTrustedDeviceDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaRevokeTrustedDeviceDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRevokeTrustedDeviceDataRequest.Merge(dst, src)
+}
+func (m *MfaRevokeTrustedDeviceDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaRevokeTrustedDeviceDataRequest.Size(m)
+}
+func (m *MfaRevokeTrustedDeviceDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRevokeTrustedDeviceDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRevokeTrustedDeviceDataRequest proto.InternalMessageInfo
+
+func (m *MfaRevokeTrustedDeviceDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaRevokeTrustedDeviceDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaRevokeTrustedDeviceDataRequest) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *MfaRevokeTrustedDeviceDataRequest) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
+type MfaRevokeTrustedDeviceDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRevokeTrustedDeviceDataResponse) Reset()         { *m = MfaRevokeTrustedDeviceDataResponse{} }
+func (m *MfaRevokeTrustedDeviceDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaRevokeTrustedDeviceDataResponse) ProtoMessage()    {}
+func (*MfaRevokeTrustedDeviceDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{18}
+}
+func (m *MfaRevokeTrustedDeviceDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRevokeTrustedDeviceDataResponse.Unmarshal(m, b)
+}
+func (m *MfaRevokeTrustedDeviceDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRevokeTrustedDeviceDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaRevokeTrustedDeviceDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRevokeTrustedDeviceDataResponse.Merge(dst, src)
+}
+func (m *MfaRevokeTrustedDeviceDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaRevokeTrustedDeviceDataResponse.Size(m)
+}
+func (m *MfaRevokeTrustedDeviceDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRevokeTrustedDeviceDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRevokeTrustedDeviceDataResponse proto.InternalMessageInfo
+
+func (m *MfaRevokeTrustedDeviceDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaRevokeTrustedDeviceDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type TrustedDevice struct {
+	ID                   string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	FingerprintBound     bool     `protobuf:"varint,3,opt,name=FingerprintBound,proto3" json:"FingerprintBound,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,4,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	ExpiresAt            int64    `protobuf:"varint,5,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	LastUsedAt           int64    `protobuf:"varint,6,opt,name=LastUsedAt,proto3" json:"LastUsedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrustedDevice) Reset()         { *m = TrustedDevice{} }
+func (m *TrustedDevice) String() string { return proto.CompactTextString(m) }
+func (*TrustedDevice) ProtoMessage()    {}
+func (*TrustedDevice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{19}
+}
+func (m *TrustedDevice) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrustedDevice.Unmarshal(m, b)
+}
+func (m *TrustedDevice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrustedDevice.Marshal(b, m, deterministic)
+}
+func (dst *TrustedDevice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrustedDevice.Merge(dst, src)
+}
+func (m *TrustedDevice) XXX_Size() int {
+	return xxx_messageInfo_TrustedDevice.Size(m)
+}
+func (m *TrustedDevice) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrustedDevice.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrustedDevice proto.InternalMessageInfo
+
+func (m *TrustedDevice) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *TrustedDevice) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TrustedDevice) GetFingerprintBound() bool {
+	if m != nil {
+		return m.FingerprintBound
+	}
+	return false
+}
+
+func (m *TrustedDevice) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *TrustedDevice) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *TrustedDevice) GetLastUsedAt() int64 {
+	if m != nil {
+		return m.LastUsedAt
+	}
+	return 0
+}
+
+type MfaQueryAuditEventsDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Types                []string `protobuf:"bytes,3,rep,name=Types,proto3" json:"Types,omitempty"`
+	From                 int64    `protobuf:"varint,4,opt,name=From,proto3" json:"From,omitempty"`
+	To                   int64    `protobuf:"varint,5,opt,name=To,proto3" json:"To,omitempty"`
+	Limit                int32    `protobuf:"varint,6,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaQueryAuditEventsDataRequest) Reset()         { *m = MfaQueryAuditEventsDataRequest{} }
+func (m *MfaQueryAuditEventsDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaQueryAuditEventsDataRequest) ProtoMessage()    {}
+func (*MfaQueryAuditEventsDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{20}
+}
+func (m *MfaQueryAuditEventsDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaQueryAuditEventsDataRequest.Unmarshal(m, b)
+}
+func (m *MfaQueryAuditEventsDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaQueryAuditEventsDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaQueryAuditEventsDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaQueryAuditEventsDataRequest.Merge(dst, src)
+}
+func (m *MfaQueryAuditEventsDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaQueryAuditEventsDataRequest.Size(m)
+}
+func (m *MfaQueryAuditEventsDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaQueryAuditEventsDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaQueryAuditEventsDataRequest proto.InternalMessageInfo
+
+func (m *MfaQueryAuditEventsDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaQueryAuditEventsDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaQueryAuditEventsDataRequest) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+func (m *MfaQueryAuditEventsDataRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *MfaQueryAuditEventsDataRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *MfaQueryAuditEventsDataRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type MfaQueryAuditEventsDataResponse struct {
+	Events               []*AuditEvent `protobuf:"bytes,1,rep,name=Events,proto3" json:"Events,omitempty"`
+	Error                *Error        `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *MfaQueryAuditEventsDataResponse) Reset()         { *m = MfaQueryAuditEventsDataResponse{} }
+func (m *MfaQueryAuditEventsDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaQueryAuditEventsDataResponse) ProtoMessage()    {}
+func (*MfaQueryAuditEventsDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{21}
+}
+func (m *MfaQueryAuditEventsDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaQueryAuditEventsDataResponse.Unmarshal(m, b)
+}
+func (m *MfaQueryAuditEventsDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaQueryAuditEventsDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaQueryAuditEventsDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaQueryAuditEventsDataResponse.Merge(dst, src)
+}
+func (m *MfaQueryAuditEventsDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaQueryAuditEventsDataResponse.Size(m)
+}
+func (m *MfaQueryAuditEventsDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaQueryAuditEventsDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaQueryAuditEventsDataResponse proto.InternalMessageInfo
+
+func (m *MfaQueryAuditEventsDataResponse) GetEvents() []*AuditEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *MfaQueryAuditEventsDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type AuditEvent struct {
+	ID         string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Type       string `protobuf:"bytes,2,opt,name=Type,proto3" json:"Type,omitempty"`
+	UserID     string `protobuf:"bytes,3,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID string `protobuf:"bytes,4,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Method     string `protobuf:"bytes,5,opt,name=Method,proto3" json:"Method,omitempty"`
+	DeviceID   string `protobuf:"bytes,6,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	// Actor is the ID of the authenticated client that made the call.
+	Actor     string `protobuf:"bytes,7,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	Reason    string `protobuf:"bytes,8,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	CreatedAt int64  `protobuf:"varint,9,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	ExpiresAt int64  `protobuf:"varint,10,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	// Note is the actor given in the request, e.g. the operator acting through the client.
+	Note                 string   `protobuf:"bytes,11,opt,name=Note,proto3" json:"Note,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEvent) Reset()         { *m = AuditEvent{} }
+func (m *AuditEvent) String() string { return proto.CompactTextString(m) }
+func (*AuditEvent) ProtoMessage()    {}
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{22}
+}
+func (m *AuditEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEvent.Unmarshal(m, b)
+}
+func (m *AuditEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEvent.Marshal(b, m, deterministic)
+}
+func (dst *AuditEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEvent.Merge(dst, src)
+}
+func (m *AuditEvent) XXX_Size() int {
+	return xxx_messageInfo_AuditEvent.Size(m)
+}
+func (m *AuditEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEvent proto.InternalMessageInfo
+
+func (m *AuditEvent) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *AuditEvent) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *AuditEvent) GetNote() string {
+	if m != nil {
+		return m.Note
+	}
+	return ""
+}
+
+// ProviderConfig holds the settings applied to every enrollment and check of
+// the provider. Zero values fall back to the service defaults.
+type ProviderConfig struct {
+	ProviderID     string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Issuer         string   `protobuf:"bytes,2,opt,name=Issuer,proto3" json:"Issuer,omitempty"`
+	AllowedFactors []string `protobuf:"bytes,3,rep,name=AllowedFactors,proto3" json:"AllowedFactors,omitempty"`
+	TotpDigits     int32    `protobuf:"varint,4,opt,name=TotpDigits,proto3" json:"TotpDigits,omitempty"`
+	TotpPeriod     int32    `protobuf:"varint,5,opt,name=TotpPeriod,proto3" json:"TotpPeriod,omitempty"`
+	TotpAlgorithm  string   `protobuf:"bytes,6,opt,name=TotpAlgorithm,proto3" json:"TotpAlgorithm,omitempty"`
+	// TotpSkew is the number of time steps accepted around the current one, -1 accepts the current step only.
+	TotpSkew          int32  `protobuf:"varint,7,opt,name=TotpSkew,proto3" json:"TotpSkew,omitempty"`
+	RecoveryCodeCount int32  `protobuf:"varint,8,opt,name=RecoveryCodeCount,proto3" json:"RecoveryCodeCount,omitempty"`
+	LockoutThreshold  int32  `protobuf:"varint,9,opt,name=LockoutThreshold,proto3" json:"LockoutThreshold,omitempty"`
+	LockoutDuration   int64  `protobuf:"varint,10,opt,name=LockoutDuration,proto3" json:"LockoutDuration,omitempty"`
+	QrSize            int32  `protobuf:"varint,11,opt,name=QrSize,proto3" json:"QrSize,omitempty"`
+	QrForeground      string `protobuf:"bytes,12,opt,name=QrForeground,proto3" json:"QrForeground,omitempty"`
+	QrBackground      string `protobuf:"bytes,13,opt,name=QrBackground,proto3" json:"QrBackground,omitempty"`
+	// TotpMaxDrift bounds the clock drift in time steps the validation window follows, -1 disables drift tracking.
+	TotpMaxDrift int32 `protobuf:"varint,14,opt,name=TotpMaxDrift,proto3" json:"TotpMaxDrift,omitempty"`
+	// TrustedDeviceTTL is the lifetime of remember-device tokens in seconds.
+	TrustedDeviceTTL     int64    `protobuf:"varint,15,opt,name=TrustedDeviceTTL,proto3" json:"TrustedDeviceTTL,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProviderConfig) Reset()         { *m = ProviderConfig{} }
+func (m *ProviderConfig) String() string { return proto.CompactTextString(m) }
+func (*ProviderConfig) ProtoMessage()    {}
+func (*ProviderConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{23}
+}
+func (m *ProviderConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProviderConfig.Unmarshal(m, b)
+}
+func (m *ProviderConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProviderConfig.Marshal(b, m, deterministic)
+}
+func (dst *ProviderConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProviderConfig.Merge(dst, src)
+}
+func (m *ProviderConfig) XXX_Size() int {
+	return xxx_messageInfo_ProviderConfig.Size(m)
+}
+func (m *ProviderConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProviderConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProviderConfig proto.InternalMessageInfo
+
+func (m *ProviderConfig) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *ProviderConfig) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *ProviderConfig) GetAllowedFactors() []string {
+	if m != nil {
+		return m.AllowedFactors
+	}
+	return nil
+}
+
+func (m *ProviderConfig) GetTotpDigits() int32 {
+	if m != nil {
+		return m.TotpDigits
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetTotpPeriod() int32 {
+	if m != nil {
+		return m.TotpPeriod
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetTotpAlgorithm() string {
+	if m != nil {
+		return m.TotpAlgorithm
+	}
+	return ""
+}
+
+func (m *ProviderConfig) GetTotpSkew() int32 {
+	if m != nil {
+		return m.TotpSkew
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetRecoveryCodeCount() int32 {
+	if m != nil {
+		return m.RecoveryCodeCount
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetLockoutThreshold() int32 {
+	if m != nil {
+		return m.LockoutThreshold
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetLockoutDuration() int64 {
+	if m != nil {
+		return m.LockoutDuration
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetQrSize() int32 {
+	if m != nil {
+		return m.QrSize
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetQrForeground() string {
+	if m != nil {
+		return m.QrForeground
+	}
+	return ""
+}
+
+func (m *ProviderConfig) GetQrBackground() string {
+	if m != nil {
+		return m.QrBackground
+	}
+	return ""
+}
+
+func (m *ProviderConfig) GetTotpMaxDrift() int32 {
+	if m != nil {
+		return m.TotpMaxDrift
+	}
+	return 0
+}
+
+func (m *ProviderConfig) GetTrustedDeviceTTL() int64 {
+	if m != nil {
+		return m.TrustedDeviceTTL
+	}
+	return 0
+}
+
+type MfaGetProviderConfigDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaGetProviderConfigDataRequest) Reset()         { *m = MfaGetProviderConfigDataRequest{} }
+func (m *MfaGetProviderConfigDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaGetProviderConfigDataRequest) ProtoMessage()    {}
+func (*MfaGetProviderConfigDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{24}
+}
+func (m *MfaGetProviderConfigDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaGetProviderConfigDataRequest.Unmarshal(m, b)
+}
+func (m *MfaGetProviderConfigDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaGetProviderConfigDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaGetProviderConfigDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaGetProviderConfigDataRequest.Merge(dst, src)
+}
+func (m *MfaGetProviderConfigDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaGetProviderConfigDataRequest.Size(m)
+}
+func (m *MfaGetProviderConfigDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaGetProviderConfigDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaGetProviderConfigDataRequest proto.InternalMessageInfo
+
+func (m *MfaGetProviderConfigDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+type MfaGetProviderConfigDataResponse struct {
+	Config               *ProviderConfig `protobuf:"bytes,1,opt,name=Config,proto3" json:"Config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MfaGetProviderConfigDataResponse) Reset()         { *m = MfaGetProviderConfigDataResponse{} }
+func (m *MfaGetProviderConfigDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaGetProviderConfigDataResponse) ProtoMessage()    {}
+func (*MfaGetProviderConfigDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{25}
+}
+func (m *MfaGetProviderConfigDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaGetProviderConfigDataResponse.Unmarshal(m, b)
+}
+func (m *MfaGetProviderConfigDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaGetProviderConfigDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaGetProviderConfigDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaGetProviderConfigDataResponse.Merge(dst, src)
+}
+func (m *MfaGetProviderConfigDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaGetProviderConfigDataResponse.Size(m)
+}
+func (m *MfaGetProviderConfigDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaGetProviderConfigDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaGetProviderConfigDataResponse proto.InternalMessageInfo
+
+func (m *MfaGetProviderConfigDataResponse) GetConfig() *ProviderConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type MfaSetProviderConfigDataRequest struct {
+	Config               *ProviderConfig `protobuf:"bytes,1,opt,name=Config,proto3" json:"Config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MfaSetProviderConfigDataRequest) Reset()         { *m = MfaSetProviderConfigDataRequest{} }
+func (m *MfaSetProviderConfigDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaSetProviderConfigDataRequest) ProtoMessage()    {}
+func (*MfaSetProviderConfigDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{26}
+}
+func (m *MfaSetProviderConfigDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaSetProviderConfigDataRequest.Unmarshal(m, b)
+}
+func (m *MfaSetProviderConfigDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaSetProviderConfigDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaSetProviderConfigDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaSetProviderConfigDataRequest.Merge(dst, src)
+}
+func (m *MfaSetProviderConfigDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaSetProviderConfigDataRequest.Size(m)
+}
+func (m *MfaSetProviderConfigDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaSetProviderConfigDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaSetProviderConfigDataRequest proto.InternalMessageInfo
+
+func (m *MfaSetProviderConfigDataRequest) GetConfig() *ProviderConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type MfaSetProviderConfigDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaSetProviderConfigDataResponse) Reset()         { *m = MfaSetProviderConfigDataResponse{} }
+func (m *MfaSetProviderConfigDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaSetProviderConfigDataResponse) ProtoMessage()    {}
+func (*MfaSetProviderConfigDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{27}
+}
+func (m *MfaSetProviderConfigDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaSetProviderConfigDataResponse.Unmarshal(m, b)
+}
+func (m *MfaSetProviderConfigDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaSetProviderConfigDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaSetProviderConfigDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaSetProviderConfigDataResponse.Merge(dst, src)
+}
+func (m *MfaSetProviderConfigDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaSetProviderConfigDataResponse.Size(m)
+}
+func (m *MfaSetProviderConfigDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaSetProviderConfigDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaSetProviderConfigDataResponse proto.InternalMessageInfo
+
+func (m *MfaSetProviderConfigDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+type MfaListProviderConfigsDataRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaListProviderConfigsDataRequest) Reset()         { *m = MfaListProviderConfigsDataRequest{} }
+func (m *MfaListProviderConfigsDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaListProviderConfigsDataRequest) ProtoMessage()    {}
+func (*MfaListProviderConfigsDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{28}
+}
+func (m *MfaListProviderConfigsDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaListProviderConfigsDataRequest.Unmarshal(m, b)
+}
+func (m *MfaListProviderConfigsDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaListProviderConfigsDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaListProviderConfigsDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaListProviderConfigsDataRequest.Merge(dst, src)
+}
+func (m *MfaListProviderConfigsDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaListProviderConfigsDataRequest.Size(m)
+}
+func (m *MfaListProviderConfigsDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaListProviderConfigsDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaListProviderConfigsDataRequest proto.InternalMessageInfo
+
+type MfaListProviderConfigsDataResponse struct {
+	Configs              []*ProviderConfig `protobuf:"bytes,1,rep,name=Configs,proto3" json:"Configs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *MfaListProviderConfigsDataResponse) Reset()         { *m = MfaListProviderConfigsDataResponse{} }
+func (m *MfaListProviderConfigsDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaListProviderConfigsDataResponse) ProtoMessage()    {}
+func (*MfaListProviderConfigsDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{29}
+}
+func (m *MfaListProviderConfigsDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaListProviderConfigsDataResponse.Unmarshal(m, b)
+}
+func (m *MfaListProviderConfigsDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaListProviderConfigsDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaListProviderConfigsDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaListProviderConfigsDataResponse.Merge(dst, src)
+}
+func (m *MfaListProviderConfigsDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaListProviderConfigsDataResponse.Size(m)
+}
+func (m *MfaListProviderConfigsDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaListProviderConfigsDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaListProviderConfigsDataResponse proto.InternalMessageInfo
+
+func (m *MfaListProviderConfigsDataResponse) GetConfigs() []*ProviderConfig {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+type MfaDeleteProviderConfigDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaDeleteProviderConfigDataRequest) Reset()         { *m = MfaDeleteProviderConfigDataRequest{} }
+func (m *MfaDeleteProviderConfigDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaDeleteProviderConfigDataRequest) ProtoMessage()    {}
+func (*MfaDeleteProviderConfigDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{30}
+}
+func (m *MfaDeleteProviderConfigDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaDeleteProviderConfigDataRequest.Unmarshal(m, b)
+}
+func (m *MfaDeleteProviderConfigDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaDeleteProviderConfigDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaDeleteProviderConfigDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaDeleteProviderConfigDataRequest.Merge(dst, src)
+}
+func (m *MfaDeleteProviderConfigDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaDeleteProviderConfigDataRequest.Size(m)
+}
+func (m *MfaDeleteProviderConfigDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaDeleteProviderConfigDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaDeleteProviderConfigDataRequest proto.InternalMessageInfo
+
+func (m *MfaDeleteProviderConfigDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+type MfaDeleteProviderConfigDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaDeleteProviderConfigDataResponse) Reset()         { *m = MfaDeleteProviderConfigDataResponse{} }
+func (m *MfaDeleteProviderConfigDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaDeleteProviderConfigDataResponse) ProtoMessage()    {}
+func (*MfaDeleteProviderConfigDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{31}
+}
+func (m *MfaDeleteProviderConfigDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaDeleteProviderConfigDataResponse.Unmarshal(m, b)
+}
+func (m *MfaDeleteProviderConfigDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaDeleteProviderConfigDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaDeleteProviderConfigDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaDeleteProviderConfigDataResponse.Merge(dst, src)
+}
+func (m *MfaDeleteProviderConfigDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaDeleteProviderConfigDataResponse.Size(m)
+}
+func (m *MfaDeleteProviderConfigDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaDeleteProviderConfigDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaDeleteProviderConfigDataResponse proto.InternalMessageInfo
+
+func (m *MfaDeleteProviderConfigDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaDeleteProviderConfigDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaGetUserStatusDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaGetUserStatusDataRequest) Reset()         { *m = MfaGetUserStatusDataRequest{} }
+func (m *MfaGetUserStatusDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaGetUserStatusDataRequest) ProtoMessage()    {}
+func (*MfaGetUserStatusDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{32}
+}
+func (m *MfaGetUserStatusDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaGetUserStatusDataRequest.Unmarshal(m, b)
+}
+func (m *MfaGetUserStatusDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaGetUserStatusDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaGetUserStatusDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaGetUserStatusDataRequest.Merge(dst, src)
+}
+func (m *MfaGetUserStatusDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaGetUserStatusDataRequest.Size(m)
+}
+func (m *MfaGetUserStatusDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaGetUserStatusDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaGetUserStatusDataRequest proto.InternalMessageInfo
+
+func (m *MfaGetUserStatusDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaGetUserStatusDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+type MfaGetUserStatusDataResponse struct {
+	Devices                    []*Device `protobuf:"bytes,1,rep,name=Devices,proto3" json:"Devices,omitempty"`
+	YubiKeys                   []string  `protobuf:"bytes,2,rep,name=YubiKeys,proto3" json:"YubiKeys,omitempty"`
+	RecoveryCodes              int32     `protobuf:"varint,3,opt,name=RecoveryCodes,proto3" json:"RecoveryCodes,omitempty"`
+	TrustedDevices             int32     `protobuf:"varint,4,opt,name=TrustedDevices,proto3" json:"TrustedDevices,omitempty"`
+	FailedAttempts             int32     `protobuf:"varint,5,opt,name=FailedAttempts,proto3" json:"FailedAttempts,omitempty"`
+	LockedOutUntil             int64     `protobuf:"varint,6,opt,name=LockedOutUntil,proto3" json:"LockedOutUntil,omitempty"`
+	BypassCodeExpiresAt        int64     `protobuf:"varint,7,opt,name=BypassCodeExpiresAt,proto3" json:"BypassCodeExpiresAt,omitempty"`
+	AccountRecoveryCompletesAt int64     `protobuf:"varint,8,opt,name=AccountRecoveryCompletesAt,proto3" json:"AccountRecoveryCompletesAt,omitempty"`
+	XXX_NoUnkeyedLiteral       struct{}  `json:"-"`
+	XXX_unrecognized           []byte    `json:"-"`
+	XXX_sizecache              int32     `json:"-"`
+}
+
+func (m *MfaGetUserStatusDataResponse) Reset()         { *m = MfaGetUserStatusDataResponse{} }
+func (m *MfaGetUserStatusDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaGetUserStatusDataResponse) ProtoMessage()    {}
+func (*MfaGetUserStatusDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{33}
+}
+func (m *MfaGetUserStatusDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaGetUserStatusDataResponse.Unmarshal(m, b)
+}
+func (m *MfaGetUserStatusDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaGetUserStatusDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaGetUserStatusDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaGetUserStatusDataResponse.Merge(dst, src)
+}
+func (m *MfaGetUserStatusDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaGetUserStatusDataResponse.Size(m)
+}
+func (m *MfaGetUserStatusDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaGetUserStatusDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaGetUserStatusDataResponse proto.InternalMessageInfo
+
+func (m *MfaGetUserStatusDataResponse) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+func (m *MfaGetUserStatusDataResponse) GetYubiKeys() []string {
+	if m != nil {
+		return m.YubiKeys
+	}
+	return nil
+}
+
+func (m *MfaGetUserStatusDataResponse) GetRecoveryCodes() int32 {
+	if m != nil {
+		return m.RecoveryCodes
+	}
+	return 0
+}
+
+func (m *MfaGetUserStatusDataResponse) GetTrustedDevices() int32 {
+	if m != nil {
+		return m.TrustedDevices
+	}
+	return 0
+}
+
+func (m *MfaGetUserStatusDataResponse) GetFailedAttempts() int32 {
+	if m != nil {
+		return m.FailedAttempts
+	}
+	return 0
+}
+
+func (m *MfaGetUserStatusDataResponse) GetLockedOutUntil() int64 {
+	if m != nil {
+		return m.LockedOutUntil
+	}
+	return 0
+}
+
+func (m *MfaGetUserStatusDataResponse) GetBypassCodeExpiresAt() int64 {
+	if m != nil {
+		return m.BypassCodeExpiresAt
+	}
+	return 0
+}
+
+func (m *MfaGetUserStatusDataResponse) GetAccountRecoveryCompletesAt() int64 {
+	if m != nil {
+		return m.AccountRecoveryCompletesAt
+	}
+	return 0
+}
+
+type MfaResetEnrollmentDataRequest struct {
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID     string `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	// Actor is recorded as a note, the audited actor is the authenticated client.
+	Actor                string   `protobuf:"bytes,3,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaResetEnrollmentDataRequest) Reset()         { *m = MfaResetEnrollmentDataRequest{} }
+func (m *MfaResetEnrollmentDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaResetEnrollmentDataRequest) ProtoMessage()    {}
+func (*MfaResetEnrollmentDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{34}
+}
+func (m *MfaResetEnrollmentDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaResetEnrollmentDataRequest.Unmarshal(m, b)
+}
+func (m *MfaResetEnrollmentDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaResetEnrollmentDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaResetEnrollmentDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaResetEnrollmentDataRequest.Merge(dst, src)
+}
+func (m *MfaResetEnrollmentDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaResetEnrollmentDataRequest.Size(m)
+}
+func (m *MfaResetEnrollmentDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaResetEnrollmentDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaResetEnrollmentDataRequest proto.InternalMessageInfo
+
+func (m *MfaResetEnrollmentDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaResetEnrollmentDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaResetEnrollmentDataRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *MfaResetEnrollmentDataRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type MfaResetEnrollmentDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaResetEnrollmentDataResponse) Reset()         { *m = MfaResetEnrollmentDataResponse{} }
+func (m *MfaResetEnrollmentDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaResetEnrollmentDataResponse) ProtoMessage()    {}
+func (*MfaResetEnrollmentDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{35}
+}
+func (m *MfaResetEnrollmentDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaResetEnrollmentDataResponse.Unmarshal(m, b)
+}
+func (m *MfaResetEnrollmentDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaResetEnrollmentDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaResetEnrollmentDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaResetEnrollmentDataResponse.Merge(dst, src)
+}
+func (m *MfaResetEnrollmentDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaResetEnrollmentDataResponse.Size(m)
+}
+func (m *MfaResetEnrollmentDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaResetEnrollmentDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaResetEnrollmentDataResponse proto.InternalMessageInfo
+
+func (m *MfaResetEnrollmentDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaResetEnrollmentDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaClearLockoutDataRequest struct {
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID     string `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	// Actor is recorded as a note, the audited actor is the authenticated client.
+	Actor                string   `protobuf:"bytes,3,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaClearLockoutDataRequest) Reset()         { *m = MfaClearLockoutDataRequest{} }
+func (m *MfaClearLockoutDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaClearLockoutDataRequest) ProtoMessage()    {}
+func (*MfaClearLockoutDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{36}
+}
+func (m *MfaClearLockoutDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaClearLockoutDataRequest.Unmarshal(m, b)
+}
+func (m *MfaClearLockoutDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaClearLockoutDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaClearLockoutDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaClearLockoutDataRequest.Merge(dst, src)
+}
+func (m *MfaClearLockoutDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaClearLockoutDataRequest.Size(m)
+}
+func (m *MfaClearLockoutDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaClearLockoutDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaClearLockoutDataRequest proto.InternalMessageInfo
+
+func (m *MfaClearLockoutDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaClearLockoutDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaClearLockoutDataRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *MfaClearLockoutDataRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type MfaClearLockoutDataResponse struct {
+	Result               bool     `protobuf:"varint,1,opt,name=Result,proto3" json:"Result,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaClearLockoutDataResponse) Reset()         { *m = MfaClearLockoutDataResponse{} }
+func (m *MfaClearLockoutDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaClearLockoutDataResponse) ProtoMessage()    {}
+func (*MfaClearLockoutDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{37}
+}
+func (m *MfaClearLockoutDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaClearLockoutDataResponse.Unmarshal(m, b)
+}
+func (m *MfaClearLockoutDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaClearLockoutDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaClearLockoutDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaClearLockoutDataResponse.Merge(dst, src)
+}
+func (m *MfaClearLockoutDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaClearLockoutDataResponse.Size(m)
+}
+func (m *MfaClearLockoutDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaClearLockoutDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaClearLockoutDataResponse proto.InternalMessageInfo
+
+func (m *MfaClearLockoutDataResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *MfaClearLockoutDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaIssueBypassCodeDataRequest struct {
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID     string `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	// Actor is recorded as a note, the audited actor is the authenticated client.
+	Actor  string `protobuf:"bytes,3,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	Reason string `protobuf:"bytes,4,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	// TTL is the lifetime of the code in seconds.
+	TTL                  int64    `protobuf:"varint,5,opt,name=TTL,proto3" json:"TTL,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaIssueBypassCodeDataRequest) Reset()         { *m = MfaIssueBypassCodeDataRequest{} }
+func (m *MfaIssueBypassCodeDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaIssueBypassCodeDataRequest) ProtoMessage()    {}
+func (*MfaIssueBypassCodeDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{38}
+}
+func (m *MfaIssueBypassCodeDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaIssueBypassCodeDataRequest.Unmarshal(m, b)
+}
+func (m *MfaIssueBypassCodeDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaIssueBypassCodeDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaIssueBypassCodeDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaIssueBypassCodeDataRequest.Merge(dst, src)
+}
+func (m *MfaIssueBypassCodeDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaIssueBypassCodeDataRequest.Size(m)
+}
+func (m *MfaIssueBypassCodeDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaIssueBypassCodeDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaIssueBypassCodeDataRequest proto.InternalMessageInfo
+
+func (m *MfaIssueBypassCodeDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaIssueBypassCodeDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaIssueBypassCodeDataRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *MfaIssueBypassCodeDataRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *MfaIssueBypassCodeDataRequest) GetTTL() int64 {
+	if m != nil {
+		return m.TTL
+	}
+	return 0
+}
+
+type MfaIssueBypassCodeDataResponse struct {
+	Code                 string   `protobuf:"bytes,1,opt,name=Code,proto3" json:"Code,omitempty"`
+	ExpiresAt            int64    `protobuf:"varint,2,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaIssueBypassCodeDataResponse) Reset()         { *m = MfaIssueBypassCodeDataResponse{} }
+func (m *MfaIssueBypassCodeDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaIssueBypassCodeDataResponse) ProtoMessage()    {}
+func (*MfaIssueBypassCodeDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{39}
+}
+func (m *MfaIssueBypassCodeDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaIssueBypassCodeDataResponse.Unmarshal(m, b)
+}
+func (m *MfaIssueBypassCodeDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaIssueBypassCodeDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaIssueBypassCodeDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaIssueBypassCodeDataResponse.Merge(dst, src)
+}
+func (m *MfaIssueBypassCodeDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaIssueBypassCodeDataResponse.Size(m)
+}
+func (m *MfaIssueBypassCodeDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaIssueBypassCodeDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaIssueBypassCodeDataResponse proto.InternalMessageInfo
+
+func (m *MfaIssueBypassCodeDataResponse) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *MfaIssueBypassCodeDataResponse) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+type MfaRequestAccountRecoveryDataRequest struct {
+	ProviderID           string   `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRequestAccountRecoveryDataRequest) Reset()         { *m = MfaRequestAccountRecoveryDataRequest{} }
+func (m *MfaRequestAccountRecoveryDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaRequestAccountRecoveryDataRequest) ProtoMessage()    {}
+func (*MfaRequestAccountRecoveryDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{40}
+}
+func (m *MfaRequestAccountRecoveryDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRequestAccountRecoveryDataRequest.Unmarshal(m, b)
+}
+func (m *MfaRequestAccountRecoveryDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRequestAccountRecoveryDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaRequestAccountRecoveryDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRequestAccountRecoveryDataRequest.Merge(dst, src)
+}
+func (m *MfaRequestAccountRecoveryDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaRequestAccountRecoveryDataRequest.Size(m)
+}
+func (m *MfaRequestAccountRecoveryDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRequestAccountRecoveryDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRequestAccountRecoveryDataRequest proto.InternalMessageInfo
+
+func (m *MfaRequestAccountRecoveryDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaRequestAccountRecoveryDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+type MfaRequestAccountRecoveryDataResponse struct {
+	CompletesAt          int64    `protobuf:"varint,1,opt,name=CompletesAt,proto3" json:"CompletesAt,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRequestAccountRecoveryDataResponse) Reset()         { *m = MfaRequestAccountRecoveryDataResponse{} }
+func (m *MfaRequestAccountRecoveryDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaRequestAccountRecoveryDataResponse) ProtoMessage()    {}
+func (*MfaRequestAccountRecoveryDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{41}
+}
+func (m *MfaRequestAccountRecoveryDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRequestAccountRecoveryDataResponse.Unmarshal(m, b)
+}
+func (m *MfaRequestAccountRecoveryDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRequestAccountRecoveryDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaRequestAccountRecoveryDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRequestAccountRecoveryDataResponse.Merge(dst, src)
+}
+func (m *MfaRequestAccountRecoveryDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaRequestAccountRecoveryDataResponse.Size(m)
+}
+func (m *MfaRequestAccountRecoveryDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRequestAccountRecoveryDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRequestAccountRecoveryDataResponse proto.InternalMessageInfo
+
+func (m *MfaRequestAccountRecoveryDataResponse) GetCompletesAt() int64 {
+	if m != nil {
+		return m.CompletesAt
+	}
+	return 0
+}
+
+func (m *MfaRequestAccountRecoveryDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaRotateSecretDataRequest struct {
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID     string `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	// DeviceID may be empty when the user has a single device.
+	DeviceID             string   `protobuf:"bytes,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	AppName              string   `protobuf:"bytes,4,opt,name=AppName,proto3" json:"AppName,omitempty"`
+	Email                string   `protobuf:"bytes,5,opt,name=Email,proto3" json:"Email,omitempty"`
+	QrSize               int32    `protobuf:"varint,6,opt,name=QrSize,proto3" json:"QrSize,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRotateSecretDataRequest) Reset()         { *m = MfaRotateSecretDataRequest{} }
+func (m *MfaRotateSecretDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaRotateSecretDataRequest) ProtoMessage()    {}
+func (*MfaRotateSecretDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{42}
+}
+func (m *MfaRotateSecretDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRotateSecretDataRequest.Unmarshal(m, b)
+}
+func (m *MfaRotateSecretDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRotateSecretDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaRotateSecretDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRotateSecretDataRequest.Merge(dst, src)
+}
+func (m *MfaRotateSecretDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaRotateSecretDataRequest.Size(m)
+}
+func (m *MfaRotateSecretDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRotateSecretDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRotateSecretDataRequest proto.InternalMessageInfo
+
+func (m *MfaRotateSecretDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataRequest) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataRequest) GetAppName() string {
+	if m != nil {
+		return m.AppName
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataRequest) GetQrSize() int32 {
+	if m != nil {
+		return m.QrSize
+	}
+	return 0
+}
+
+type MfaRotateSecretDataResponse struct {
+	SecretKey            string   `protobuf:"bytes,1,opt,name=SecretKey,proto3" json:"SecretKey,omitempty"`
+	URL                  string   `protobuf:"bytes,2,opt,name=URL,proto3" json:"URL,omitempty"`
+	QrCodeURL            string   `protobuf:"bytes,3,opt,name=QrCodeURL,proto3" json:"QrCodeURL,omitempty"`
+	ImageBased           string   `protobuf:"bytes,4,opt,name=ImageBased,proto3" json:"ImageBased,omitempty"`
+	DeviceID             string   `protobuf:"bytes,5,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	GraceExpiresAt       int64    `protobuf:"varint,6,opt,name=GraceExpiresAt,proto3" json:"GraceExpiresAt,omitempty"`
+	Error                *Error   `protobuf:"bytes,7,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRotateSecretDataResponse) Reset()         { *m = MfaRotateSecretDataResponse{} }
+func (m *MfaRotateSecretDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaRotateSecretDataResponse) ProtoMessage()    {}
+func (*MfaRotateSecretDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{43}
+}
+func (m *MfaRotateSecretDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRotateSecretDataResponse.Unmarshal(m, b)
+}
+func (m *MfaRotateSecretDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRotateSecretDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaRotateSecretDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRotateSecretDataResponse.Merge(dst, src)
+}
+func (m *MfaRotateSecretDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaRotateSecretDataResponse.Size(m)
+}
+func (m *MfaRotateSecretDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRotateSecretDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRotateSecretDataResponse proto.InternalMessageInfo
+
+func (m *MfaRotateSecretDataResponse) GetSecretKey() string {
+	if m != nil {
+		return m.SecretKey
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataResponse) GetURL() string {
+	if m != nil {
+		return m.URL
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataResponse) GetQrCodeURL() string {
+	if m != nil {
+		return m.QrCodeURL
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataResponse) GetImageBased() string {
+	if m != nil {
+		return m.ImageBased
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataResponse) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *MfaRotateSecretDataResponse) GetGraceExpiresAt() int64 {
+	if m != nil {
+		return m.GraceExpiresAt
+	}
+	return 0
+}
+
+func (m *MfaRotateSecretDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaImportEnrollmentDataRequest struct {
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	// Actor is recorded as a note, the audited actor is the authenticated client.
+	Actor                string          `protobuf:"bytes,2,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	Records              []*ImportRecord `protobuf:"bytes,3,rep,name=Records,proto3" json:"Records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MfaImportEnrollmentDataRequest) Reset()         { *m = MfaImportEnrollmentDataRequest{} }
+func (m *MfaImportEnrollmentDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaImportEnrollmentDataRequest) ProtoMessage()    {}
+func (*MfaImportEnrollmentDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{44}
+}
+func (m *MfaImportEnrollmentDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaImportEnrollmentDataRequest.Unmarshal(m, b)
+}
+func (m *MfaImportEnrollmentDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaImportEnrollmentDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaImportEnrollmentDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaImportEnrollmentDataRequest.Merge(dst, src)
+}
+func (m *MfaImportEnrollmentDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaImportEnrollmentDataRequest.Size(m)
+}
+func (m *MfaImportEnrollmentDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaImportEnrollmentDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaImportEnrollmentDataRequest proto.InternalMessageInfo
+
+func (m *MfaImportEnrollmentDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaImportEnrollmentDataRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *MfaImportEnrollmentDataRequest) GetRecords() []*ImportRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type MfaImportEnrollmentDataResponse struct {
+	Results              []*ImportResult `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+	Imported             int32           `protobuf:"varint,2,opt,name=Imported,proto3" json:"Imported,omitempty"`
+	Failed               int32           `protobuf:"varint,3,opt,name=Failed,proto3" json:"Failed,omitempty"`
+	Error                *Error          `protobuf:"bytes,4,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MfaImportEnrollmentDataResponse) Reset()         { *m = MfaImportEnrollmentDataResponse{} }
+func (m *MfaImportEnrollmentDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaImportEnrollmentDataResponse) ProtoMessage()    {}
+func (*MfaImportEnrollmentDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{45}
+}
+func (m *MfaImportEnrollmentDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaImportEnrollmentDataResponse.Unmarshal(m, b)
+}
+func (m *MfaImportEnrollmentDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaImportEnrollmentDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaImportEnrollmentDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaImportEnrollmentDataResponse.Merge(dst, src)
+}
+func (m *MfaImportEnrollmentDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaImportEnrollmentDataResponse.Size(m)
+}
+func (m *MfaImportEnrollmentDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaImportEnrollmentDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaImportEnrollmentDataResponse proto.InternalMessageInfo
+
+func (m *MfaImportEnrollmentDataResponse) GetResults() []*ImportResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *MfaImportEnrollmentDataResponse) GetImported() int32 {
+	if m != nil {
+		return m.Imported
+	}
+	return 0
+}
+
+func (m *MfaImportEnrollmentDataResponse) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *MfaImportEnrollmentDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// ImportRecord is a TOTP secret enrolled in another system, given either as an
+// otpauth URI or as the secret with its parameters.
+type ImportRecord struct {
+	UserID     string `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	URI        string `protobuf:"bytes,2,opt,name=URI,proto3" json:"URI,omitempty"`
+	Secret     string `protobuf:"bytes,3,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	Algorithm  string `protobuf:"bytes,4,opt,name=Algorithm,proto3" json:"Algorithm,omitempty"`
+	Digits     int32  `protobuf:"varint,5,opt,name=Digits,proto3" json:"Digits,omitempty"`
+	Period     int32  `protobuf:"varint,6,opt,name=Period,proto3" json:"Period,omitempty"`
+	DeviceName string `protobuf:"bytes,7,opt,name=DeviceName,proto3" json:"DeviceName,omitempty"`
+	// RecoveryCodeHashes are hex encoded SHA-256 hashes of recovery codes.
+	RecoveryCodeHashes   []string `protobuf:"bytes,8,rep,name=RecoveryCodeHashes,proto3" json:"RecoveryCodeHashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportRecord) Reset()         { *m = ImportRecord{} }
+func (m *ImportRecord) String() string { return proto.CompactTextString(m) }
+func (*ImportRecord) ProtoMessage()    {}
+func (*ImportRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{46}
+}
+func (m *ImportRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportRecord.Unmarshal(m, b)
+}
+func (m *ImportRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportRecord.Marshal(b, m, deterministic)
+}
+func (dst *ImportRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRecord.Merge(dst, src)
+}
+func (m *ImportRecord) XXX_Size() int {
+	return xxx_messageInfo_ImportRecord.Size(m)
+}
+func (m *ImportRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportRecord proto.InternalMessageInfo
+
+func (m *ImportRecord) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *ImportRecord) GetURI() string {
+	if m != nil {
+		return m.URI
+	}
+	return ""
+}
+
+func (m *ImportRecord) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *ImportRecord) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ""
+}
+
+func (m *ImportRecord) GetDigits() int32 {
+	if m != nil {
+		return m.Digits
+	}
+	return 0
+}
+
+func (m *ImportRecord) GetPeriod() int32 {
+	if m != nil {
+		return m.Period
+	}
+	return 0
+}
+
+func (m *ImportRecord) GetDeviceName() string {
+	if m != nil {
+		return m.DeviceName
+	}
+	return ""
+}
+
+func (m *ImportRecord) GetRecoveryCodeHashes() []string {
+	if m != nil {
+		return m.RecoveryCodeHashes
+	}
+	return nil
+}
+
+type ImportResult struct {
+	Index                int32    `protobuf:"varint,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	UserID               string   `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Result               bool     `protobuf:"varint,3,opt,name=Result,proto3" json:"Result,omitempty"`
+	DeviceID             string   `protobuf:"bytes,4,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Error                string   `protobuf:"bytes,5,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportResult) Reset()         { *m = ImportResult{} }
+func (m *ImportResult) String() string { return proto.CompactTextString(m) }
+func (*ImportResult) ProtoMessage()    {}
+func (*ImportResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{47}
+}
+func (m *ImportResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportResult.Unmarshal(m, b)
+}
+func (m *ImportResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportResult.Marshal(b, m, deterministic)
+}
+func (dst *ImportResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResult.Merge(dst, src)
+}
+func (m *ImportResult) XXX_Size() int {
+	return xxx_messageInfo_ImportResult.Size(m)
+}
+func (m *ImportResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResult proto.InternalMessageInfo
+
+func (m *ImportResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ImportResult) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *ImportResult) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *ImportResult) GetDeviceID() string {
+	if m != nil {
+		return m.DeviceID
+	}
+	return ""
+}
+
+func (m *ImportResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type MfaExportEnrollmentsDataRequest struct {
+	// ProviderID limits the export to one provider, all providers are exported when it is empty.
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	// Actor is recorded as a note, the audited actor is the authenticated client.
+	Actor string `protobuf:"bytes,2,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	// Passphrase encrypts the archive with a key derived by scrypt.
+	Passphrase string `protobuf:"bytes,3,opt,name=Passphrase,proto3" json:"Passphrase,omitempty"`
+	// PublicKey is the PEM encoded RSA public key the archive is encrypted for, it is used instead of Passphrase.
+	PublicKey            string   `protobuf:"bytes,4,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaExportEnrollmentsDataRequest) Reset()         { *m = MfaExportEnrollmentsDataRequest{} }
+func (m *MfaExportEnrollmentsDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaExportEnrollmentsDataRequest) ProtoMessage()    {}
+func (*MfaExportEnrollmentsDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{48}
+}
+func (m *MfaExportEnrollmentsDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaExportEnrollmentsDataRequest.Unmarshal(m, b)
+}
+func (m *MfaExportEnrollmentsDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaExportEnrollmentsDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaExportEnrollmentsDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaExportEnrollmentsDataRequest.Merge(dst, src)
+}
+func (m *MfaExportEnrollmentsDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaExportEnrollmentsDataRequest.Size(m)
+}
+func (m *MfaExportEnrollmentsDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaExportEnrollmentsDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaExportEnrollmentsDataRequest proto.InternalMessageInfo
+
+func (m *MfaExportEnrollmentsDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaExportEnrollmentsDataRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *MfaExportEnrollmentsDataRequest) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
+func (m *MfaExportEnrollmentsDataRequest) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+// ArchiveChunk is a part of an encrypted enrollment archive, the archive is the chunks in the order streamed.
+type ArchiveChunk struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchiveChunk) Reset()         { *m = ArchiveChunk{} }
+func (m *ArchiveChunk) String() string { return proto.CompactTextString(m) }
+func (*ArchiveChunk) ProtoMessage()    {}
+func (*ArchiveChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{49}
+}
+func (m *ArchiveChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchiveChunk.Unmarshal(m, b)
+}
+func (m *ArchiveChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchiveChunk.Marshal(b, m, deterministic)
+}
+func (dst *ArchiveChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveChunk.Merge(dst, src)
+}
+func (m *ArchiveChunk) XXX_Size() int {
+	return xxx_messageInfo_ArchiveChunk.Size(m)
+}
+func (m *ArchiveChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchiveChunk proto.InternalMessageInfo
+
+func (m *ArchiveChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type MfaRestoreEnrollmentsDataRequest struct {
+	Enrollments []*Enrollment `protobuf:"bytes,1,rep,name=Enrollments,proto3" json:"Enrollments,omitempty"`
+	// Conflict is the policy for users already enrolled: skip, overwrite or merge, skip by default.
+	Conflict string `protobuf:"bytes,2,opt,name=Conflict,proto3" json:"Conflict,omitempty"`
+	// Actor is recorded as a note, the audited actor is the authenticated client.
+	Actor                string   `protobuf:"bytes,3,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaRestoreEnrollmentsDataRequest) Reset()         { *m = MfaRestoreEnrollmentsDataRequest{} }
+func (m *MfaRestoreEnrollmentsDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaRestoreEnrollmentsDataRequest) ProtoMessage()    {}
+func (*MfaRestoreEnrollmentsDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{50}
+}
+func (m *MfaRestoreEnrollmentsDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRestoreEnrollmentsDataRequest.Unmarshal(m, b)
+}
+func (m *MfaRestoreEnrollmentsDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRestoreEnrollmentsDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaRestoreEnrollmentsDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRestoreEnrollmentsDataRequest.Merge(dst, src)
+}
+func (m *MfaRestoreEnrollmentsDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaRestoreEnrollmentsDataRequest.Size(m)
+}
+func (m *MfaRestoreEnrollmentsDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRestoreEnrollmentsDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRestoreEnrollmentsDataRequest proto.InternalMessageInfo
+
+func (m *MfaRestoreEnrollmentsDataRequest) GetEnrollments() []*Enrollment {
+	if m != nil {
+		return m.Enrollments
+	}
+	return nil
+}
+
+func (m *MfaRestoreEnrollmentsDataRequest) GetConflict() string {
+	if m != nil {
+		return m.Conflict
+	}
+	return ""
+}
+
+func (m *MfaRestoreEnrollmentsDataRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+type MfaRestoreEnrollmentsDataResponse struct {
+	Results              []*RestoreResult `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+	Restored             int32            `protobuf:"varint,2,opt,name=Restored,proto3" json:"Restored,omitempty"`
+	Skipped              int32            `protobuf:"varint,3,opt,name=Skipped,proto3" json:"Skipped,omitempty"`
+	Failed               int32            `protobuf:"varint,4,opt,name=Failed,proto3" json:"Failed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *MfaRestoreEnrollmentsDataResponse) Reset()         { *m = MfaRestoreEnrollmentsDataResponse{} }
+func (m *MfaRestoreEnrollmentsDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaRestoreEnrollmentsDataResponse) ProtoMessage()    {}
+func (*MfaRestoreEnrollmentsDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{51}
+}
+func (m *MfaRestoreEnrollmentsDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaRestoreEnrollmentsDataResponse.Unmarshal(m, b)
+}
+func (m *MfaRestoreEnrollmentsDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaRestoreEnrollmentsDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaRestoreEnrollmentsDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaRestoreEnrollmentsDataResponse.Merge(dst, src)
+}
+func (m *MfaRestoreEnrollmentsDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaRestoreEnrollmentsDataResponse.Size(m)
+}
+func (m *MfaRestoreEnrollmentsDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaRestoreEnrollmentsDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaRestoreEnrollmentsDataResponse proto.InternalMessageInfo
+
+func (m *MfaRestoreEnrollmentsDataResponse) GetResults() []*RestoreResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *MfaRestoreEnrollmentsDataResponse) GetRestored() int32 {
+	if m != nil {
+		return m.Restored
+	}
+	return 0
+}
+
+func (m *MfaRestoreEnrollmentsDataResponse) GetSkipped() int32 {
+	if m != nil {
+		return m.Skipped
+	}
+	return 0
+}
+
+func (m *MfaRestoreEnrollmentsDataResponse) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+// Enrollment holds all factors of a user for a provider.
+type Enrollment struct {
+	UserID               string               `protobuf:"bytes,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID           string               `protobuf:"bytes,2,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	Devices              []*EnrollmentDevice  `protobuf:"bytes,3,rep,name=Devices,proto3" json:"Devices,omitempty"`
+	YubiKeys             []*EnrollmentYubiKey `protobuf:"bytes,4,rep,name=YubiKeys,proto3" json:"YubiKeys,omitempty"`
+	RecoveryCodes        []string             `protobuf:"bytes,5,rep,name=RecoveryCodes,proto3" json:"RecoveryCodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Enrollment) Reset()         { *m = Enrollment{} }
+func (m *Enrollment) String() string { return proto.CompactTextString(m) }
+func (*Enrollment) ProtoMessage()    {}
+func (*Enrollment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{52}
+}
+func (m *Enrollment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Enrollment.Unmarshal(m, b)
+}
+func (m *Enrollment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Enrollment.Marshal(b, m, deterministic)
+}
+func (dst *Enrollment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Enrollment.Merge(dst, src)
+}
+func (m *Enrollment) XXX_Size() int {
+	return xxx_messageInfo_Enrollment.Size(m)
+}
+func (m *Enrollment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Enrollment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Enrollment proto.InternalMessageInfo
+
+func (m *Enrollment) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *Enrollment) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *Enrollment) GetDevices() []*EnrollmentDevice {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+func (m *Enrollment) GetYubiKeys() []*EnrollmentYubiKey {
+	if m != nil {
+		return m.YubiKeys
+	}
+	return nil
+}
+
+func (m *Enrollment) GetRecoveryCodes() []string {
+	if m != nil {
+		return m.RecoveryCodes
+	}
+	return nil
+}
+
+type EnrollmentDevice struct {
+	ID                   string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Secret               string   `protobuf:"bytes,3,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,4,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	ConfirmedAt          int64    `protobuf:"varint,5,opt,name=ConfirmedAt,proto3" json:"ConfirmedAt,omitempty"`
+	Digits               int32    `protobuf:"varint,6,opt,name=Digits,proto3" json:"Digits,omitempty"`
+	Period               int32    `protobuf:"varint,7,opt,name=Period,proto3" json:"Period,omitempty"`
+	Algorithm            string   `protobuf:"bytes,8,opt,name=Algorithm,proto3" json:"Algorithm,omitempty"`
+	Drift                int32    `protobuf:"varint,9,opt,name=Drift,proto3" json:"Drift,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnrollmentDevice) Reset()         { *m = EnrollmentDevice{} }
+func (m *EnrollmentDevice) String() string { return proto.CompactTextString(m) }
+func (*EnrollmentDevice) ProtoMessage()    {}
+func (*EnrollmentDevice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{53}
+}
+func (m *EnrollmentDevice) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnrollmentDevice.Unmarshal(m, b)
+}
+func (m *EnrollmentDevice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnrollmentDevice.Marshal(b, m, deterministic)
+}
+func (dst *EnrollmentDevice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnrollmentDevice.Merge(dst, src)
+}
+func (m *EnrollmentDevice) XXX_Size() int {
+	return xxx_messageInfo_EnrollmentDevice.Size(m)
+}
+func (m *EnrollmentDevice) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnrollmentDevice.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnrollmentDevice proto.InternalMessageInfo
+
+func (m *EnrollmentDevice) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *EnrollmentDevice) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *EnrollmentDevice) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *EnrollmentDevice) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *EnrollmentDevice) GetConfirmedAt() int64 {
+	if m != nil {
+		return m.ConfirmedAt
+	}
+	return 0
+}
+
+func (m *EnrollmentDevice) GetDigits() int32 {
+	if m != nil {
+		return m.Digits
+	}
+	return 0
+}
+
+func (m *EnrollmentDevice) GetPeriod() int32 {
+	if m != nil {
+		return m.Period
+	}
+	return 0
+}
+
+func (m *EnrollmentDevice) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ""
+}
+
+func (m *EnrollmentDevice) GetDrift() int32 {
+	if m != nil {
+		return m.Drift
+	}
+	return 0
+}
+
+type EnrollmentYubiKey struct {
+	PublicID             string   `protobuf:"bytes,1,opt,name=PublicID,proto3" json:"PublicID,omitempty"`
+	PrivateID            string   `protobuf:"bytes,2,opt,name=PrivateID,proto3" json:"PrivateID,omitempty"`
+	AesKey               string   `protobuf:"bytes,3,opt,name=AesKey,proto3" json:"AesKey,omitempty"`
+	UsageCounter         uint32   `protobuf:"varint,4,opt,name=UsageCounter,proto3" json:"UsageCounter,omitempty"`
+	SessionCounter       uint32   `protobuf:"varint,5,opt,name=SessionCounter,proto3" json:"SessionCounter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnrollmentYubiKey) Reset()         { *m = EnrollmentYubiKey{} }
+func (m *EnrollmentYubiKey) String() string { return proto.CompactTextString(m) }
+func (*EnrollmentYubiKey) ProtoMessage()    {}
+func (*EnrollmentYubiKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{54}
+}
+func (m *EnrollmentYubiKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnrollmentYubiKey.Unmarshal(m, b)
+}
+func (m *EnrollmentYubiKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnrollmentYubiKey.Marshal(b, m, deterministic)
+}
+func (dst *EnrollmentYubiKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnrollmentYubiKey.Merge(dst, src)
+}
+func (m *EnrollmentYubiKey) XXX_Size() int {
+	return xxx_messageInfo_EnrollmentYubiKey.Size(m)
+}
+func (m *EnrollmentYubiKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnrollmentYubiKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnrollmentYubiKey proto.InternalMessageInfo
+
+func (m *EnrollmentYubiKey) GetPublicID() string {
+	if m != nil {
+		return m.PublicID
+	}
+	return ""
+}
+
+func (m *EnrollmentYubiKey) GetPrivateID() string {
+	if m != nil {
+		return m.PrivateID
+	}
+	return ""
+}
+
+func (m *EnrollmentYubiKey) GetAesKey() string {
+	if m != nil {
+		return m.AesKey
+	}
+	return ""
+}
+
+func (m *EnrollmentYubiKey) GetUsageCounter() uint32 {
+	if m != nil {
+		return m.UsageCounter
+	}
+	return 0
+}
+
+func (m *EnrollmentYubiKey) GetSessionCounter() uint32 {
+	if m != nil {
+		return m.SessionCounter
+	}
+	return 0
+}
+
+type RestoreResult struct {
+	Index      int32  `protobuf:"varint,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	UserID     string `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ProviderID string `protobuf:"bytes,3,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	// Status is restored, skipped or failed.
+	Status               string   `protobuf:"bytes,4,opt,name=Status,proto3" json:"Status,omitempty"`
+	Error                string   `protobuf:"bytes,5,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreResult) Reset()         { *m = RestoreResult{} }
+func (m *RestoreResult) String() string { return proto.CompactTextString(m) }
+func (*RestoreResult) ProtoMessage()    {}
+func (*RestoreResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{55}
+}
+func (m *RestoreResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreResult.Unmarshal(m, b)
+}
+func (m *RestoreResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreResult.Marshal(b, m, deterministic)
+}
+func (dst *RestoreResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreResult.Merge(dst, src)
+}
+func (m *RestoreResult) XXX_Size() int {
+	return xxx_messageInfo_RestoreResult.Size(m)
+}
+func (m *RestoreResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreResult proto.InternalMessageInfo
+
+func (m *RestoreResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *RestoreResult) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *RestoreResult) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *RestoreResult) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *RestoreResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type MfaImportMigrationDataRequest struct {
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID     string `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	// URI is an otpauth-migration URI exported by Google Authenticator.
+	URI                  string   `protobuf:"bytes,3,opt,name=URI,proto3" json:"URI,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaImportMigrationDataRequest) Reset()         { *m = MfaImportMigrationDataRequest{} }
+func (m *MfaImportMigrationDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaImportMigrationDataRequest) ProtoMessage()    {}
+func (*MfaImportMigrationDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{56}
+}
+func (m *MfaImportMigrationDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaImportMigrationDataRequest.Unmarshal(m, b)
+}
+func (m *MfaImportMigrationDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaImportMigrationDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaImportMigrationDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaImportMigrationDataRequest.Merge(dst, src)
+}
+func (m *MfaImportMigrationDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaImportMigrationDataRequest.Size(m)
+}
+func (m *MfaImportMigrationDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaImportMigrationDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaImportMigrationDataRequest proto.InternalMessageInfo
+
+func (m *MfaImportMigrationDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaImportMigrationDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaImportMigrationDataRequest) GetURI() string {
+	if m != nil {
+		return m.URI
+	}
+	return ""
+}
+
+type MfaImportMigrationDataResponse struct {
+	Results              []*ImportResult `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+	Imported             int32           `protobuf:"varint,2,opt,name=Imported,proto3" json:"Imported,omitempty"`
+	Failed               int32           `protobuf:"varint,3,opt,name=Failed,proto3" json:"Failed,omitempty"`
+	Error                *Error          `protobuf:"bytes,4,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MfaImportMigrationDataResponse) Reset()         { *m = MfaImportMigrationDataResponse{} }
+func (m *MfaImportMigrationDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaImportMigrationDataResponse) ProtoMessage()    {}
+func (*MfaImportMigrationDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{57}
+}
+func (m *MfaImportMigrationDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaImportMigrationDataResponse.Unmarshal(m, b)
+}
+func (m *MfaImportMigrationDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaImportMigrationDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaImportMigrationDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaImportMigrationDataResponse.Merge(dst, src)
+}
+func (m *MfaImportMigrationDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaImportMigrationDataResponse.Size(m)
+}
+func (m *MfaImportMigrationDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaImportMigrationDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaImportMigrationDataResponse proto.InternalMessageInfo
+
+func (m *MfaImportMigrationDataResponse) GetResults() []*ImportResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *MfaImportMigrationDataResponse) GetImported() int32 {
+	if m != nil {
+		return m.Imported
+	}
+	return 0
+}
+
+func (m *MfaImportMigrationDataResponse) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *MfaImportMigrationDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type MfaExportMigrationDataRequest struct {
+	ProviderID string `protobuf:"bytes,1,opt,name=ProviderID,proto3" json:"ProviderID,omitempty"`
+	UserID     string `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	AppName    string `protobuf:"bytes,3,opt,name=AppName,proto3" json:"AppName,omitempty"`
+	Email      string `protobuf:"bytes,4,opt,name=Email,proto3" json:"Email,omitempty"`
+	QrSize     int32  `protobuf:"varint,5,opt,name=QrSize,proto3" json:"QrSize,omitempty"`
+	// Code is a current code of the user, the secrets are not exported without it.
+	Code                 string   `protobuf:"bytes,6,opt,name=Code,proto3" json:"Code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaExportMigrationDataRequest) Reset()         { *m = MfaExportMigrationDataRequest{} }
+func (m *MfaExportMigrationDataRequest) String() string { return proto.CompactTextString(m) }
+func (*MfaExportMigrationDataRequest) ProtoMessage()    {}
+func (*MfaExportMigrationDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{58}
+}
+func (m *MfaExportMigrationDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaExportMigrationDataRequest.Unmarshal(m, b)
+}
+func (m *MfaExportMigrationDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaExportMigrationDataRequest.Marshal(b, m, deterministic)
+}
+func (dst *MfaExportMigrationDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaExportMigrationDataRequest.Merge(dst, src)
+}
+func (m *MfaExportMigrationDataRequest) XXX_Size() int {
+	return xxx_messageInfo_MfaExportMigrationDataRequest.Size(m)
+}
+func (m *MfaExportMigrationDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaExportMigrationDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaExportMigrationDataRequest proto.InternalMessageInfo
+
+func (m *MfaExportMigrationDataRequest) GetProviderID() string {
+	if m != nil {
+		return m.ProviderID
+	}
+	return ""
+}
+
+func (m *MfaExportMigrationDataRequest) GetUserID() string {
+	if m != nil {
+		return m.UserID
+	}
+	return ""
+}
+
+func (m *MfaExportMigrationDataRequest) GetAppName() string {
+	if m != nil {
+		return m.AppName
+	}
+	return ""
+}
+
+func (m *MfaExportMigrationDataRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *MfaExportMigrationDataRequest) GetQrSize() int32 {
+	if m != nil {
+		return m.QrSize
+	}
+	return 0
+}
+
+func (m *MfaExportMigrationDataRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type MfaExportMigrationDataResponse struct {
+	URI                  string   `protobuf:"bytes,1,opt,name=URI,proto3" json:"URI,omitempty"`
+	ImageBased           string   `protobuf:"bytes,2,opt,name=ImageBased,proto3" json:"ImageBased,omitempty"`
+	Exported             int32    `protobuf:"varint,3,opt,name=Exported,proto3" json:"Exported,omitempty"`
+	Error                *Error   `protobuf:"bytes,4,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MfaExportMigrationDataResponse) Reset()         { *m = MfaExportMigrationDataResponse{} }
+func (m *MfaExportMigrationDataResponse) String() string { return proto.CompactTextString(m) }
+func (*MfaExportMigrationDataResponse) ProtoMessage()    {}
+func (*MfaExportMigrationDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{59}
+}
+func (m *MfaExportMigrationDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MfaExportMigrationDataResponse.Unmarshal(m, b)
+}
+func (m *MfaExportMigrationDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MfaExportMigrationDataResponse.Marshal(b, m, deterministic)
+}
+func (dst *MfaExportMigrationDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MfaExportMigrationDataResponse.Merge(dst, src)
+}
+func (m *MfaExportMigrationDataResponse) XXX_Size() int {
+	return xxx_messageInfo_MfaExportMigrationDataResponse.Size(m)
+}
+func (m *MfaExportMigrationDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MfaExportMigrationDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MfaExportMigrationDataResponse proto.InternalMessageInfo
+
+func (m *MfaExportMigrationDataResponse) GetURI() string {
+	if m != nil {
+		return m.URI
+	}
+	return ""
+}
+
+func (m *MfaExportMigrationDataResponse) GetImageBased() string {
+	if m != nil {
+		return m.ImageBased
+	}
+	return ""
+}
+
+func (m *MfaExportMigrationDataResponse) GetExported() int32 {
+	if m != nil {
+		return m.Exported
+	}
+	return 0
+}
+
+func (m *MfaExportMigrationDataResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type Error struct {
+	Message              string   `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Error) Reset()         { *m = Error{} }
+func (m *Error) String() string { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()    {}
+func (*Error) Descriptor() ([]byte, []int) {
+	return fileDescriptor_mfa_00c2b63dd1e77d1e, []int{60}
+}
+func (m *Error) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Error.Unmarshal(m, b)
+}
+func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+}
+func (dst *Error) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Error.Merge(dst, src)
+}
+func (m *Error) XXX_Size() int {
+	return xxx_messageInfo_Error.Size(m)
+}
+func (m *Error) XXX_DiscardUnknown() {
+	xxx_messageInfo_Error.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Error proto.InternalMessageInfo
+
+func (m *Error) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*MfaCreateDataRequest)(nil), "proto.MfaCreateDataRequest")
+	proto.RegisterType((*MfaCreateDataResponse)(nil), "proto.MfaCreateDataResponse")
+	proto.RegisterType((*MfaCheckDataRequest)(nil), "proto.MfaCheckDataRequest")
+	proto.RegisterType((*MfaCheckDataResponse)(nil), "proto.MfaCheckDataResponse")
+	proto.RegisterType((*MfaAddYubiKeyDataRequest)(nil), "proto.MfaAddYubiKeyDataRequest")
+	proto.RegisterType((*MfaAddYubiKeyDataResponse)(nil), "proto.MfaAddYubiKeyDataResponse")
+	proto.RegisterType((*MfaListDevicesDataRequest)(nil), "proto.MfaListDevicesDataRequest")
+	proto.RegisterType((*MfaListDevicesDataResponse)(nil), "proto.MfaListDevicesDataResponse")
+	proto.RegisterType((*MfaRenameDeviceDataRequest)(nil), "proto.MfaRenameDeviceDataRequest")
+	proto.RegisterType((*MfaRenameDeviceDataResponse)(nil), "proto.MfaRenameDeviceDataResponse")
+	proto.RegisterType((*MfaRemoveDeviceDataRequest)(nil), "proto.MfaRemoveDeviceDataRequest")
+	proto.RegisterType((*MfaRemoveDeviceDataResponse)(nil), "proto.MfaRemoveDeviceDataResponse")
+	proto.RegisterType((*Device)(nil), "proto.Device")
+	proto.RegisterType((*MfaValidateTrustedDeviceDataRequest)(nil), "proto.MfaValidateTrustedDeviceDataRequest")
+	proto.RegisterType((*MfaValidateTrustedDeviceDataResponse)(nil), "proto.MfaValidateTrustedDeviceDataResponse")
+	proto.RegisterType((*MfaListTrustedDevicesDataRequest)(nil), "proto.MfaListTrustedDevicesDataRequest")
+	proto.RegisterType((*MfaListTrustedDevicesDataResponse)(nil), "proto.MfaListTrustedDevicesDataResponse")
+	proto.RegisterType((*MfaRevokeTrustedDeviceDataRequest)(nil), "proto.MfaRevokeTrustedDeviceDataRequest")
+	proto.RegisterType((*MfaRevokeTrustedDeviceDataResponse)(nil), "proto.MfaRevokeTrustedDeviceDataResponse")
+	proto.RegisterType((*TrustedDevice)(nil), "proto.TrustedDevice")
+	proto.RegisterType((*MfaQueryAuditEventsDataRequest)(nil), "proto.MfaQueryAuditEventsDataRequest")
+	proto.RegisterType((*MfaQueryAuditEventsDataResponse)(nil), "proto.MfaQueryAuditEventsDataResponse")
+	proto.RegisterType((*AuditEvent)(nil), "proto.AuditEvent")
+	proto.RegisterType((*ProviderConfig)(nil), "proto.ProviderConfig")
+	proto.RegisterType((*MfaGetProviderConfigDataRequest)(nil), "proto.MfaGetProviderConfigDataRequest")
+	proto.RegisterType((*MfaGetProviderConfigDataResponse)(nil), "proto.MfaGetProviderConfigDataResponse")
+	proto.RegisterType((*MfaSetProviderConfigDataRequest)(nil), "proto.MfaSetProviderConfigDataRequest")
+	proto.RegisterType((*MfaSetProviderConfigDataResponse)(nil), "proto.MfaSetProviderConfigDataResponse")
+	proto.RegisterType((*MfaListProviderConfigsDataRequest)(nil), "proto.MfaListProviderConfigsDataRequest")
+	proto.RegisterType((*MfaListProviderConfigsDataResponse)(nil), "proto.MfaListProviderConfigsDataResponse")
+	proto.RegisterType((*MfaDeleteProviderConfigDataRequest)(nil), "proto.MfaDeleteProviderConfigDataRequest")
+	proto.RegisterType((*MfaDeleteProviderConfigDataResponse)(nil), "proto.MfaDeleteProviderConfigDataResponse")
+	proto.RegisterType((*MfaGetUserStatusDataRequest)(nil), "proto.MfaGetUserStatusDataRequest")
+	proto.RegisterType((*MfaGetUserStatusDataResponse)(nil), "proto.MfaGetUserStatusDataResponse")
+	proto.RegisterType((*MfaResetEnrollmentDataRequest)(nil), "proto.MfaResetEnrollmentDataRequest")
+	proto.RegisterType((*MfaResetEnrollmentDataResponse)(nil), "proto.MfaResetEnrollmentDataResponse")
+	proto.RegisterType((*MfaClearLockoutDataRequest)(nil), "proto.MfaClearLockoutDataRequest")
+	proto.RegisterType((*MfaClearLockoutDataResponse)(nil), "proto.MfaClearLockoutDataResponse")
+	proto.RegisterType((*MfaIssueBypassCodeDataRequest)(nil), "proto.MfaIssueBypassCodeDataRequest")
+	proto.RegisterType((*MfaIssueBypassCodeDataResponse)(nil), "proto.MfaIssueBypassCodeDataResponse")
+	proto.RegisterType((*MfaRequestAccountRecoveryDataRequest)(nil), "proto.MfaRequestAccountRecoveryDataRequest")
+	proto.RegisterType((*MfaRequestAccountRecoveryDataResponse)(nil), "proto.MfaRequestAccountRecoveryDataResponse")
+	proto.RegisterType((*MfaRotateSecretDataRequest)(nil), "proto.MfaRotateSecretDataRequest")
+	proto.RegisterType((*MfaRotateSecretDataResponse)(nil), "proto.MfaRotateSecretDataResponse")
+	proto.RegisterType((*MfaImportEnrollmentDataRequest)(nil), "proto.MfaImportEnrollmentDataRequest")
+	proto.RegisterType((*MfaImportEnrollmentDataResponse)(nil), "proto.MfaImportEnrollmentDataResponse")
+	proto.RegisterType((*ImportRecord)(nil), "proto.ImportRecord")
+	proto.RegisterType((*ImportResult)(nil), "proto.ImportResult")
+	proto.RegisterType((*MfaExportEnrollmentsDataRequest)(nil), "proto.MfaExportEnrollmentsDataRequest")
+	proto.RegisterType((*ArchiveChunk)(nil), "proto.ArchiveChunk")
+	proto.RegisterType((*MfaRestoreEnrollmentsDataRequest)(nil), "proto.MfaRestoreEnrollmentsDataRequest")
+	proto.RegisterType((*MfaRestoreEnrollmentsDataResponse)(nil), "proto.MfaRestoreEnrollmentsDataResponse")
+	proto.RegisterType((*Enrollment)(nil), "proto.Enrollment")
+	proto.RegisterType((*EnrollmentDevice)(nil), "proto.EnrollmentDevice")
+	proto.RegisterType((*EnrollmentYubiKey)(nil), "proto.EnrollmentYubiKey")
+	proto.RegisterType((*RestoreResult)(nil), "proto.RestoreResult")
+	proto.RegisterType((*MfaImportMigrationDataRequest)(nil), "proto.MfaImportMigrationDataRequest")
+	proto.RegisterType((*MfaImportMigrationDataResponse)(nil), "proto.MfaImportMigrationDataResponse")
+	proto.RegisterType((*MfaExportMigrationDataRequest)(nil), "proto.MfaExportMigrationDataRequest")
+	proto.RegisterType((*MfaExportMigrationDataResponse)(nil), "proto.MfaExportMigrationDataResponse")
+	proto.RegisterType((*Error)(nil), "proto.Error")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// MfaServiceClient is the client API for MfaService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MfaServiceClient interface {
+	Create(ctx context.Context, in *MfaCreateDataRequest, opts ...grpc.CallOption) (*MfaCreateDataResponse, error)
+	Check(ctx context.Context, in *MfaCheckDataRequest, opts ...grpc.CallOption) (*MfaCheckDataResponse, error)
+	AddYubiKey(ctx context.Context, in *MfaAddYubiKeyDataRequest, opts ...grpc.CallOption) (*MfaAddYubiKeyDataResponse, error)
+	ListDevices(ctx context.Context, in *MfaListDevicesDataRequest, opts ...grpc.CallOption) (*MfaListDevicesDataResponse, error)
+	RenameDevice(ctx context.Context, in *MfaRenameDeviceDataRequest, opts ...grpc.CallOption) (*MfaRenameDeviceDataResponse, error)
+	RemoveDevice(ctx context.Context, in *MfaRemoveDeviceDataRequest, opts ...grpc.CallOption) (*MfaRemoveDeviceDataResponse, error)
+	ValidateTrustedDevice(ctx context.Context, in *MfaValidateTrustedDeviceDataRequest, opts ...grpc.CallOption) (*MfaValidateTrustedDeviceDataResponse, error)
+	ListTrustedDevices(ctx context.Context, in *MfaListTrustedDevicesDataRequest, opts ...grpc.CallOption) (*MfaListTrustedDevicesDataResponse, error)
+	RevokeTrustedDevice(ctx context.Context, in *MfaRevokeTrustedDeviceDataRequest, opts ...grpc.CallOption) (*MfaRevokeTrustedDeviceDataResponse, error)
+	QueryAuditEvents(ctx context.Context, in *MfaQueryAuditEventsDataRequest, opts ...grpc.CallOption) (*MfaQueryAuditEventsDataResponse, error)
+	GetProviderConfig(ctx context.Context, in *MfaGetProviderConfigDataRequest, opts ...grpc.CallOption) (*MfaGetProviderConfigDataResponse, error)
+	SetProviderConfig(ctx context.Context, in *MfaSetProviderConfigDataRequest, opts ...grpc.CallOption) (*MfaSetProviderConfigDataResponse, error)
+	ListProviderConfigs(ctx context.Context, in *MfaListProviderConfigsDataRequest, opts ...grpc.CallOption) (*MfaListProviderConfigsDataResponse, error)
+	DeleteProviderConfig(ctx context.Context, in *MfaDeleteProviderConfigDataRequest, opts ...grpc.CallOption) (*MfaDeleteProviderConfigDataResponse, error)
+	GetUserStatus(ctx context.Context, in *MfaGetUserStatusDataRequest, opts ...grpc.CallOption) (*MfaGetUserStatusDataResponse, error)
+	ResetEnrollment(ctx context.Context, in *MfaResetEnrollmentDataRequest, opts ...grpc.CallOption) (*MfaResetEnrollmentDataResponse, error)
+	ClearLockout(ctx context.Context, in *MfaClearLockoutDataRequest, opts ...grpc.CallOption) (*MfaClearLockoutDataResponse, error)
+	IssueBypassCode(ctx context.Context, in *MfaIssueBypassCodeDataRequest, opts ...grpc.CallOption) (*MfaIssueBypassCodeDataResponse, error)
+	RequestAccountRecovery(ctx context.Context, in *MfaRequestAccountRecoveryDataRequest, opts ...grpc.CallOption) (*MfaRequestAccountRecoveryDataResponse, error)
+	RotateSecret(ctx context.Context, in *MfaRotateSecretDataRequest, opts ...grpc.CallOption) (*MfaRotateSecretDataResponse, error)
+	ImportEnrollment(ctx context.Context, in *MfaImportEnrollmentDataRequest, opts ...grpc.CallOption) (*MfaImportEnrollmentDataResponse, error)
+	ExportEnrollments(ctx context.Context, in *MfaExportEnrollmentsDataRequest, opts ...grpc.CallOption) (MfaService_ExportEnrollmentsClient, error)
+	RestoreEnrollments(ctx context.Context, in *MfaRestoreEnrollmentsDataRequest, opts ...grpc.CallOption) (*MfaRestoreEnrollmentsDataResponse, error)
+	ImportMigration(ctx context.Context, in *MfaImportMigrationDataRequest, opts ...grpc.CallOption) (*MfaImportMigrationDataResponse, error)
+	ExportMigration(ctx context.Context, in *MfaExportMigrationDataRequest, opts ...grpc.CallOption) (*MfaExportMigrationDataResponse, error)
+}
+
+type mfaServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMfaServiceClient(cc *grpc.ClientConn) MfaServiceClient {
+	return &mfaServiceClient{cc}
+}
+
+func (c *mfaServiceClient) Create(ctx context.Context, in *MfaCreateDataRequest, opts ...grpc.CallOption) (*MfaCreateDataResponse, error) {
+	out := new(MfaCreateDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) Check(ctx context.Context, in *MfaCheckDataRequest, opts ...grpc.CallOption) (*MfaCheckDataResponse, error) {
+	out := new(MfaCheckDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/Check", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) AddYubiKey(ctx context.Context, in *MfaAddYubiKeyDataRequest, opts ...grpc.CallOption) (*MfaAddYubiKeyDataResponse, error) {
+	out := new(MfaAddYubiKeyDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/AddYubiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ListDevices(ctx context.Context, in *MfaListDevicesDataRequest, opts ...grpc.CallOption) (*MfaListDevicesDataResponse, error) {
+	out := new(MfaListDevicesDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ListDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) RenameDevice(ctx context.Context, in *MfaRenameDeviceDataRequest, opts ...grpc.CallOption) (*MfaRenameDeviceDataResponse, error) {
+	out := new(MfaRenameDeviceDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/RenameDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) RemoveDevice(ctx context.Context, in *MfaRemoveDeviceDataRequest, opts ...grpc.CallOption) (*MfaRemoveDeviceDataResponse, error) {
+	out := new(MfaRemoveDeviceDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/RemoveDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ValidateTrustedDevice(ctx context.Context, in *MfaValidateTrustedDeviceDataRequest, opts ...grpc.CallOption) (*MfaValidateTrustedDeviceDataResponse, error) {
+	out := new(MfaValidateTrustedDeviceDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ValidateTrustedDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ListTrustedDevices(ctx context.Context, in *MfaListTrustedDevicesDataRequest, opts ...grpc.CallOption) (*MfaListTrustedDevicesDataResponse, error) {
+	out := new(MfaListTrustedDevicesDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ListTrustedDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) RevokeTrustedDevice(ctx context.Context, in *MfaRevokeTrustedDeviceDataRequest, opts ...grpc.CallOption) (*MfaRevokeTrustedDeviceDataResponse, error) {
+	out := new(MfaRevokeTrustedDeviceDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/RevokeTrustedDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) QueryAuditEvents(ctx context.Context, in *MfaQueryAuditEventsDataRequest, opts ...grpc.CallOption) (*MfaQueryAuditEventsDataResponse, error) {
+	out := new(MfaQueryAuditEventsDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/QueryAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) GetProviderConfig(ctx context.Context, in *MfaGetProviderConfigDataRequest, opts ...grpc.CallOption) (*MfaGetProviderConfigDataResponse, error) {
+	out := new(MfaGetProviderConfigDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/GetProviderConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) SetProviderConfig(ctx context.Context, in *MfaSetProviderConfigDataRequest, opts ...grpc.CallOption) (*MfaSetProviderConfigDataResponse, error) {
+	out := new(MfaSetProviderConfigDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/SetProviderConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ListProviderConfigs(ctx context.Context, in *MfaListProviderConfigsDataRequest, opts ...grpc.CallOption) (*MfaListProviderConfigsDataResponse, error) {
+	out := new(MfaListProviderConfigsDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ListProviderConfigs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) DeleteProviderConfig(ctx context.Context, in *MfaDeleteProviderConfigDataRequest, opts ...grpc.CallOption) (*MfaDeleteProviderConfigDataResponse, error) {
+	out := new(MfaDeleteProviderConfigDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/DeleteProviderConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) GetUserStatus(ctx context.Context, in *MfaGetUserStatusDataRequest, opts ...grpc.CallOption) (*MfaGetUserStatusDataResponse, error) {
+	out := new(MfaGetUserStatusDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/GetUserStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ResetEnrollment(ctx context.Context, in *MfaResetEnrollmentDataRequest, opts ...grpc.CallOption) (*MfaResetEnrollmentDataResponse, error) {
+	out := new(MfaResetEnrollmentDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ResetEnrollment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ClearLockout(ctx context.Context, in *MfaClearLockoutDataRequest, opts ...grpc.CallOption) (*MfaClearLockoutDataResponse, error) {
+	out := new(MfaClearLockoutDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ClearLockout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) IssueBypassCode(ctx context.Context, in *MfaIssueBypassCodeDataRequest, opts ...grpc.CallOption) (*MfaIssueBypassCodeDataResponse, error) {
+	out := new(MfaIssueBypassCodeDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/IssueBypassCode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) RequestAccountRecovery(ctx context.Context, in *MfaRequestAccountRecoveryDataRequest, opts ...grpc.CallOption) (*MfaRequestAccountRecoveryDataResponse, error) {
+	out := new(MfaRequestAccountRecoveryDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/RequestAccountRecovery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) RotateSecret(ctx context.Context, in *MfaRotateSecretDataRequest, opts ...grpc.CallOption) (*MfaRotateSecretDataResponse, error) {
+	out := new(MfaRotateSecretDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/RotateSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ImportEnrollment(ctx context.Context, in *MfaImportEnrollmentDataRequest, opts ...grpc.CallOption) (*MfaImportEnrollmentDataResponse, error) {
+	out := new(MfaImportEnrollmentDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ImportEnrollment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ExportEnrollments(ctx context.Context, in *MfaExportEnrollmentsDataRequest, opts ...grpc.CallOption) (MfaService_ExportEnrollmentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MfaService_serviceDesc.Streams[0], "/proto.MfaService/ExportEnrollments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mfaServiceExportEnrollmentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MfaService_ExportEnrollmentsClient interface {
+	Recv() (*ArchiveChunk, error)
+	grpc.ClientStream
+}
+
+type mfaServiceExportEnrollmentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *mfaServiceExportEnrollmentsClient) Recv() (*ArchiveChunk, error) {
+	m := new(ArchiveChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mfaServiceClient) RestoreEnrollments(ctx context.Context, in *MfaRestoreEnrollmentsDataRequest, opts ...grpc.CallOption) (*MfaRestoreEnrollmentsDataResponse, error) {
+	out := new(MfaRestoreEnrollmentsDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/RestoreEnrollments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ImportMigration(ctx context.Context, in *MfaImportMigrationDataRequest, opts ...grpc.CallOption) (*MfaImportMigrationDataResponse, error) {
+	out := new(MfaImportMigrationDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ImportMigration", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mfaServiceClient) ExportMigration(ctx context.Context, in *MfaExportMigrationDataRequest, opts ...grpc.CallOption) (*MfaExportMigrationDataResponse, error) {
+	out := new(MfaExportMigrationDataResponse)
+	err := c.cc.Invoke(ctx, "/proto.MfaService/ExportMigration", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MfaServiceServer is the server API for MfaService service.
+type MfaServiceServer interface {
+	Create(context.Context, *MfaCreateDataRequest) (*MfaCreateDataResponse, error)
+	Check(context.Context, *MfaCheckDataRequest) (*MfaCheckDataResponse, error)
+	AddYubiKey(context.Context, *MfaAddYubiKeyDataRequest) (*MfaAddYubiKeyDataResponse, error)
+	ListDevices(context.Context, *MfaListDevicesDataRequest) (*MfaListDevicesDataResponse, error)
+	RenameDevice(context.Context, *MfaRenameDeviceDataRequest) (*MfaRenameDeviceDataResponse, error)
+	RemoveDevice(context.Context, *MfaRemoveDeviceDataRequest) (*MfaRemoveDeviceDataResponse, error)
+	ValidateTrustedDevice(context.Context, *MfaValidateTrustedDeviceDataRequest) (*MfaValidateTrustedDeviceDataResponse, error)
+	ListTrustedDevices(context.Context, *MfaListTrustedDevicesDataRequest) (*MfaListTrustedDevicesDataResponse, error)
+	RevokeTrustedDevice(context.Context, *MfaRevokeTrustedDeviceDataRequest) (*MfaRevokeTrustedDeviceDataResponse, error)
+	QueryAuditEvents(context.Context, *MfaQueryAuditEventsDataRequest) (*MfaQueryAuditEventsDataResponse, error)
+	GetProviderConfig(context.Context, *MfaGetProviderConfigDataRequest) (*MfaGetProviderConfigDataResponse, error)
+	SetProviderConfig(context.Context, *MfaSetProviderConfigDataRequest) (*MfaSetProviderConfigDataResponse, error)
+	ListProviderConfigs(context.Context, *MfaListProviderConfigsDataRequest) (*MfaListProviderConfigsDataResponse, error)
+	DeleteProviderConfig(context.Context, *MfaDeleteProviderConfigDataRequest) (*MfaDeleteProviderConfigDataResponse, error)
+	GetUserStatus(context.Context, *MfaGetUserStatusDataRequest) (*MfaGetUserStatusDataResponse, error)
+	ResetEnrollment(context.Context, *MfaResetEnrollmentDataRequest) (*MfaResetEnrollmentDataResponse, error)
+	ClearLockout(context.Context, *MfaClearLockoutDataRequest) (*MfaClearLockoutDataResponse, error)
+	IssueBypassCode(context.Context, *MfaIssueBypassCodeDataRequest) (*MfaIssueBypassCodeDataResponse, error)
+	RequestAccountRecovery(context.Context, *MfaRequestAccountRecoveryDataRequest) (*MfaRequestAccountRecoveryDataResponse, error)
+	RotateSecret(context.Context, *MfaRotateSecretDataRequest) (*MfaRotateSecretDataResponse, error)
+	ImportEnrollment(context.Context, *MfaImportEnrollmentDataRequest) (*MfaImportEnrollmentDataResponse, error)
+	ExportEnrollments(*MfaExportEnrollmentsDataRequest, MfaService_ExportEnrollmentsServer) error
+	RestoreEnrollments(context.Context, *MfaRestoreEnrollmentsDataRequest) (*MfaRestoreEnrollmentsDataResponse, error)
+	ImportMigration(context.Context, *MfaImportMigrationDataRequest) (*MfaImportMigrationDataResponse, error)
+	ExportMigration(context.Context, *MfaExportMigrationDataRequest) (*MfaExportMigrationDataResponse, error)
+}
+
+func RegisterMfaServiceServer(s *grpc.Server, srv MfaServiceServer) {
+	s.RegisterService(&_MfaService_serviceDesc, srv)
+}
+
+func _MfaService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaCreateDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).Create(ctx, req.(*MfaCreateDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaCheckDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/Check",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).Check(ctx, req.(*MfaCheckDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_AddYubiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaAddYubiKeyDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).AddYubiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/AddYubiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).AddYubiKey(ctx, req.(*MfaAddYubiKeyDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaListDevicesDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ListDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ListDevices(ctx, req.(*MfaListDevicesDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_RenameDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaRenameDeviceDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).RenameDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/RenameDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).RenameDevice(ctx, req.(*MfaRenameDeviceDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_RemoveDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaRemoveDeviceDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).RemoveDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/RemoveDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).RemoveDevice(ctx, req.(*MfaRemoveDeviceDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ValidateTrustedDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaValidateTrustedDeviceDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ValidateTrustedDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ValidateTrustedDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ValidateTrustedDevice(ctx, req.(*MfaValidateTrustedDeviceDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ListTrustedDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaListTrustedDevicesDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ListTrustedDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ListTrustedDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ListTrustedDevices(ctx, req.(*MfaListTrustedDevicesDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_RevokeTrustedDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaRevokeTrustedDeviceDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).RevokeTrustedDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/RevokeTrustedDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).RevokeTrustedDevice(ctx, req.(*MfaRevokeTrustedDeviceDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_QueryAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaQueryAuditEventsDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).QueryAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/QueryAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).QueryAuditEvents(ctx, req.(*MfaQueryAuditEventsDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_GetProviderConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaGetProviderConfigDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).GetProviderConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/GetProviderConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).GetProviderConfig(ctx, req.(*MfaGetProviderConfigDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_SetProviderConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaSetProviderConfigDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).SetProviderConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/SetProviderConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).SetProviderConfig(ctx, req.(*MfaSetProviderConfigDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ListProviderConfigs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaListProviderConfigsDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ListProviderConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ListProviderConfigs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ListProviderConfigs(ctx, req.(*MfaListProviderConfigsDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_DeleteProviderConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaDeleteProviderConfigDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).DeleteProviderConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/DeleteProviderConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).DeleteProviderConfig(ctx, req.(*MfaDeleteProviderConfigDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_GetUserStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaGetUserStatusDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).GetUserStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/GetUserStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).GetUserStatus(ctx, req.(*MfaGetUserStatusDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ResetEnrollment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaResetEnrollmentDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ResetEnrollment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ResetEnrollment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ResetEnrollment(ctx, req.(*MfaResetEnrollmentDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ClearLockout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaClearLockoutDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ClearLockout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ClearLockout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ClearLockout(ctx, req.(*MfaClearLockoutDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_IssueBypassCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaIssueBypassCodeDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).IssueBypassCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/IssueBypassCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).IssueBypassCode(ctx, req.(*MfaIssueBypassCodeDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_RequestAccountRecovery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaRequestAccountRecoveryDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).RequestAccountRecovery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/RequestAccountRecovery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).RequestAccountRecovery(ctx, req.(*MfaRequestAccountRecoveryDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_RotateSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaRotateSecretDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).RotateSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/RotateSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).RotateSecret(ctx, req.(*MfaRotateSecretDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ImportEnrollment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaImportEnrollmentDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ImportEnrollment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ImportEnrollment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ImportEnrollment(ctx, req.(*MfaImportEnrollmentDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ExportEnrollments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MfaExportEnrollmentsDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MfaServiceServer).ExportEnrollments(m, &mfaServiceExportEnrollmentsServer{stream})
+}
+
+type MfaService_ExportEnrollmentsServer interface {
+	Send(*ArchiveChunk) error
+	grpc.ServerStream
+}
+
+type mfaServiceExportEnrollmentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *mfaServiceExportEnrollmentsServer) Send(m *ArchiveChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MfaService_RestoreEnrollments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaRestoreEnrollmentsDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).RestoreEnrollments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/RestoreEnrollments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).RestoreEnrollments(ctx, req.(*MfaRestoreEnrollmentsDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ImportMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaImportMigrationDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ImportMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ImportMigration",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ImportMigration(ctx, req.(*MfaImportMigrationDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MfaService_ExportMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MfaExportMigrationDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MfaServiceServer).ExportMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MfaService/ExportMigration",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MfaServiceServer).ExportMigration(ctx, req.(*MfaExportMigrationDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MfaService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.MfaService",
+	HandlerType: (*MfaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _MfaService_Create_Handler,
+		},
+		{
+			MethodName: "Check",
+			Handler:    _MfaService_Check_Handler,
+		},
+		{
+			MethodName: "AddYubiKey",
+			Handler:    _MfaService_AddYubiKey_Handler,
+		},
+		{
+			MethodName: "ListDevices",
+			Handler:    _MfaService_ListDevices_Handler,
+		},
+		{
+			MethodName: "RenameDevice",
+			Handler:    _MfaService_RenameDevice_Handler,
+		},
+		{
+			MethodName: "RemoveDevice",
+			Handler:    _MfaService_RemoveDevice_Handler,
+		},
+		{
+			MethodName: "ValidateTrustedDevice",
+			Handler:    _MfaService_ValidateTrustedDevice_Handler,
+		},
+		{
+			MethodName: "ListTrustedDevices",
+			Handler:    _MfaService_ListTrustedDevices_Handler,
+		},
+		{
+			MethodName: "RevokeTrustedDevice",
+			Handler:    _MfaService_RevokeTrustedDevice_Handler,
+		},
+		{
+			MethodName: "QueryAuditEvents",
+			Handler:    _MfaService_QueryAuditEvents_Handler,
+		},
+		{
+			MethodName: "GetProviderConfig",
+			Handler:    _MfaService_GetProviderConfig_Handler,
+		},
+		{
+			MethodName: "SetProviderConfig",
+			Handler:    _MfaService_SetProviderConfig_Handler,
+		},
+		{
+			MethodName: "ListProviderConfigs",
+			Handler:    _MfaService_ListProviderConfigs_Handler,
+		},
+		{
+			MethodName: "DeleteProviderConfig",
+			Handler:    _MfaService_DeleteProviderConfig_Handler,
+		},
+		{
+			MethodName: "GetUserStatus",
+			Handler:    _MfaService_GetUserStatus_Handler,
+		},
+		{
+			MethodName: "ResetEnrollment",
+			Handler:    _MfaService_ResetEnrollment_Handler,
+		},
+		{
+			MethodName: "ClearLockout",
+			Handler:    _MfaService_ClearLockout_Handler,
+		},
+		{
+			MethodName: "IssueBypassCode",
+			Handler:    _MfaService_IssueBypassCode_Handler,
+		},
+		{
+			MethodName: "RequestAccountRecovery",
+			Handler:    _MfaService_RequestAccountRecovery_Handler,
+		},
+		{
+			MethodName: "RotateSecret",
+			Handler:    _MfaService_RotateSecret_Handler,
+		},
+		{
+			MethodName: "ImportEnrollment",
+			Handler:    _MfaService_ImportEnrollment_Handler,
+		},
+		{
+			MethodName: "RestoreEnrollments",
+			Handler:    _MfaService_RestoreEnrollments_Handler,
+		},
+		{
+			MethodName: "ImportMigration",
+			Handler:    _MfaService_ImportMigration_Handler,
+		},
+		{
+			MethodName: "ExportMigration",
+			Handler:    _MfaService_ExportMigration_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportEnrollments",
+			Handler:       _MfaService_ExportEnrollments_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "mfa.proto",
+}
+
+func init() { proto.RegisterFile("mfa.proto", fileDescriptor_mfa_00c2b63dd1e77d1e) }
+
+var fileDescriptor_mfa_00c2b63dd1e77d1e = []byte{
+	// 2585 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x1a, 0x4d, 0x6f, 0x23, 0x49,
+	0x75, 0xda, 0x76, 0x3b, 0xf1, 0x73, 0x32, 0x1f, 0x9d, 0xcc, 0x60, 0x7a, 0x86, 0x19, 0x4f, 0xcd,
+	0x57, 0x66, 0x76, 0x77, 0x58, 0x66, 0x39, 0x71, 0x40, 0xf2, 0x24, 0xce, 0x10, 0x6d, 0x02, 0x49,
+	0x3b, 0x61, 0xb5, 0x2b, 0x84, 0xa6, 0x63, 0x57, 0x92, 0x56, 0x6c, 0xb7, 0xa9, 0x2a, 0x67, 0x13,
+	0x24, 0x2e, 0x88, 0x5d, 0x09, 0x71, 0x43, 0x5a, 0x21, 0x71, 0x43, 0x42, 0xc0, 0x15, 0x21, 0x21,
+	0x24, 0x7e, 0x06, 0xbf, 0x82, 0x0b, 0x07, 0x16, 0x89, 0x2b, 0xaa, 0x8f, 0xee, 0xae, 0x6a, 0x77,
+	0xb7, 0x3d, 0x33, 0x66, 0xe0, 0xe4, 0x7e, 0xaf, 0x5e, 0x55, 0xbd, 0xf7, 0xea, 0x7d, 0xd5, 0x2b,
+	0x43, 0x6d, 0x70, 0xe4, 0x3f, 0x1d, 0x91, 0x90, 0x85, 0x8e, 0x2d, 0x7e, 0xd0, 0x9f, 0x2d, 0x58,
+	0xdd, 0x39, 0xf2, 0xd7, 0x09, 0xf6, 0x19, 0xde, 0xf0, 0x99, 0xef, 0xe1, 0x1f, 0x8d, 0x31, 0x65,
+	0xce, 0x0d, 0xa8, 0x1e, 0x50, 0x4c, 0xb6, 0x36, 0x1a, 0x56, 0xd3, 0x5a, 0xab, 0x79, 0x0a, 0x72,
+	0x6e, 0x03, 0xec, 0x92, 0xf0, 0x2c, 0xe8, 0x89, 0xb1, 0x92, 0x18, 0xd3, 0x30, 0x4e, 0x03, 0x16,
+	0x5a, 0xa3, 0xd1, 0x77, 0xfd, 0x01, 0x6e, 0x94, 0xc5, 0x60, 0x04, 0x3a, 0xab, 0x60, 0xb7, 0x07,
+	0x7e, 0xd0, 0x6f, 0x54, 0x04, 0x5e, 0x02, 0x7c, 0x9f, 0x3d, 0xd2, 0x09, 0x7e, 0x8c, 0x1b, 0x76,
+	0xd3, 0x5a, 0xb3, 0x3d, 0x05, 0xf1, 0x7d, 0x36, 0xf0, 0x59, 0xd0, 0xc5, 0x62, 0xa9, 0xaa, 0xdc,
+	0x27, 0xc1, 0xa0, 0xbf, 0x5b, 0x70, 0x3d, 0xc5, 0x38, 0x1d, 0x85, 0x43, 0x8a, 0x9d, 0x5b, 0x50,
+	0xeb, 0xe0, 0x2e, 0xc1, 0xec, 0x43, 0x7c, 0xa1, 0x98, 0x4f, 0x10, 0xce, 0x55, 0x28, 0x1f, 0x78,
+	0xdb, 0x8a, 0x71, 0xfe, 0xc9, 0xe9, 0xf7, 0xc8, 0x7a, 0xd8, 0xc3, 0x1c, 0x2f, 0x79, 0x4e, 0x10,
+	0x9c, 0x8f, 0xad, 0x81, 0x7f, 0x8c, 0x9f, 0xfb, 0x14, 0xf7, 0x14, 0xeb, 0x1a, 0xc6, 0x41, 0xb0,
+	0xe4, 0xe1, 0x6e, 0x78, 0x86, 0xc9, 0x05, 0x9f, 0xd2, 0xb0, 0x9b, 0xe5, 0xb5, 0x9a, 0x67, 0xe0,
+	0x1c, 0x17, 0x16, 0x25, 0xe7, 0x5b, 0x1b, 0x4a, 0x92, 0x18, 0x76, 0x10, 0xd8, 0x6d, 0x42, 0x42,
+	0xd2, 0x58, 0x68, 0x5a, 0x6b, 0xf5, 0x67, 0x4b, 0xf2, 0x78, 0x9e, 0x0a, 0x9c, 0x27, 0x87, 0xd0,
+	0xbf, 0x2d, 0x58, 0xe1, 0xb2, 0x9e, 0xe0, 0xee, 0xa9, 0x7e, 0x46, 0xe6, 0x59, 0x58, 0x13, 0x67,
+	0x91, 0x9c, 0x61, 0xc9, 0x38, 0x43, 0x07, 0x2a, 0x82, 0x57, 0x29, 0xac, 0xf8, 0x76, 0x1e, 0xc2,
+	0x65, 0x0f, 0x0f, 0xf0, 0xe0, 0x10, 0x13, 0xc9, 0x9b, 0x90, 0x75, 0xd1, 0x4b, 0x61, 0x9d, 0x26,
+	0xd4, 0x37, 0x83, 0xe1, 0x31, 0x26, 0x23, 0x12, 0x0c, 0x99, 0x38, 0xb4, 0x9a, 0xa7, 0xa3, 0x9c,
+	0x77, 0xe1, 0xda, 0x3e, 0x19, 0x53, 0x86, 0x7b, 0x13, 0x07, 0x38, 0x39, 0xc0, 0xb5, 0xdf, 0xa2,
+	0x14, 0x13, 0x16, 0x84, 0x43, 0xa1, 0x83, 0x45, 0x2f, 0x41, 0xa0, 0x7f, 0x29, 0xf3, 0x4c, 0x24,
+	0x57, 0x87, 0x7c, 0x03, 0xaa, 0x1e, 0xa6, 0xe3, 0x3e, 0x13, 0x62, 0x2f, 0x7a, 0x0a, 0x4a, 0xd4,
+	0x59, 0xca, 0x55, 0xa7, 0x71, 0x1c, 0xe5, 0xd4, 0x71, 0x98, 0x66, 0x57, 0x49, 0x9b, 0x9d, 0xf3,
+	0x14, 0x1c, 0x43, 0x86, 0xfd, 0xf0, 0x14, 0x0f, 0x95, 0x16, 0x32, 0x46, 0x38, 0x9f, 0x3b, 0x98,
+	0x9d, 0x84, 0x3d, 0xa5, 0x01, 0x05, 0x4d, 0x8a, 0x5d, 0xd3, 0xc5, 0xfe, 0xad, 0x05, 0x8d, 0x9d,
+	0x23, 0xbf, 0xd5, 0xeb, 0x7d, 0x3c, 0x3e, 0x0c, 0x3e, 0xc4, 0x17, 0xf3, 0xf0, 0x4c, 0x17, 0x16,
+	0x77, 0xc7, 0x87, 0xfd, 0xa0, 0x9b, 0x88, 0x1d, 0xc1, 0x9c, 0x9d, 0x5d, 0x12, 0x9c, 0xf9, 0x8c,
+	0xeb, 0x44, 0x4a, 0x9d, 0x20, 0xf8, 0x8e, 0x2d, 0x4c, 0xb9, 0x3b, 0x49, 0x41, 0x15, 0x84, 0x3e,
+	0x82, 0xaf, 0x66, 0x70, 0xf9, 0xe6, 0x27, 0x84, 0x3a, 0x62, 0xe1, 0xed, 0x80, 0x32, 0xa9, 0x4b,
+	0x3a, 0x07, 0xab, 0x47, 0x6d, 0x70, 0xb3, 0x16, 0x55, 0xec, 0x3e, 0x82, 0x05, 0x85, 0x6e, 0x58,
+	0xcd, 0xf2, 0x5a, 0xfd, 0xd9, 0xb2, 0x62, 0x4c, 0x62, 0xbd, 0x68, 0x14, 0xfd, 0xcc, 0x12, 0xeb,
+	0x78, 0x78, 0xe8, 0x0f, 0xb0, 0x44, 0xce, 0xc3, 0x27, 0x8b, 0x8c, 0xd2, 0x81, 0x8a, 0x66, 0x8e,
+	0xe2, 0x1b, 0x7d, 0x0c, 0x37, 0x33, 0xb9, 0x98, 0x83, 0xf6, 0x47, 0x4a, 0xc0, 0x41, 0x78, 0xf6,
+	0x76, 0x04, 0x8c, 0x85, 0x49, 0xef, 0x38, 0x07, 0x61, 0x5e, 0x42, 0x55, 0x45, 0xae, 0xcb, 0x50,
+	0x8a, 0x19, 0x2e, 0x69, 0x5a, 0x2d, 0x25, 0x5a, 0xe5, 0x7e, 0x20, 0x33, 0x4a, 0xaf, 0xc5, 0x04,
+	0x97, 0x65, 0x2f, 0x41, 0xf0, 0x0c, 0xb6, 0x41, 0x82, 0x23, 0x26, 0x0e, 0xc2, 0xf6, 0x24, 0x80,
+	0xbe, 0xb0, 0xe0, 0xde, 0xce, 0x91, 0xff, 0x7d, 0xbf, 0x1f, 0xf4, 0x7c, 0x86, 0x8d, 0x20, 0x30,
+	0x0f, 0xc5, 0xad, 0x82, 0x2d, 0xa3, 0x8c, 0xd4, 0x9a, 0x04, 0xd2, 0x71, 0xb8, 0x32, 0x11, 0x87,
+	0xd1, 0x21, 0xdc, 0x2f, 0x66, 0x6b, 0x0e, 0xda, 0xfd, 0x04, 0x9a, 0xca, 0xa7, 0x8c, 0xf5, 0xe7,
+	0xe2, 0xaf, 0x1d, 0xb8, 0x5b, 0xb0, 0xb6, 0x62, 0xfe, 0x69, 0xda, 0x6d, 0x57, 0x15, 0x9b, 0xc6,
+	0x9c, 0xc4, 0x7b, 0x7f, 0x22, 0x16, 0xf5, 0xf0, 0x59, 0x78, 0x3a, 0xff, 0x93, 0x92, 0x16, 0x56,
+	0x8e, 0x2d, 0xec, 0x2a, 0x94, 0x5b, 0xfd, 0xbe, 0x4a, 0xa4, 0xfc, 0x13, 0xbd, 0x04, 0x54, 0xb4,
+	0xfd, 0x1c, 0x4e, 0xe4, 0xaf, 0x16, 0x2c, 0x1b, 0x2b, 0xcf, 0x64, 0xf7, 0x4f, 0xe0, 0xaa, 0x66,
+	0x3a, 0xcf, 0xc3, 0xf1, 0xb0, 0x27, 0xe4, 0x58, 0xf4, 0x26, 0xf0, 0xa6, 0x8f, 0x54, 0xd2, 0x3e,
+	0x72, 0x0b, 0x6a, 0xed, 0xf3, 0x51, 0x40, 0x30, 0x6d, 0xc9, 0xea, 0xa0, 0xec, 0x25, 0x08, 0xae,
+	0xd9, 0x6d, 0x9f, 0xb2, 0x03, 0x2a, 0x26, 0x57, 0xc5, 0xb0, 0x86, 0x41, 0x7f, 0xb0, 0xe0, 0xf6,
+	0xce, 0x91, 0xbf, 0x37, 0xc6, 0xe4, 0xa2, 0x35, 0xee, 0x05, 0xac, 0x7d, 0x86, 0x87, 0x8c, 0xce,
+	0xcb, 0x8d, 0x2e, 0x46, 0x98, 0x36, 0xca, 0xa2, 0x42, 0x93, 0x00, 0x57, 0xc6, 0x26, 0x09, 0x07,
+	0x4a, 0x0e, 0xf1, 0xcd, 0x15, 0xb6, 0x1f, 0x2a, 0xde, 0x4b, 0xfb, 0x21, 0x9f, 0xb9, 0x1d, 0x0c,
+	0x02, 0xc9, 0xaf, 0xed, 0x49, 0x00, 0x8d, 0xe0, 0x4e, 0x2e, 0xa7, 0xea, 0x1c, 0x1f, 0x43, 0x55,
+	0x62, 0x95, 0x6d, 0x5e, 0x53, 0x07, 0x96, 0xd0, 0x7b, 0x8a, 0x60, 0xa6, 0xa3, 0xfd, 0x75, 0x09,
+	0x20, 0x99, 0x9a, 0x75, 0xae, 0x5c, 0xa6, 0xe8, 0x5c, 0xf9, 0xb7, 0xa6, 0x8c, 0x72, 0x41, 0xad,
+	0x50, 0xc9, 0x52, 0xa2, 0x2a, 0x5b, 0x6c, 0xa3, 0x6c, 0x29, 0xaa, 0x64, 0x57, 0xc1, 0x6e, 0x75,
+	0x99, 0xaa, 0x64, 0x6b, 0x9e, 0x04, 0xa4, 0x2d, 0xfb, 0x34, 0x1c, 0x36, 0x16, 0xe5, 0x4a, 0x12,
+	0x32, 0xad, 0xa8, 0x56, 0x68, 0x45, 0x90, 0xb6, 0x22, 0x6e, 0xc1, 0x21, 0xc3, 0x8d, 0xba, 0xb2,
+	0xe0, 0x90, 0x61, 0xf4, 0xbb, 0x0a, 0x5c, 0x8e, 0x04, 0x58, 0x0f, 0x87, 0x47, 0xc1, 0xf1, 0x2c,
+	0x96, 0xb2, 0x45, 0xe9, 0x18, 0x93, 0xc8, 0x52, 0x24, 0xc4, 0x4b, 0xe1, 0x56, 0xbf, 0x1f, 0x7e,
+	0x8a, 0x7b, 0x9b, 0x3e, 0x97, 0x21, 0x32, 0x99, 0x14, 0x96, 0xaf, 0xbf, 0x1f, 0xb2, 0xd1, 0x46,
+	0x70, 0x1c, 0x30, 0xaa, 0x72, 0x82, 0x86, 0x89, 0xc6, 0x77, 0x31, 0x09, 0x94, 0x22, 0x6d, 0x4f,
+	0xc3, 0x38, 0xf7, 0x61, 0x99, 0x43, 0xad, 0xfe, 0x71, 0x48, 0x02, 0x76, 0x32, 0x50, 0x1a, 0x35,
+	0x91, 0x5c, 0xe5, 0x1c, 0xd1, 0x39, 0xc5, 0x9f, 0x0a, 0xcd, 0xda, 0x5e, 0x0c, 0xf3, 0x52, 0x5b,
+	0xbf, 0x68, 0xac, 0x87, 0xe3, 0x21, 0x13, 0x7a, 0xb6, 0xbd, 0xc9, 0x01, 0xee, 0xe4, 0xdb, 0x61,
+	0xf7, 0x34, 0x1c, 0xb3, 0xfd, 0x13, 0x82, 0xe9, 0x49, 0xd8, 0xef, 0x09, 0xcd, 0xdb, 0xde, 0x04,
+	0xde, 0x59, 0x83, 0x2b, 0x0a, 0xb7, 0x31, 0x26, 0xbe, 0xa8, 0x52, 0xe5, 0x31, 0xa4, 0xd1, 0xda,
+	0x05, 0xae, 0x6e, 0x5c, 0xe0, 0x10, 0x2c, 0xed, 0x91, 0xcd, 0x90, 0xe0, 0x63, 0x22, 0xc2, 0xc9,
+	0x92, 0x10, 0xce, 0xc0, 0x49, 0x9a, 0xe7, 0x7e, 0xf7, 0x54, 0xd1, 0x2c, 0x47, 0x34, 0x09, 0x8e,
+	0xd3, 0x70, 0x79, 0x77, 0xfc, 0x73, 0x99, 0x7b, 0x2f, 0x8b, 0x5d, 0x0c, 0x1c, 0x97, 0xcc, 0xac,
+	0xbd, 0xf7, 0xb7, 0x1b, 0x57, 0x04, 0xbb, 0x13, 0x78, 0xd4, 0x12, 0x7e, 0xfb, 0x02, 0x33, 0xd3,
+	0x5a, 0x5e, 0x21, 0xc4, 0xa0, 0x3d, 0x68, 0xe6, 0x2f, 0xa1, 0x7c, 0xff, 0x3d, 0xa8, 0x4a, 0xac,
+	0x98, 0x5f, 0x7f, 0x76, 0x5d, 0x79, 0xb4, 0x39, 0xc5, 0x53, 0x44, 0x68, 0x57, 0x70, 0xd5, 0x29,
+	0xe2, 0xea, 0x15, 0x57, 0xfc, 0x16, 0x34, 0xf3, 0x57, 0x2c, 0x4e, 0x34, 0xe8, 0x5e, 0x9c, 0x7a,
+	0xcd, 0xc9, 0x7a, 0x20, 0x46, 0x07, 0x80, 0x8a, 0x88, 0xd4, 0x16, 0x5f, 0x87, 0x05, 0x85, 0x56,
+	0x41, 0x30, 0x87, 0xed, 0x88, 0x0a, 0x6d, 0x88, 0x65, 0x37, 0x70, 0x1f, 0x33, 0xfc, 0xfa, 0x47,
+	0xe4, 0xc3, 0xbd, 0xc2, 0x55, 0xe6, 0x90, 0x69, 0x0f, 0x44, 0xd1, 0xfa, 0x02, 0xf3, 0xdc, 0x45,
+	0x3a, 0xcc, 0x67, 0xe3, 0xb9, 0x94, 0x3d, 0x5f, 0x96, 0xe0, 0x56, 0xf6, 0xba, 0xaf, 0x78, 0x53,
+	0xe1, 0x91, 0x43, 0x5d, 0xcc, 0x68, 0xa3, 0x24, 0x22, 0x58, 0x0c, 0xf3, 0xd8, 0xa3, 0x07, 0x08,
+	0x2a, 0xf2, 0x83, 0xed, 0x99, 0x48, 0x1e, 0x09, 0xcd, 0xda, 0x4b, 0x45, 0xb9, 0x14, 0x96, 0xd3,
+	0x6d, 0xfa, 0x41, 0x9f, 0x87, 0x6e, 0x86, 0x07, 0x23, 0x46, 0x55, 0xb4, 0x4b, 0x61, 0x39, 0x1d,
+	0x0f, 0x1f, 0xb8, 0xf7, 0xbd, 0x31, 0x3b, 0x18, 0xb2, 0xa0, 0xaf, 0x4a, 0x80, 0x14, 0xd6, 0x79,
+	0x1f, 0x56, 0x9e, 0x5f, 0x8c, 0x7c, 0x4a, 0x39, 0x1b, 0x49, 0x22, 0x58, 0x10, 0xc4, 0x59, 0x43,
+	0xce, 0xb7, 0xc1, 0x6d, 0x75, 0xbb, 0x3c, 0xcc, 0x25, 0x12, 0x0c, 0x46, 0xfc, 0xf4, 0x69, 0x4b,
+	0x86, 0xc4, 0xb2, 0x57, 0x40, 0x81, 0x3e, 0xb3, 0xe0, 0x6b, 0xa2, 0x32, 0xa3, 0x98, 0xb5, 0x87,
+	0x24, 0xec, 0xf7, 0x07, 0x78, 0xc8, 0xe6, 0x54, 0x77, 0xc8, 0xb4, 0x58, 0xce, 0x4e, 0x8b, 0x15,
+	0x3d, 0x2d, 0xa2, 0x1f, 0xc0, 0xed, 0x3c, 0x36, 0xe6, 0x60, 0xb2, 0x3f, 0x95, 0x77, 0xd7, 0xf5,
+	0x3e, 0xf6, 0x49, 0x14, 0xc7, 0xdf, 0xba, 0x88, 0xf2, 0xb2, 0x37, 0xc9, 0xc3, 0x1c, 0xe4, 0xfb,
+	0x95, 0x3c, 0x45, 0x91, 0xc7, 0x13, 0x2b, 0x79, 0xeb, 0x22, 0xf2, 0xc2, 0x9f, 0xa7, 0x20, 0x59,
+	0x42, 0xf2, 0x4f, 0xe4, 0xc1, 0xed, 0x3c, 0xc6, 0x94, 0xdc, 0x51, 0x53, 0xce, 0xd2, 0x9a, 0x72,
+	0x46, 0x19, 0x54, 0x4a, 0x95, 0x41, 0xe8, 0x87, 0xe2, 0x82, 0xa7, 0x24, 0x4b, 0xd9, 0xf6, 0x3c,
+	0x22, 0xd1, 0x00, 0x1e, 0x4c, 0x59, 0x5f, 0xb1, 0xde, 0x84, 0xba, 0xee, 0x6d, 0x96, 0x60, 0x54,
+	0x47, 0xcd, 0x74, 0x78, 0x7f, 0x51, 0x8d, 0x95, 0x90, 0xf9, 0x0c, 0xcb, 0x86, 0xed, 0x7f, 0xbb,
+	0xb1, 0xa2, 0x35, 0xab, 0x2b, 0x39, 0xcd, 0x6a, 0x3b, 0xbb, 0x59, 0x5d, 0xd5, 0x6b, 0x1d, 0xf4,
+	0xa5, 0x05, 0x37, 0x33, 0x59, 0xff, 0x9f, 0xb4, 0xa4, 0x75, 0x89, 0xed, 0x94, 0xc4, 0x0f, 0xe1,
+	0xf2, 0x0b, 0xe2, 0x77, 0xb5, 0xa0, 0xaa, 0x22, 0xb0, 0x89, 0x9d, 0xa9, 0x2d, 0xfd, 0x99, 0xbc,
+	0xac, 0x6d, 0x0d, 0x46, 0x21, 0x79, 0xcd, 0xa0, 0x19, 0xbb, 0x55, 0x49, 0x77, 0xab, 0xf7, 0x60,
+	0x81, 0xdb, 0x19, 0xe9, 0xc9, 0xca, 0xbb, 0xfe, 0x6c, 0x45, 0x6d, 0x2f, 0xb7, 0x92, 0x63, 0x5e,
+	0x44, 0xc3, 0x2f, 0x8d, 0x77, 0x72, 0xf9, 0x88, 0xcb, 0xb1, 0x05, 0x19, 0x47, 0xa2, 0xa4, 0x99,
+	0x5e, 0x92, 0x8f, 0x79, 0x11, 0x0d, 0x57, 0xa1, 0x1c, 0xc0, 0x3d, 0xc1, 0x9a, 0xed, 0xc5, 0x30,
+	0x37, 0x02, 0x99, 0xd6, 0x54, 0xce, 0x54, 0x50, 0xa2, 0xb2, 0x4a, 0xbe, 0xca, 0xfe, 0x61, 0xc1,
+	0x92, 0x2e, 0x44, 0x6e, 0x33, 0x57, 0xd8, 0xc4, 0x56, 0x62, 0x13, 0x5b, 0x9c, 0x52, 0x9a, 0x4c,
+	0x74, 0x95, 0x93, 0x90, 0xe8, 0x24, 0xc7, 0x37, 0x08, 0xd5, 0xba, 0x8d, 0x11, 0x7c, 0x96, 0xba,
+	0x9f, 0xa8, 0xe7, 0x15, 0x09, 0x71, 0xbc, 0xba, 0x97, 0x28, 0x4b, 0x96, 0x50, 0xaa, 0xff, 0xbd,
+	0x90, 0xd5, 0xff, 0xd6, 0x4b, 0x84, 0xef, 0xf8, 0xf4, 0x04, 0xd3, 0xc6, 0xa2, 0xa8, 0x2e, 0x32,
+	0x46, 0xd0, 0xe7, 0x9a, 0xc0, 0x22, 0x8c, 0xaf, 0x82, 0xbd, 0x35, 0xec, 0xe1, 0x73, 0x21, 0xaf,
+	0xed, 0x49, 0x20, 0xd7, 0x79, 0x93, 0x64, 0x50, 0x36, 0x92, 0x81, 0x6e, 0xe2, 0x95, 0xc9, 0x7b,
+	0xa8, 0x3c, 0x87, 0xc8, 0x75, 0x85, 0xe6, 0xbf, 0x90, 0x46, 0xd2, 0x3e, 0x37, 0x8d, 0x84, 0xbe,
+	0xb9, 0xb5, 0xf2, 0x59, 0x3e, 0xa5, 0xa3, 0x13, 0xe2, 0xd3, 0xe8, 0x4d, 0x45, 0xc3, 0x88, 0xde,
+	0xba, 0xe8, 0xb3, 0x73, 0xe7, 0x8f, 0x7a, 0xeb, 0x11, 0x02, 0x21, 0x58, 0x6a, 0x91, 0xee, 0x49,
+	0x70, 0x86, 0xd7, 0x4f, 0xc6, 0xc3, 0x53, 0x9e, 0x06, 0x38, 0x4b, 0x62, 0xf7, 0x25, 0x4f, 0x7c,
+	0xa3, 0x9f, 0x5b, 0xa2, 0x96, 0xf7, 0x30, 0x65, 0x21, 0xc1, 0x39, 0xcc, 0x7f, 0x00, 0x75, 0x6d,
+	0x24, 0xd5, 0x71, 0x48, 0x46, 0x3c, 0x9d, 0x8a, 0xeb, 0x91, 0x57, 0xc5, 0xfd, 0xa0, 0xcb, 0x94,
+	0x50, 0x31, 0x9c, 0x9d, 0xf2, 0xd0, 0x6f, 0x2c, 0xb8, 0x5b, 0xc0, 0x4b, 0xd2, 0x96, 0x33, 0xdd,
+	0x2d, 0x6a, 0xcb, 0xa9, 0x79, 0x19, 0xfe, 0xa6, 0x46, 0x62, 0x7f, 0x8b, 0x60, 0x1e, 0xa4, 0x3b,
+	0xa7, 0xc1, 0x68, 0x14, 0x3b, 0x5c, 0x04, 0x6a, 0x9e, 0x58, 0xd1, 0x3d, 0x11, 0xfd, 0xcd, 0x02,
+	0x48, 0x38, 0x7b, 0xed, 0x07, 0x93, 0x6f, 0x24, 0x85, 0xb6, 0x0c, 0x43, 0x5f, 0x99, 0xd0, 0x66,
+	0xba, 0xe4, 0xfe, 0xa6, 0x56, 0x72, 0x57, 0xc4, 0x9c, 0xc6, 0xc4, 0x1c, 0x45, 0x50, 0x54, 0x8c,
+	0xcb, 0x47, 0x44, 0x13, 0x89, 0xfe, 0x69, 0xc1, 0xd5, 0xf4, 0xce, 0x33, 0x35, 0xf7, 0x0a, 0x22,
+	0x47, 0x41, 0x23, 0x4f, 0x24, 0xf5, 0xe1, 0x51, 0x40, 0x06, 0x62, 0xdc, 0x8e, 0x92, 0x7a, 0x8c,
+	0xd2, 0x62, 0x4b, 0x35, 0x27, 0xb6, 0x2c, 0x18, 0xb1, 0xc5, 0x88, 0x54, 0x8b, 0xe9, 0x48, 0x15,
+	0x37, 0xd7, 0x6b, 0x7a, 0x73, 0xfd, 0x8f, 0x16, 0x5c, 0x9b, 0x50, 0x9d, 0xf1, 0x94, 0x65, 0x15,
+	0x3d, 0x65, 0x95, 0xf2, 0x9f, 0xb2, 0xca, 0xfa, 0x53, 0x16, 0xef, 0x32, 0x1c, 0x50, 0xff, 0x58,
+	0x76, 0x4a, 0xb0, 0x8c, 0xe1, 0xcb, 0x9e, 0x81, 0xe3, 0xb9, 0xb3, 0x83, 0x29, 0x0d, 0xc2, 0x61,
+	0x44, 0x65, 0x0b, 0xaa, 0x14, 0x16, 0xfd, 0xc2, 0x82, 0x65, 0x65, 0xbd, 0xaf, 0x15, 0xf4, 0x4c,
+	0xbb, 0x2c, 0x67, 0x55, 0x3a, 0xf2, 0x5a, 0x18, 0x55, 0x9d, 0x12, 0xca, 0x09, 0x7c, 0x81, 0x2c,
+	0x89, 0x45, 0x0c, 0xde, 0x09, 0x8e, 0x65, 0xd7, 0x66, 0x1e, 0x85, 0x95, 0x4a, 0x51, 0xe5, 0x38,
+	0x45, 0xa1, 0xdf, 0xeb, 0x05, 0x41, 0x6a, 0xaf, 0xff, 0xaf, 0x3c, 0xfc, 0x27, 0x79, 0x51, 0x68,
+	0x9f, 0x67, 0x70, 0xfa, 0x66, 0x5a, 0x99, 0xd7, 0xff, 0x1f, 0xa2, 0xeb, 0x40, 0x35, 0xb9, 0x0e,
+	0xa0, 0x5f, 0x4a, 0xfd, 0xb6, 0xcf, 0xf3, 0xf5, 0xab, 0x0e, 0xc5, 0x4a, 0xea, 0x06, 0xb3, 0x5a,
+	0x2c, 0x65, 0x55, 0x8b, 0x72, 0xc1, 0x58, 0x91, 0x31, 0x3c, 0x93, 0x2a, 0xef, 0x2a, 0x1a, 0x2e,
+	0xf9, 0x0e, 0xa6, 0xdc, 0x61, 0xd4, 0xf6, 0x11, 0xf8, 0xec, 0xf3, 0x15, 0x00, 0xd1, 0x8b, 0x22,
+	0x22, 0x66, 0xb5, 0xa1, 0x2a, 0xc3, 0x8c, 0x73, 0x53, 0x2d, 0x98, 0xf5, 0x0f, 0x14, 0xf7, 0x56,
+	0xf6, 0xa0, 0x14, 0x14, 0x5d, 0x72, 0x9e, 0x83, 0x2d, 0xfe, 0x17, 0xe0, 0xb8, 0x1a, 0x61, 0xea,
+	0x2f, 0x12, 0xee, 0xcd, 0xcc, 0xb1, 0x78, 0x8d, 0x3d, 0x80, 0xe4, 0xf9, 0xda, 0xb9, 0x93, 0x10,
+	0x67, 0x3e, 0xbd, 0xbb, 0xcd, 0x7c, 0x82, 0x78, 0xc9, 0x7d, 0xa8, 0x6b, 0x6f, 0xcc, 0x8e, 0x36,
+	0x25, 0xfb, 0x3d, 0xdb, 0xbd, 0x5b, 0x40, 0x11, 0xaf, 0xfa, 0x11, 0x2c, 0xe9, 0x6f, 0xbd, 0x8e,
+	0x36, 0x29, 0xe7, 0x25, 0xda, 0x45, 0x45, 0x24, 0xe6, 0xc2, 0xc9, 0xbb, 0xab, 0xb9, 0x70, 0xe6,
+	0x0b, 0xb0, 0x8b, 0x8a, 0x48, 0xe2, 0x85, 0x09, 0x5c, 0xcf, 0x7c, 0x7b, 0x74, 0x9e, 0x24, 0xd3,
+	0xa7, 0xbd, 0x99, 0xba, 0xef, 0xcc, 0x44, 0x1b, 0xef, 0x19, 0x80, 0x33, 0xf9, 0x5e, 0xe8, 0x3c,
+	0x32, 0x15, 0x9c, 0xfb, 0x52, 0xe9, 0xae, 0x4d, 0x27, 0x8c, 0xb7, 0xea, 0xc3, 0x4a, 0xc6, 0x33,
+	0x9e, 0xb3, 0xa6, 0xeb, 0xa6, 0xe8, 0x91, 0xd1, 0x7d, 0x3c, 0x03, 0x65, 0xbc, 0x5b, 0x17, 0xae,
+	0xa6, 0x5f, 0x9a, 0x9c, 0x07, 0xc9, 0x02, 0x05, 0xef, 0x65, 0xee, 0xc3, 0x69, 0x64, 0xf1, 0x26,
+	0x47, 0x70, 0x6d, 0xa2, 0xa7, 0xed, 0x68, 0xd3, 0x8b, 0x7a, 0xe6, 0xee, 0xa3, 0xa9, 0x74, 0xfa,
+	0x3e, 0x9d, 0xa2, 0x7d, 0x3a, 0x33, 0xee, 0xd3, 0x99, 0xb2, 0x4f, 0x1f, 0x56, 0x32, 0xba, 0xd3,
+	0x4e, 0xea, 0x94, 0xf3, 0x3b, 0xdc, 0xee, 0xe3, 0x19, 0x28, 0xe3, 0xdd, 0x42, 0x58, 0xcd, 0x6a,
+	0x37, 0x3b, 0xda, 0x22, 0x53, 0x9a, 0xda, 0xee, 0x93, 0x59, 0x48, 0xe3, 0x0d, 0x3f, 0x81, 0x65,
+	0xa3, 0x49, 0xec, 0x20, 0xe3, 0x08, 0x32, 0xbb, 0xd2, 0xee, 0xbd, 0x42, 0x9a, 0x78, 0xed, 0x97,
+	0x70, 0x25, 0xd5, 0x83, 0x74, 0xee, 0xeb, 0xf6, 0x9a, 0xd7, 0x25, 0x75, 0x1f, 0x4c, 0xa1, 0xd2,
+	0xe3, 0x8e, 0xde, 0x02, 0xd4, 0xe3, 0x4e, 0x4e, 0x7b, 0xd2, 0x45, 0x45, 0x24, 0x3a, 0xeb, 0xa9,
+	0x36, 0x9b, 0xce, 0x7a, 0x7e, 0x6b, 0xd0, 0x7d, 0x30, 0x85, 0x2a, 0xde, 0x61, 0x0c, 0x37, 0xb2,
+	0x9b, 0x62, 0xce, 0x3b, 0xba, 0xf4, 0x53, 0xda, 0x72, 0xee, 0xbb, 0xb3, 0x11, 0x1b, 0x91, 0x5a,
+	0x6b, 0x30, 0x19, 0x91, 0x3a, 0xbb, 0x67, 0xe6, 0xa2, 0x22, 0x12, 0x3d, 0xb8, 0xa4, 0x7b, 0x27,
+	0x7a, 0x70, 0x29, 0xe8, 0xef, 0xb8, 0x0f, 0xa7, 0x91, 0x69, 0x69, 0xf1, 0xda, 0xc4, 0xdd, 0x5b,
+	0x77, 0xfa, 0xa2, 0x8b, 0xb9, 0x1b, 0x15, 0x89, 0xfa, 0x4d, 0x19, 0x5d, 0x7a, 0xdf, 0xe2, 0x01,
+	0x7f, 0xf2, 0x26, 0xaa, 0x07, 0xfc, 0xc2, 0x3b, 0xb3, 0xbb, 0x36, 0x9d, 0xd0, 0xb0, 0x2b, 0xb3,
+	0xb0, 0x35, 0xec, 0x2a, 0xb7, 0xbe, 0x76, 0x1f, 0x4c, 0xa1, 0xd2, 0x77, 0x68, 0x9f, 0xe7, 0xee,
+	0xd0, 0x3e, 0x9f, 0x65, 0x87, 0x82, 0xda, 0x10, 0x5d, 0x3a, 0xac, 0x0a, 0xba, 0x0f, 0xfe, 0x33,
+	0x00, 0xdb, 0xdd, 0x1d, 0xcc, 0x08, 0x2c, 0x00, 0x00,
 }
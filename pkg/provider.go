@@ -0,0 +1,342 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/pquerna/otp"
+	"go.uber.org/zap"
+	"image/color"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	mfaProviderStorage = "mfa_providers"
+
+	defaultTotpDigits        = 6
+	defaultTotpPeriod        = 30
+	defaultTotpAlgorithm     = "SHA1"
+	defaultTotpSkew          = 1
+	defaultTotpMaxDrift      = 3
+	defaultRecoveryCodeCount = 10
+	defaultLockoutDuration   = 900
+	defaultQrSize            = 200
+	defaultQrForeground      = "#000000"
+	defaultQrBackground      = "#ffffff"
+	maxRecoveryCodeCount     = 100
+	maxTotpPeriod            = 300
+	maxTotpSkew              = 10
+
+	// TotpDriftDisabled as TotpMaxDrift disables drift tracking, zero falls back to the default.
+	TotpDriftDisabled = -1
+	// TotpSkewNone as TotpSkew accepts codes of the current time step only, zero falls back to the default.
+	TotpSkewNone = -1
+
+	ErrorProviderConfigNotExists = "Provider config not exists"
+	ErrorFactorNotAllowed        = "Factor is not allowed for the provider"
+)
+
+var (
+	totpAlgorithms = map[string]otp.Algorithm{
+		"SHA1":   otp.AlgorithmSHA1,
+		"SHA256": otp.AlgorithmSHA256,
+		"SHA512": otp.AlgorithmSHA512,
+	}
+	factors      = []string{MethodTotp, MethodYubiKey, MethodRecoveryCode}
+	colorPattern = regexp.MustCompile("^#[0-9a-fA-F]{6}$")
+)
+
+// LoadProviderConfigs reads provider configs from a JSON file, the configs are
+// used for providers without a config in the storage.
+func LoadProviderConfigs(path string) ([]*proto.ProviderConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []*proto.ProviderConfig
+	if err = json.Unmarshal(data, &configs); err != nil {
+		return nil, err
+	}
+
+	if err = ValidateProviderConfigs(configs); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// ValidateProviderConfigs checks the provider configs of a file.
+func ValidateProviderConfigs(configs []*proto.ProviderConfig) error {
+	for _, c := range configs {
+		if err := validateProviderConfig(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetProviderConfig(ctx context.Context, req *proto.MfaGetProviderConfigDataRequest, res *proto.MfaGetProviderConfigDataResponse) error {
+	s = s.withContext(ctx)
+
+	if req.ProviderID == "" {
+		err := newRequestError(ErrorRequestPropertyRequired, "ProviderID")
+		s.logger.Error("Validate get provider config request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	config, err := s.providerConfig(req.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	res.Config = config
+
+	return nil
+}
+
+func (s *service) SetProviderConfig(ctx context.Context, req *proto.MfaSetProviderConfigDataRequest, res *proto.MfaSetProviderConfigDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := validateProviderConfig(req.Config); err != nil {
+		s.logger.Error("Validate set provider config request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	data, err := json.Marshal(req.Config)
+	if err != nil {
+		s.logger.Error("Encode provider config failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if err = s.redis.HSet(mfaProviderStorage, req.Config.ProviderID, data).Err(); err != nil {
+		s.logger.Error("Save provider config to Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditAdminAction,
+		ProviderID: req.Config.ProviderID,
+		Actor:      AuthClientID(ctx),
+		Reason:     "provider config updated",
+	})
+
+	res.Result = true
+
+	return nil
+}
+
+func (s *service) ListProviderConfigs(ctx context.Context, req *proto.MfaListProviderConfigsDataRequest, res *proto.MfaListProviderConfigsDataResponse) error {
+	s = s.withContext(ctx)
+
+	stored, err := s.redis.HGetAll(mfaProviderStorage).Result()
+	if err != nil {
+		s.logger.Error("Getting provider configs from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	ids := map[string]bool{}
+	for providerId := range stored {
+		ids[providerId] = true
+	}
+	for providerId := range s.opts().ProviderConfigs {
+		ids[providerId] = true
+	}
+
+	for providerId := range ids {
+		config, err := s.providerConfig(providerId)
+		if err != nil {
+			return err
+		}
+		res.Configs = append(res.Configs, config)
+	}
+
+	sort.Slice(res.Configs, func(i, j int) bool {
+		return res.Configs[i].ProviderID < res.Configs[j].ProviderID
+	})
+
+	return nil
+}
+
+func (s *service) DeleteProviderConfig(ctx context.Context, req *proto.MfaDeleteProviderConfigDataRequest, res *proto.MfaDeleteProviderConfigDataResponse) error {
+	s = s.withContext(ctx)
+
+	if req.ProviderID == "" {
+		err := newRequestError(ErrorRequestPropertyRequired, "ProviderID")
+		s.logger.Error("Validate delete provider config request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	removed, err := s.redis.HDel(mfaProviderStorage, req.ProviderID).Result()
+	if err != nil {
+		s.logger.Error("Remove provider config from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if removed == 0 {
+		res.Error = &proto.Error{
+			Message: ErrorProviderConfigNotExists,
+		}
+		return nil
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditAdminAction,
+		ProviderID: req.ProviderID,
+		Actor:      AuthClientID(ctx),
+		Reason:     "provider config deleted",
+	})
+
+	res.Result = true
+
+	return nil
+}
+
+// providerConfig returns the config of the provider with defaults applied. A
+// config in the storage takes precedence over the one loaded from the file.
+func (s *service) providerConfig(providerId string) (*proto.ProviderConfig, error) {
+	config := &proto.ProviderConfig{}
+
+	data, err := s.redis.HGet(mfaProviderStorage, providerId).Bytes()
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(data, config); err != nil {
+			s.logger.Error("Decode provider config failed with error", zap.Error(err), zap.String("providerId", providerId))
+
+			return nil, err
+		}
+	case err == redis.Nil:
+		if c, ok := s.opts().ProviderConfigs[providerId]; ok {
+			config = protobuf.Clone(c).(*proto.ProviderConfig)
+		}
+	default:
+		s.logger.Error("Getting provider config from Redis failed with error", zap.Error(err), zap.String("providerId", providerId))
+
+		return nil, err
+	}
+
+	config.ProviderID = providerId
+	if len(config.AllowedFactors) == 0 {
+		config.AllowedFactors = append([]string{}, factors...)
+	}
+	if config.TotpDigits == 0 {
+		config.TotpDigits = defaultTotpDigits
+	}
+	if config.TotpPeriod == 0 {
+		config.TotpPeriod = defaultTotpPeriod
+	}
+	if config.TotpAlgorithm == "" {
+		config.TotpAlgorithm = defaultTotpAlgorithm
+	}
+	if config.TotpSkew == 0 {
+		config.TotpSkew = defaultTotpSkew
+	}
+	if config.TotpMaxDrift == 0 {
+		config.TotpMaxDrift = defaultTotpMaxDrift
+	}
+	if config.RecoveryCodeCount == 0 {
+		config.RecoveryCodeCount = defaultRecoveryCodeCount
+	}
+	if config.LockoutThreshold > 0 && config.LockoutDuration == 0 {
+		config.LockoutDuration = defaultLockoutDuration
+	}
+	if config.QrSize == 0 {
+		config.QrSize = defaultQrSize
+	}
+	if config.QrForeground == "" {
+		config.QrForeground = defaultQrForeground
+	}
+	if config.QrBackground == "" {
+		config.QrBackground = defaultQrBackground
+	}
+
+	return config, nil
+}
+
+// parseColor parses a validated "#rrggbb" color.
+func parseColor(hex string) color.Color {
+	var r, g, b uint8
+	_, _ = fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+func factorAllowed(config *proto.ProviderConfig, factor string) bool {
+	return containsString(config.AllowedFactors, factor)
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func validateProviderConfig(config *proto.ProviderConfig) error {
+	if config == nil {
+		return newRequestError(ErrorRequestPropertyRequired, "Config")
+	}
+	if config.ProviderID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "ProviderID")
+	}
+	if strings.Contains(config.ProviderID, "_") {
+		return newRequestError(ErrorRequestPropertyFormat, "ProviderID")
+	}
+	for _, f := range config.AllowedFactors {
+		if !containsString(factors, f) {
+			return newRequestError(ErrorRequestPropertyFormat, "AllowedFactors")
+		}
+	}
+	if config.TotpDigits != 0 && config.TotpDigits != 6 && config.TotpDigits != 8 {
+		return newRequestError(ErrorRequestPropertyFormat, "TotpDigits")
+	}
+	if config.TotpPeriod < 0 || config.TotpPeriod > maxTotpPeriod {
+		return newRequestError(ErrorRequestPropertyFormat, "TotpPeriod")
+	}
+	if _, ok := totpAlgorithms[config.TotpAlgorithm]; config.TotpAlgorithm != "" && !ok {
+		return newRequestError(ErrorRequestPropertyFormat, "TotpAlgorithm")
+	}
+	if config.TotpSkew < TotpSkewNone || config.TotpSkew > maxTotpSkew {
+		return newRequestError(ErrorRequestPropertyFormat, "TotpSkew")
+	}
+	if config.TotpMaxDrift < TotpDriftDisabled {
+		return newRequestError(ErrorRequestPropertyFormat, "TotpMaxDrift")
+	}
+	if config.RecoveryCodeCount < 0 || config.RecoveryCodeCount > maxRecoveryCodeCount {
+		return newRequestError(ErrorRequestPropertyFormat, "RecoveryCodeCount")
+	}
+	if config.LockoutThreshold < 0 {
+		return newRequestError(ErrorRequestPropertyFormat, "LockoutThreshold")
+	}
+	if config.LockoutDuration < 0 {
+		return newRequestError(ErrorRequestPropertyFormat, "LockoutDuration")
+	}
+	if config.TrustedDeviceTTL < 0 {
+		return newRequestError(ErrorRequestPropertyFormat, "TrustedDeviceTTL")
+	}
+	if config.QrSize < 0 {
+		return newRequestError(ErrorRequestPropertyFormat, "QrSize")
+	}
+	if config.QrForeground != "" && !colorPattern.MatchString(config.QrForeground) {
+		return newRequestError(ErrorRequestPropertyFormat, "QrForeground")
+	}
+	if config.QrBackground != "" && !colorPattern.MatchString(config.QrBackground) {
+		return newRequestError(ErrorRequestPropertyFormat, "QrBackground")
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package mfa
+
+import (
+	"context"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+func (suite *ServiceTestSuite) setProviderConfig(config *proto.ProviderConfig) {
+	config.ProviderID = suite.ProviderID
+	res := &proto.MfaSetProviderConfigDataResponse{}
+	err := suite.service.SetProviderConfig(context.TODO(), &proto.MfaSetProviderConfigDataRequest{Config: config}, res)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+}
+
+func (suite *ServiceTestSuite) TestProviderConfigToApplyDefaults() {
+	res := &proto.MfaGetProviderConfigDataResponse{}
+	err := suite.service.GetProviderConfig(context.TODO(), &proto.MfaGetProviderConfigDataRequest{ProviderID: suite.ProviderID}, res)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.ProviderID, res.Config.ProviderID)
+	assert.Equal(suite.T(), []string{MethodTotp, MethodYubiKey, MethodRecoveryCode}, res.Config.AllowedFactors)
+	assert.Equal(suite.T(), int32(defaultTotpDigits), res.Config.TotpDigits)
+	assert.Equal(suite.T(), defaultTotpAlgorithm, res.Config.TotpAlgorithm)
+	assert.Equal(suite.T(), int32(defaultRecoveryCodeCount), res.Config.RecoveryCodeCount)
+	assert.Equal(suite.T(), int32(0), res.Config.LockoutThreshold)
+}
+
+func (suite *ServiceTestSuite) TestProviderConfigToBeManaged() {
+	suite.service = NewService(suite.redis, zap.L(), ProviderConfigs(&proto.ProviderConfig{ProviderID: suite.ProviderID, Issuer: "File"}))
+
+	get := func() *proto.ProviderConfig {
+		res := &proto.MfaGetProviderConfigDataResponse{}
+		_ = suite.service.GetProviderConfig(context.TODO(), &proto.MfaGetProviderConfigDataRequest{ProviderID: suite.ProviderID}, res)
+		return res.Config
+	}
+	assert.Equal(suite.T(), "File", get().Issuer)
+
+	suite.setProviderConfig(&proto.ProviderConfig{Issuer: "Storage"})
+	assert.Equal(suite.T(), "Storage", get().Issuer)
+
+	list := &proto.MfaListProviderConfigsDataResponse{}
+	_ = suite.service.ListProviderConfigs(context.TODO(), &proto.MfaListProviderConfigsDataRequest{}, list)
+	assert.Equal(suite.T(), 1, len(list.Configs))
+
+	del := &proto.MfaDeleteProviderConfigDataResponse{}
+	_ = suite.service.DeleteProviderConfig(context.TODO(), &proto.MfaDeleteProviderConfigDataRequest{ProviderID: suite.ProviderID}, del)
+	assert.True(suite.T(), del.Result)
+	assert.Equal(suite.T(), "File", get().Issuer)
+
+	del = &proto.MfaDeleteProviderConfigDataResponse{}
+	_ = suite.service.DeleteProviderConfig(context.TODO(), &proto.MfaDeleteProviderConfigDataRequest{ProviderID: suite.ProviderID}, del)
+	assert.Equal(suite.T(), ErrorProviderConfigNotExists, del.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestSetProviderConfigToReturnErrorRequestData() {
+	configs := map[string]*proto.ProviderConfig{
+		"ProviderID":       {},
+		"AllowedFactors":   {ProviderID: "p", AllowedFactors: []string{"sms"}},
+		"TotpDigits":       {ProviderID: "p", TotpDigits: 7},
+		"TotpPeriod":       {ProviderID: "p", TotpPeriod: maxTotpPeriod + 1},
+		"TotpSkew":         {ProviderID: "p", TotpSkew: maxTotpSkew + 1},
+		"TrustedDeviceTTL": {ProviderID: "p", TrustedDeviceTTL: -1},
+		"TotpAlgorithm":    {ProviderID: "p", TotpAlgorithm: "MD5"},
+		"QrForeground":     {ProviderID: "p", QrForeground: "red"},
+	}
+	for property, config := range configs {
+		err := suite.service.SetProviderConfig(context.TODO(), &proto.MfaSetProviderConfigDataRequest{Config: config}, &proto.MfaSetProviderConfigDataResponse{})
+		assert.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), property)
+	}
+}
+
+func (suite *ServiceTestSuite) TestCreateToApplyProviderConfig() {
+	suite.setProviderConfig(&proto.ProviderConfig{
+		Issuer:            "Provider",
+		TotpDigits:        8,
+		TotpAlgorithm:     "SHA256",
+		RecoveryCodeCount: 5,
+		QrForeground:      "#336699",
+	})
+
+	res := &proto.MfaCreateDataResponse{}
+	err := suite.service.Create(context.TODO(), &proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, res)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), res.URL, "issuer=Provider")
+	assert.Contains(suite.T(), res.URL, "digits=8")
+	assert.Equal(suite.T(), 5, len(res.RecoveryCode))
+
+	code, _ := totp.GenerateCodeCustom(res.SecretKey, time.Now(), totp.ValidateOpts{Period: 30, Digits: otp.DigitsEight, Algorithm: otp.AlgorithmSHA256})
+	check := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, check)
+	assert.True(suite.T(), check.Result)
+}
+
+func (suite *ServiceTestSuite) TestCreateToRequireIssuerOrAppName() {
+	err := suite.service.Create(context.TODO(), &proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, &proto.MfaCreateDataResponse{})
+	assert.EqualError(suite.T(), err, fmt.Sprintf(ErrorRequestPropertyRequired, "AppName"))
+}
+
+func (suite *ServiceTestSuite) TestProviderConfigToRestrictFactors() {
+	suite.setProviderConfig(&proto.ProviderConfig{AllowedFactors: []string{MethodTotp}})
+
+	res := suite.createDevice("")
+	assert.Empty(suite.T(), res.RecoveryCode)
+
+	check := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "RECOVERYCODE"}, check)
+	assert.False(suite.T(), check.Result)
+	assert.Equal(suite.T(), ErrorFactorNotAllowed, check.Error.Message)
+
+	yubiKey := &proto.MfaAddYubiKeyDataResponse{}
+	_ = suite.service.AddYubiKey(context.TODO(), &proto.MfaAddYubiKeyDataRequest{
+		ProviderID: suite.ProviderID,
+		UserID:     suite.userID,
+		PublicID:   "cccccccccccb",
+		PrivateID:  strings.Repeat("0", 12),
+		AesKey:     strings.Repeat("0", 32),
+	}, yubiKey)
+	assert.Equal(suite.T(), ErrorFactorNotAllowed, yubiKey.Error.Message)
+
+	suite.setProviderConfig(&proto.ProviderConfig{AllowedFactors: []string{MethodYubiKey}})
+	create := &proto.MfaCreateDataResponse{}
+	_ = suite.service.Create(context.TODO(), &proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, AppName: "test"}, create)
+	assert.Equal(suite.T(), ErrorFactorNotAllowed, create.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestCheckToLockOutAfterFailedAttempts() {
+	suite.setProviderConfig(&proto.ProviderConfig{LockoutThreshold: 2, LockoutDuration: 60})
+	res := suite.createDevice("")
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: "000000"}
+
+	for i := 0; i < 2; i++ {
+		check := &proto.MfaCheckDataResponse{}
+		_ = suite.service.Check(context.TODO(), req, check)
+		assert.Equal(suite.T(), ErrorCodeInvalid, check.Error.Message)
+	}
+
+	req.Code, _ = totp.GenerateCode(res.SecretKey, time.Now())
+	check := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), req, check)
+	assert.False(suite.T(), check.Result)
+	assert.Equal(suite.T(), ErrorLockedOut, check.Error.Message)
+
+	events := &proto.MfaQueryAuditEventsDataResponse{}
+	_ = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{UserID: suite.userID, Types: []string{AuditLockout}}, events)
+	assert.Equal(suite.T(), 1, len(events.Events))
+	assert.True(suite.T(), events.Events[0].ExpiresAt > time.Now().Unix())
+
+	suite.redis.Del(suite.service.GetLockoutStorageKey(suite.userID, suite.ProviderID))
+	check = &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), req, check)
+	assert.True(suite.T(), check.Result)
+}
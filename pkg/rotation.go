@@ -0,0 +1,244 @@
+package mfa
+
+import (
+	"context"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"go.uber.org/zap"
+	"net/url"
+	"time"
+)
+
+// secretRotation is a new secret issued for a device. Until it expires the
+// device secret is accepted as well, an expired rotation not confirmed is
+// discarded and the device keeps its secret.
+type secretRotation struct {
+	Secret    string `json:"secret"`
+	Digits    int    `json:"digits,omitempty"`
+	Period    int    `json:"period,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// RotateSecret issues a new secret for the device. Check accepts both secrets
+// until the new one is confirmed with a valid code, a new secret not confirmed
+// by the end of the grace period is discarded. Rotating again replaces a new
+// secret not confirmed yet.
+func (s *service) RotateSecret(ctx context.Context, req *proto.MfaRotateSecretDataRequest, res *proto.MfaRotateSecretDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate rotate secret request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	config, err := s.providerConfig(req.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	if config.Issuer == "" && req.AppName == "" {
+		err := newRequestError(ErrorRequestPropertyRequired, "AppName")
+		s.logger.Error("Validate rotate secret request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if !factorAllowed(config, MethodTotp) {
+		res.Error = &proto.Error{
+			Message: ErrorFactorNotAllowed,
+		}
+		return nil
+	}
+
+	devices, err := s.loadDevices(req.UserID, req.ProviderID)
+	if err != nil {
+		s.logger.Error("Getting devices from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	var d *device
+	switch {
+	case req.DeviceID != "":
+		for _, candidate := range devices {
+			if candidate.ID == req.DeviceID {
+				d = candidate
+			}
+		}
+	case len(devices) > 1:
+		err := newRequestError(ErrorRequestPropertyRequired, "DeviceID")
+		s.logger.Error("Validate rotate secret request failed with error", zap.Error(err))
+
+		return err
+	case len(devices) == 1:
+		d = devices[0]
+	}
+	if d == nil {
+		res.Error = &proto.Error{
+			Message: ErrorDeviceNotExists,
+		}
+		return nil
+	}
+
+	key, imageBased, err := s.generateKey(config, req.AppName, req.UserID, req.Email, req.QrSize)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rotation := &secretRotation{
+		Secret:    key.Secret(),
+		Digits:    int(config.TotpDigits),
+		Period:    int(config.TotpPeriod),
+		Algorithm: config.TotpAlgorithm,
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(s.opts().SecretRotationGrace).Unix(),
+	}
+	updated, err := s.updateDevice(req.UserID, req.ProviderID, d.ID, func(stored *device) bool {
+		stored.Rotation = rotation
+		return true
+	})
+	if err != nil {
+		s.logger.Error("Save device to Redis failed with error", zap.Error(err))
+
+		return err
+	}
+	if !updated {
+		res.Error = &proto.Error{
+			Message: ErrorDeviceNotExists,
+		}
+		return nil
+	}
+	d.Rotation = rotation
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditSecretRotationStarted,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     MethodTotp,
+		DeviceID:   d.ID,
+		ExpiresAt:  d.Rotation.ExpiresAt,
+	})
+
+	res.SecretKey = key.Secret()
+	res.URL = key.URL()
+	res.ImageBased = imageBased
+	res.QrCodeURL = fmt.Sprintf(qrUrlPattern, url.QueryEscape(key.URL()))
+	res.DeviceID = d.ID
+	res.GraceExpiresAt = d.Rotation.ExpiresAt
+
+	return nil
+}
+
+// validateDevice checks the code against the device secret and the secret it
+// is rotated to. A code of the new secret completes the rotation, once the
+// grace period has ended without one the new secret is discarded. The drift
+// estimate of the device follows the time step the code matched.
+func (s *service) validateDevice(userId string, providerId string, d *device, code string, config *proto.ProviderConfig, now time.Time) bool {
+	if r := d.Rotation; r != nil && now.Unix() >= r.ExpiresAt {
+		s.expireRotation(userId, providerId, d)
+	}
+
+	if r := d.Rotation; r != nil {
+		if ok, offset := validateTotp(code, r.Secret, r.Digits, r.Period, r.Algorithm, config.TotpSkew, clampDrift(d.Drift, config), now); ok {
+			observeDrift(providerId, offset)
+			s.completeRotation(userId, providerId, d, offset, config, "confirmed")
+			return true
+		}
+	}
+
+	ok, offset := d.validate(code, config, now)
+	if !ok {
+		return false
+	}
+
+	observeDrift(providerId, offset)
+	if d.trackDrift(offset, config) {
+		// Only the drift is saved, a device removed in the meantime is not restored.
+		_, err := s.updateDevice(userId, providerId, d.ID, func(stored *device) bool {
+			return stored.trackDrift(offset, config)
+		})
+		if err != nil {
+			s.logger.Error("Saving device drift to Redis failed with error", zap.Error(err))
+		}
+	}
+
+	return true
+}
+
+// expireRotation discards the new secret of the device, the device keeps its
+// secret. A rotation replaced or completed in the meantime is left as it is.
+func (s *service) expireRotation(userId string, providerId string, d *device) {
+	r := d.Rotation
+	d.Rotation = nil
+
+	expired, err := s.updateDevice(userId, providerId, d.ID, func(stored *device) bool {
+		if !sameRotation(stored.Rotation, r) {
+			return false
+		}
+		stored.Rotation = nil
+		return true
+	})
+	if err != nil {
+		s.logger.Error("Expire secret rotation in Redis failed with error", zap.Error(err))
+
+		return
+	}
+	if !expired {
+		return
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditSecretRotationExpired,
+		UserID:     userId,
+		ProviderID: providerId,
+		Method:     MethodTotp,
+		DeviceID:   d.ID,
+		Reason:     "grace period ended",
+	})
+}
+
+// completeRotation replaces the device secret with the new one the code
+// matched at the offset. The secret is kept when the rotation was replaced or
+// expired in the meantime.
+func (s *service) completeRotation(userId string, providerId string, d *device, offset int, config *proto.ProviderConfig, reason string) {
+	r := d.Rotation
+	completed, err := s.updateDevice(userId, providerId, d.ID, func(stored *device) bool {
+		if !sameRotation(stored.Rotation, r) {
+			return false
+		}
+		stored.Secret, stored.Digits, stored.Period, stored.Algorithm = r.Secret, r.Digits, r.Period, r.Algorithm
+		stored.Rotation = nil
+		stored.trackDrift(offset, config)
+		return true
+	})
+	if err != nil {
+		s.logger.Error("Complete secret rotation in Redis failed with error", zap.Error(err))
+
+		return
+	}
+	if !completed {
+		return
+	}
+	d.Secret, d.Digits, d.Period, d.Algorithm = r.Secret, r.Digits, r.Period, r.Algorithm
+	d.Rotation = nil
+	d.trackDrift(offset, config)
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditSecretRotationCompleted,
+		UserID:     userId,
+		ProviderID: providerId,
+		Method:     MethodTotp,
+		DeviceID:   d.ID,
+		Reason:     reason,
+	})
+}
+
+// sameRotation reports whether the stored rotation is the one issued at the
+// same time as r.
+func sameRotation(stored *secretRotation, r *secretRotation) bool {
+	return stored != nil && r != nil && stored.CreatedAt == r.CreatedAt && stored.Secret == r.Secret
+}
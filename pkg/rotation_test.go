@@ -0,0 +1,115 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"time"
+)
+
+func (suite *ServiceTestSuite) rotateSecret(deviceId string) *proto.MfaRotateSecretDataResponse {
+	res := &proto.MfaRotateSecretDataResponse{}
+	req := &proto.MfaRotateSecretDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: deviceId, AppName: "test"}
+	err := suite.service.RotateSecret(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+func (suite *ServiceTestSuite) checkCode(secret string) bool {
+	code, _ := totp.GenerateCode(secret, time.Now())
+	res := &proto.MfaCheckDataResponse{}
+	err := suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, res)
+	assert.NoError(suite.T(), err)
+
+	return res.Result
+}
+
+func (suite *ServiceTestSuite) TestRotateSecretToAcceptBothSecretsDuringGracePeriod() {
+	device := suite.createDevice("")
+
+	res := suite.rotateSecret("")
+	assert.Nil(suite.T(), res.Error)
+	assert.NotEqual(suite.T(), device.SecretKey, res.SecretKey)
+	assert.Equal(suite.T(), device.DeviceID, res.DeviceID)
+	assert.InDelta(suite.T(), time.Now().Add(defaultSecretRotationGrace).Unix(), res.GraceExpiresAt, 2)
+
+	assert.True(suite.T(), suite.checkCode(device.SecretKey))
+	assert.True(suite.T(), suite.checkCode(res.SecretKey))
+	assert.False(suite.T(), suite.checkCode(device.SecretKey))
+	assert.True(suite.T(), suite.checkCode(res.SecretKey))
+
+	events := &proto.MfaQueryAuditEventsDataResponse{}
+	_ = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{
+		UserID: suite.userID,
+		Types:  []string{AuditSecretRotationStarted, AuditSecretRotationCompleted},
+	}, events)
+	assert.Equal(suite.T(), 2, len(events.Events))
+}
+
+func (suite *ServiceTestSuite) TestRotateSecretToDiscardUnconfirmedSecretAfterGracePeriod() {
+	suite.service = NewService(suite.redis, zap.L(), SecretRotationGrace(-time.Second))
+	device := suite.createDevice("")
+	res := suite.rotateSecret(device.DeviceID)
+
+	assert.False(suite.T(), suite.checkCode(res.SecretKey))
+	assert.True(suite.T(), suite.checkCode(device.SecretKey))
+
+	devices, err := suite.service.loadDevices(suite.userID, suite.ProviderID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), device.SecretKey, devices[0].Secret)
+	assert.Nil(suite.T(), devices[0].Rotation)
+
+	events := &proto.MfaQueryAuditEventsDataResponse{}
+	_ = suite.service.QueryAuditEvents(context.TODO(), &proto.MfaQueryAuditEventsDataRequest{
+		UserID: suite.userID,
+		Types:  []string{AuditSecretRotationCompleted, AuditSecretRotationExpired},
+	}, events)
+	assert.Equal(suite.T(), 1, len(events.Events))
+	assert.Equal(suite.T(), AuditSecretRotationExpired, events.Events[0].Type)
+}
+
+func (suite *ServiceTestSuite) TestRotateSecretToRequireDeviceIdForMultipleDevices() {
+	suite.createDevice("phone")
+	suite.createDevice("tablet")
+
+	err := suite.service.RotateSecret(context.TODO(), &proto.MfaRotateSecretDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, AppName: "test"}, &proto.MfaRotateSecretDataResponse{})
+	assert.EqualError(suite.T(), err, "DeviceID is required field")
+}
+
+func (suite *ServiceTestSuite) TestRotateSecretToReturnErrorForUnknownDevice() {
+	suite.createDevice("")
+
+	res := suite.rotateSecret("unknown")
+	assert.Equal(suite.T(), ErrorDeviceNotExists, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestCompleteRotationToKeepRotationReplacedInTheMeantime() {
+	device := suite.createDevice("")
+	suite.rotateSecret("")
+	devices, _ := suite.service.loadDevices(suite.userID, suite.ProviderID)
+	stale := devices[0]
+
+	// Rotated again while a code of the first new secret is validated.
+	res := suite.rotateSecret("")
+	suite.service.completeRotation(suite.userID, suite.ProviderID, stale, 0, &proto.ProviderConfig{}, "confirmed")
+
+	devices, _ = suite.service.loadDevices(suite.userID, suite.ProviderID)
+	assert.Equal(suite.T(), device.SecretKey, devices[0].Secret)
+	assert.Equal(suite.T(), res.SecretKey, devices[0].Rotation.Secret)
+}
+
+func (suite *ServiceTestSuite) TestExpireRotationToNotRestoreRemovedDevice() {
+	device := suite.createDevice("")
+	suite.rotateSecret("")
+	devices, _ := suite.service.loadDevices(suite.userID, suite.ProviderID)
+
+	req := &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: device.DeviceID}
+	_ = suite.service.RemoveDevice(context.TODO(), req, &proto.MfaRemoveDeviceDataResponse{})
+	suite.service.expireRotation(suite.userID, suite.ProviderID, devices[0])
+
+	exists, _ := suite.redis.HExists(suite.service.GetDeviceStorageKey(suite.userID, suite.ProviderID), device.DeviceID).Result()
+	assert.False(suite.T(), exists)
+}
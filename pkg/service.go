@@ -6,16 +6,23 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/ProtocolONE/mfa-service/pkg/proto"
 	"github.com/go-redis/redis"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"image"
+	"image/color"
 	"image/png"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -25,62 +32,162 @@ const (
 	mfaRecoveryStoragePattern = "mfa_recovery_%s_%s"
 	mfaSecretStoragePattern   = "mfa_secret_%s"
 
+	MethodTotp         = "totp"
+	MethodYubiKey      = "yubikey"
+	MethodRecoveryCode = "recovery_code"
+	MethodBypassCode   = "bypass_code"
+
 	ErrorSecretKeyNotExists      = "Secret key not exists"
 	ErrorCodeInvalid             = "Invalid code"
 	ErrorRequestPropertyRequired = "%s is required field"
 )
 
+// requestError is returned by handlers when the request fails validation.
+type requestError struct {
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func newRequestError(format string, property string) error {
+	return &requestError{message: fmt.Sprintf(format, property)}
+}
+
 type service struct {
 	redis  *redis.Client
 	logger *zap.Logger
+	// options hold the Options replaced by Reload, they are read with opts.
+	options *atomic.Value
+	clock   *clockState
+	keys    *signingKeyCache
+	// ctx is the context of the call the service copy was made for by withContext.
+	ctx context.Context
+}
+
+func NewService(redis *redis.Client, logger *zap.Logger, opts ...Option) *service {
+	s := &service{
+		redis:   redis,
+		logger:  logger,
+		options: &atomic.Value{},
+		clock:   &clockState{},
+		keys:    &signingKeyCache{parsed: map[string]*signingKey{}},
+	}
+	s.Reload(opts...)
+
+	return s
+}
+
+// Reload replaces the options of the running service. Calls read the options
+// as they need them, so a call in flight may see the new ones.
+func (s *service) Reload(opts ...Option) {
+	options := newOptions(opts...)
+	if options.AuditSink == nil {
+		options.AuditSink = NewStorageAuditSink(s.redis, DefaultAuditMaxLen)
+	}
+	if options.Notifier == nil {
+		options.Notifier = NewLogNotifier(s.logger)
+	}
+
+	s.options.Store(options)
+}
+
+func (s *service) opts() Options {
+	return s.options.Load().(Options)
 }
 
-func NewService(redis *redis.Client, logger *zap.Logger) *service {
-	return &service{
-		redis:  redis,
-		logger: logger,
+// withContext returns a copy of the service for a call. Its entries are logged
+// with the correlation and trace IDs of the context, storage calls and other
+// work are traced as children of the span of the context. The service is
+// returned as is when the context carries neither.
+func (s *service) withContext(ctx context.Context) *service {
+	id := CorrelationID(ctx)
+	span := trace.SpanContextFromContext(ctx)
+	if id == "" && !span.IsValid() {
+		return s
+	}
+
+	c := *s
+	c.ctx = ctx
+	if id != "" {
+		c.logger = c.logger.With(zap.String("correlationId", id))
+	}
+	if span.IsValid() {
+		c.logger = c.logger.With(zap.String("traceId", span.TraceID().String()))
+		c.redis = traceStorage(ctx, s.redis)
 	}
+
+	return &c
 }
 
 func (s *service) Create(ctx context.Context, req *proto.MfaCreateDataRequest, res *proto.MfaCreateDataResponse) error {
+	s = s.withContext(ctx)
+
 	if err := s.validateCreateRequest(req); err != nil {
 		s.logger.Error("Validate create request failed with error", zap.Error(err))
 
 		return err
 	}
 
-	an := req.UserID
-	if req.Email != "" {
-		an = req.Email
-	}
-	key, err := totp.Generate(totp.GenerateOpts{
-		Issuer:      req.AppName,
-		AccountName: an,
-	})
+	config, err := s.providerConfig(req.ProviderID)
 	if err != nil {
-		s.logger.Error("Generate a new TOTP Key failed with error", zap.Error(err))
 		return err
 	}
 
-	imageBased, err := s.generateBase64QrCode(key, int(req.QrSize))
-	if err != nil {
-		s.logger.Error("Generate base 64 qr code with error", zap.Error(err))
+	if config.Issuer == "" && req.AppName == "" {
+		err := newRequestError(ErrorRequestPropertyRequired, "AppName")
+		s.logger.Error("Validate create request failed with error", zap.Error(err))
 
 		return err
 	}
-	codes, err := s.generateRecoveryCodes(10)
-	if err != nil {
-		s.logger.Error("Generate recovery codes failed with error", zap.Error(err))
 
+	if !factorAllowed(config, MethodTotp) {
+		res.Error = &proto.Error{
+			Message: ErrorFactorNotAllowed,
+		}
+		return nil
+	}
+
+	key, imageBased, err := s.generateKey(config, req.AppName, req.UserID, req.Email, req.QrSize)
+	if err != nil {
 		return err
 	}
-	if err = s.redis.SAdd(s.GetRecoveryStorageKey(req.ProviderID, req.UserID), codes).Err(); err != nil {
-		s.logger.Error("Add recovery codes to Redis failed with error", zap.Error(err))
+	recoveryKey := s.GetRecoveryStorageKey(req.UserID, req.ProviderID)
+	exists, err := s.redis.Exists(recoveryKey).Result()
+	if err != nil {
+		s.logger.Error("Getting recovery codes from Redis failed with error", zap.Error(err))
 
 		return err
 	}
-	if err = s.redis.HSet(s.GetSecretStorageKey(req.UserID), req.ProviderID, key.Secret()).Err(); err != nil {
-		s.logger.Error("Add secret codes to Redis failed with error", zap.Error(err))
+	if exists == 0 && factorAllowed(config, MethodRecoveryCode) {
+		codes, err := s.generateRecoveryCodes(int(config.RecoveryCodeCount))
+		if err != nil {
+			s.logger.Error("Generate recovery codes failed with error", zap.Error(err))
+
+			return err
+		}
+		_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.SAdd(s.GetUserProvidersStorageKey(req.UserID), req.ProviderID)
+			pipe.SAdd(recoveryKey, codes)
+			return nil
+		})
+		if err != nil {
+			s.logger.Error("Add recovery codes to Redis failed with error", zap.Error(err))
+
+			return err
+		}
+		res.RecoveryCode = codes
+	}
+	d, err := s.addDevice(req.UserID, req.ProviderID, &device{
+		Name:      req.DeviceName,
+		Secret:    key.Secret(),
+		Digits:    int(config.TotpDigits),
+		Period:    int(config.TotpPeriod),
+		Algorithm: config.TotpAlgorithm,
+	})
+	if err != nil {
+		s.logger.Error("Add device to Redis failed with error", zap.Error(err))
 
 		return err
 	}
@@ -89,44 +196,154 @@ func (s *service) Create(ctx context.Context, req *proto.MfaCreateDataRequest, r
 	res.URL = key.URL()
 	res.ImageBased = imageBased
 	res.QrCodeURL = fmt.Sprintf(qrUrlPattern, url.QueryEscape(key.URL()))
-	res.RecoveryCode = codes
+	res.DeviceID = d.ID
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentCreated,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     MethodTotp,
+		DeviceID:   d.ID,
+	})
 
 	return nil
 }
 
 func (s *service) Check(ctx context.Context, req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse) error {
+	s = s.withContext(ctx)
+
 	if err := s.validateCheckRequest(req); err != nil {
 		s.logger.Error("Validate check request failed with error", zap.Error(err))
 
 		return err
 	}
 
+	config, err := s.providerConfig(req.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	defer func() {
+		observeVerification(req.ProviderID, res.Method, start)
+	}()
+
+	if s.clockSkewed() {
+		s.logger.Warn("Check rejected because of the clock skew", zap.String("providerId", req.ProviderID))
+
+		res.Error = &proto.Error{
+			Message: ErrorClockSkew,
+		}
+		s.auditVerification(req, res)
+		return nil
+	}
+
+	locked, err := s.lockedOut(req.UserID, req.ProviderID)
+	if err != nil {
+		return err
+	}
+	if locked {
+		res.Error = &proto.Error{
+			Message: ErrorLockedOut,
+		}
+		s.auditVerification(req, res)
+		return nil
+	}
+
+	err = s.verify(req, res, config)
+	s.auditVerification(req, res)
+	if err != nil {
+		return err
+	}
+
+	s.trackFailures(req, res, config)
+
+	if err := s.cancelAccountRecovery(req, res); err != nil {
+		return err
+	}
+
+	if err := s.rememberDevice(req, res, config); err != nil {
+		return err
+	}
+
+	return s.issueAssertion(req, res)
+}
+
+// verify checks the code against the bypass code, the YubiKeys, the devices or the recovery codes of the user.
+func (s *service) verify(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse, config *proto.ProviderConfig) error {
+	switch {
+	case isBypassCode(req.Code):
+		// Bypass codes are issued by administrators regardless of the allowed factors.
+		res.Method = MethodBypassCode
+		return s.checkBypassCode(req, res)
+	case isYubiKeyOTP(req.Code):
+		res.Method = MethodYubiKey
+	case len(regexp.MustCompile("[0-9]{6}").FindStringSubmatch(req.Code)) > 0:
+		res.Method = MethodTotp
+	default:
+		res.Method = MethodRecoveryCode
+	}
+
+	if !factorAllowed(config, res.Method) {
+		res.Error = &proto.Error{
+			Message: ErrorFactorNotAllowed,
+		}
+		return nil
+	}
+
+	if res.Method == MethodYubiKey {
+		return s.checkYubiKey(req, res)
+	}
+
 	res.Result = false
-	secret := s.redis.HGet(s.GetSecretStorageKey(req.UserID), req.ProviderID)
-	if secret.Err() != nil {
-		s.logger.Error("Getting secret key from Redis failed with error", zap.Error(secret.Err()))
+	devices, err := s.loadDevices(req.UserID, req.ProviderID)
+	if err == nil && len(devices) == 0 {
+		err = errors.New(ErrorSecretKeyNotExists)
+	}
+	if err != nil {
+		s.logger.Error("Getting devices from Redis failed with error", zap.Error(err))
 
 		res.Error = &proto.Error{
 			Message: ErrorSecretKeyNotExists,
 		}
-		return secret.Err()
+		return err
 	}
 
-	if len(regexp.MustCompile("[0-9]{6}").FindStringSubmatch(req.Code)) > 0 {
-		res.Result = totp.Validate(req.Code, secret.Val())
-		if res.Result != false {
-			s.logger.Warn("Validating TOTP code format failed", zap.String("code", req.Code))
+	if res.Method == MethodTotp {
+		for _, d := range devices {
+			if s.validateDevice(req.UserID, req.ProviderID, d, req.Code, config, time.Now()) {
+				res.Result = true
+				res.DeviceID = d.ID
+				res.DeviceName = d.Name
+
+				if d.ConfirmedAt == 0 {
+					s.confirmDevice(req.UserID, req.ProviderID, d)
+				}
+				break
+			}
+		}
+		if !res.Result {
+			s.logger.Warn(
+				"Validating TOTP code failed",
+				zap.String("userId", req.UserID),
+				zap.String("providerId", req.ProviderID),
+			)
 
 			res.Error = &proto.Error{
 				Message: ErrorCodeInvalid,
 			}
 		}
 	} else {
-		r := s.redis.SRem(s.GetRecoveryStorageKey(req.UserID, req.ProviderID), req.Code)
-		if r.Err() != nil || r.Val() < 1 {
+		// Imported recovery codes are stored as hashes, a stored hash itself is not a code.
+		var removed int64
+		if !strings.HasPrefix(req.Code, recoveryCodeHashPrefix) {
+			key := s.GetRecoveryStorageKey(req.UserID, req.ProviderID)
+			removed, err = s.redis.SRem(key, req.Code, recoveryCodeHashPrefix+sha256Hex(req.Code)).Result()
+		}
+		if err != nil || removed < 1 {
 			s.logger.Warn(
 				"Removing recovery code from Redis failed",
-				zap.Error(r.Err()),
+				zap.Error(err),
 				zap.String("userId", req.UserID),
 				zap.String("providerId", req.ProviderID),
 			)
@@ -142,6 +359,44 @@ func (s *service) Check(ctx context.Context, req *proto.MfaCheckDataRequest, res
 	return nil
 }
 
+// generateKey generates a TOTP key with the provider parameters and its QR code.
+// The issuer of the provider takes precedence over the application name.
+func (s *service) generateKey(config *proto.ProviderConfig, appName string, userId string, email string, qrSize int32) (*otp.Key, string, error) {
+	issuer := appName
+	if config.Issuer != "" {
+		issuer = config.Issuer
+	}
+
+	an := userId
+	if email != "" {
+		an = email
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: an,
+		Period:      uint(config.TotpPeriod),
+		Digits:      otp.Digits(config.TotpDigits),
+		Algorithm:   totpAlgorithms[config.TotpAlgorithm],
+	})
+	if err != nil {
+		s.logger.Error("Generate a new TOTP Key failed with error", zap.Error(err))
+
+		return nil, "", err
+	}
+
+	if qrSize == 0 {
+		qrSize = config.QrSize
+	}
+	imageBased, err := s.generateBase64QrCode(key, int(qrSize), config.QrForeground, config.QrBackground)
+	if err != nil {
+		s.logger.Error("Generate base 64 qr code with error", zap.Error(err))
+
+		return nil, "", err
+	}
+
+	return key, imageBased, nil
+}
+
 func (s *service) generateRecoveryCodes(count int) (codes []string, err error) {
 	secret := make([]byte, 10)
 	for i := 0; i < count; i++ {
@@ -153,18 +408,32 @@ func (s *service) generateRecoveryCodes(count int) (codes []string, err error) {
 	return codes, nil
 }
 
-func (s *service) generateBase64QrCode(key *otp.Key, size int) (string, error) {
+func (s *service) generateBase64QrCode(key *otp.Key, size int, foreground string, background string) (_ string, err error) {
 	if size == 0 {
-		size = 200
+		size = defaultQrSize
 	}
 
+	span := s.startSpan("qr.generate", attribute.Int("mfa.qr_size", size))
+	defer func() { endSpan(span, err) }()
+
 	var buf bytes.Buffer
 	img, err := key.Image(size, size)
 	if err != nil {
 		return "", err
 	}
 
-	err = png.Encode(&buf, img)
+	// The code is drawn black on white, recolor it with the provider colors.
+	palette := color.Palette{parseColor(background), parseColor(foreground)}
+	branded := image.NewPaletted(img.Bounds(), palette)
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray); gray.Y < 128 {
+				branded.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	err = png.Encode(&buf, branded)
 	if err != nil {
 		return "", err
 	}
@@ -172,28 +441,26 @@ func (s *service) generateBase64QrCode(key *otp.Key, size int) (string, error) {
 	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
 }
 
+// validateCreateRequest does not check AppName, it is not required when the provider has an issuer.
 func (s *service) validateCreateRequest(req *proto.MfaCreateDataRequest) error {
 	if req.ProviderID == "" {
-		return fmt.Errorf(ErrorRequestPropertyRequired, "ProviderID")
-	}
-	if req.AppName == "" {
-		return fmt.Errorf(ErrorRequestPropertyRequired, "AppName")
+		return newRequestError(ErrorRequestPropertyRequired, "ProviderID")
 	}
 	if req.UserID == "" {
-		return fmt.Errorf(ErrorRequestPropertyRequired, "UserID")
+		return newRequestError(ErrorRequestPropertyRequired, "UserID")
 	}
 	return nil
 }
 
 func (s *service) validateCheckRequest(req *proto.MfaCheckDataRequest) error {
 	if req.ProviderID == "" {
-		return fmt.Errorf(ErrorRequestPropertyRequired, "ProviderID")
+		return newRequestError(ErrorRequestPropertyRequired, "ProviderID")
 	}
 	if req.UserID == "" {
-		return fmt.Errorf(ErrorRequestPropertyRequired, "UserID")
+		return newRequestError(ErrorRequestPropertyRequired, "UserID")
 	}
 	if req.Code == "" {
-		return fmt.Errorf(ErrorRequestPropertyRequired, "Code")
+		return newRequestError(ErrorRequestPropertyRequired, "Code")
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package mfa
 
 import (
 	"context"
+	"fmt"
 	"github.com/ProtocolONE/mfa-service/pkg/proto"
 	"github.com/go-redis/redis"
 	"github.com/pquerna/otp/totp"
@@ -34,6 +35,12 @@ func (suite *ServiceTestSuite) SetupTest() {
 
 	suite.redis = r
 	suite.service = NewService(r, zap.L())
+
+	// The metric state is shared by the tests, providers of earlier ones must not count.
+	metricProviders = &providerLabels{seen: map[string]bool{}}
+	if err := r.Del(mfaLowRecoveryCodesProviders).Err(); err != nil {
+		panic(err)
+	}
 	suite.userID = strconv.Itoa(random(1000000, 9999999))
 	suite.ProviderID = strconv.Itoa(random(1000000, 9999999))
 }
@@ -181,6 +188,23 @@ func (suite *ServiceTestSuite) deleteKeys() error {
 	return suite.redis.Del(
 		suite.service.GetRecoveryStorageKey(suite.userID, suite.ProviderID),
 		suite.service.GetSecretStorageKey(suite.userID),
+		suite.service.GetYubiKeyStorageKey(suite.userID, suite.ProviderID),
+		suite.service.GetDeviceStorageKey(suite.userID, suite.ProviderID),
+		suite.service.GetTrustedDeviceStorageKey(suite.userID, suite.ProviderID),
+		suite.service.GetFailureStorageKey(suite.userID, suite.ProviderID),
+		suite.service.GetLockoutStorageKey(suite.userID, suite.ProviderID),
+		suite.service.GetBypassStorageKey(suite.userID, suite.ProviderID),
+		suite.service.GetAccountRecoveryStorageKey(suite.userID, suite.ProviderID),
+		suite.service.GetUserProvidersStorageKey(suite.userID),
+		mfaAccountRecoverySchedule,
+		mfaSigningKeyStorage,
+		mfaSigningKeyLease,
+		mfaProviderStorage,
+		mfaAuditStorage,
+		mfaOutboxStorage,
+		mfaOutboxInstancesStorage,
+		fmt.Sprintf(mfaLowRecoveryCodesStoragePattern, suite.ProviderID),
+		mfaLowRecoveryCodesProviders,
 	).Err()
 }
 
@@ -0,0 +1,424 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	mfaSigningKeyStorage = "mfa_signing_keys"
+	mfaSigningKeyLease   = "mfa_signing_key_lease"
+	signingKeyBits       = 2048
+	signingKeyIDSize     = 8
+	signingKeyAlgorithm  = "RS256"
+	// jwksMaxAge is how long verifiers may cache the JWKS, a new key is
+	// published for this long before assertions are signed with it.
+	jwksMaxAge = 5 * time.Minute
+	// signingKeyCacheTTL is how long the parsed keys are used before they are
+	// loaded from Redis again.
+	signingKeyCacheTTL = 10 * time.Second
+	// signingKeyLeaseTTL bounds how long a caller holds the rotation lease and
+	// how long the others wait for the first key.
+	signingKeyLeaseTTL   = 30 * time.Second
+	signingKeyLeaseRetry = 100 * time.Millisecond
+)
+
+// signingKey is an RSA key used to sign step-up assertions. Keys are kept in
+// Redis so every instance of the service signs with the same key, the PEM of
+// the private key is encrypted with the SigningKeySecret when one is set.
+type signingKey struct {
+	ID         string `json:"id"`
+	PrivateKey string `json:"private_key"`
+	Encrypted  bool   `json:"encrypted,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+
+	key *rsa.PrivateKey
+}
+
+// signingKeyCache holds the parsed signing keys, it is shared by the copies of
+// the service.
+type signingKeyCache struct {
+	mu       sync.Mutex
+	parsed   map[string]*signingKey
+	keys     []*signingKey
+	loadedAt time.Time
+}
+
+// add caches a key generated by the instance, the keys are ordered by creation time.
+func (c *signingKeyCache) add(sk *signingKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.parsed[sk.ID] = sk
+	c.keys = append(c.keys[:len(c.keys):len(c.keys)], sk)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWKSHandler publishes the public part of the signing keys as a JSON Web Key Set.
+func (s *service) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys, err := s.signingKeys()
+		if err != nil {
+			s.logger.Error("Getting signing keys failed with error", zap.Error(err))
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		set := jwks{Keys: []jwk{}}
+		for _, k := range keys {
+			set.Keys = append(set.Keys, jwk{
+				Kty: "RSA",
+				Kid: k.ID,
+				Use: "sig",
+				Alg: signingKeyAlgorithm,
+				N:   base64.RawURLEncoding.EncodeToString(k.key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.key.E)).Bytes()),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwksMaxAge.Seconds())))
+		if err := json.NewEncoder(w).Encode(set); err != nil {
+			s.logger.Error("Encode JWKS failed with error", zap.Error(err))
+		}
+	})
+}
+
+// activeSigningKey returns the key to sign with, the next key is generated
+// the JWKS max age before the rotation interval ends. The first key is used at
+// once as there is no other to sign with.
+func (s *service) activeSigningKey() (*signingKey, error) {
+	keys, err := s.signingKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := s.opts().SigningKeyRotation
+	if signingKeyDue(keys, rotation) {
+		if keys, err = s.rotateSigningKeyLeased(rotation); err != nil {
+			return nil, err
+		}
+	}
+
+	return currentSigningKey(keys, rotation), nil
+}
+
+func signingKeyDue(keys []*signingKey, rotation time.Duration) bool {
+	return len(keys) == 0 || signingKeyAge(keys[len(keys)-1]) >= rotation-signingKeyPublishDelay(rotation)
+}
+
+// rotateSigningKeyLeased generates the next key while holding a lease in
+// Redis, so one caller of all the instances rotates at a time. The others
+// re-read the keys, they wait for the first key as there is no other to sign with.
+func (s *service) rotateSigningKeyLeased(rotation time.Duration) ([]*signingKey, error) {
+	deadline := time.Now().Add(signingKeyLeaseTTL)
+	for {
+		keys, leased, err := s.rotateSigningKeyOnLease(rotation)
+		if err != nil || leased || len(keys) > 0 {
+			return keys, err
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("signing key is not rotated in time")
+		}
+		time.Sleep(signingKeyLeaseRetry)
+	}
+}
+
+// rotateSigningKeyOnLease takes the rotation lease and generates the next key
+// when it is still due once the keys are re-read. Without the lease it returns
+// the keys re-read.
+func (s *service) rotateSigningKeyOnLease(rotation time.Duration) ([]*signingKey, bool, error) {
+	leased, err := s.redis.SetNX(mfaSigningKeyLease, 1, signingKeyLeaseTTL).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if leased {
+		defer func() {
+			if err := s.redis.Del(mfaSigningKeyLease).Err(); err != nil {
+				s.logger.Warn("Releasing signing key lease in Redis failed", zap.Error(err))
+			}
+		}()
+	}
+
+	keys, err := s.reloadSigningKeys()
+	if err != nil || !leased || !signingKeyDue(keys, rotation) {
+		return keys, leased, err
+	}
+
+	next, err := s.rotateSigningKey()
+	if err != nil {
+		return nil, true, err
+	}
+
+	// The slice is cached, it is not appended to in place.
+	return append(keys[:len(keys):len(keys)], next), true, nil
+}
+
+// currentSigningKey returns the newest of the keys published for at least the
+// JWKS max age, so verifiers with a cached JWKS know it, or the newest key when
+// none is. It returns nil when there are no keys.
+func currentSigningKey(keys []*signingKey, rotation time.Duration) *signingKey {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	publish := signingKeyPublishDelay(rotation)
+	for i := len(keys) - 1; i >= 0; i-- {
+		if age := signingKeyAge(keys[i]); age >= publish {
+			if age < rotation+publish {
+				return keys[i]
+			}
+			break
+		}
+	}
+
+	return keys[len(keys)-1]
+}
+
+// signingKeyPublishDelay returns how long a new key is published before it is
+// used, at most half of the rotation interval.
+func signingKeyPublishDelay(rotation time.Duration) time.Duration {
+	if jwksMaxAge > rotation/2 {
+		return rotation / 2
+	}
+	return jwksMaxAge
+}
+
+func signingKeyAge(sk *signingKey) time.Duration {
+	return time.Since(time.Unix(sk.CreatedAt, 0))
+}
+
+func (s *service) rotateSigningKey() (*signingKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, signingKeyIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	sk := &signingKey{
+		ID:        hex.EncodeToString(id),
+		CreatedAt: time.Now().Unix(),
+		key:       key,
+	}
+
+	privateKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	if secret := s.opts().SigningKeySecret; len(secret) > 0 {
+		if privateKey, err = sealSigningKey(secret, sk.ID, privateKey); err != nil {
+			return nil, err
+		}
+		sk.Encrypted = true
+	}
+	sk.PrivateKey = string(privateKey)
+
+	data, err := json.Marshal(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.redis.HSet(mfaSigningKeyStorage, sk.ID, data).Err(); err != nil {
+		return nil, err
+	}
+	s.keys.add(sk)
+
+	s.logger.Info("Assertion signing key rotated", zap.String("kid", sk.ID))
+
+	if err := s.removeExpiredSigningKeys(); err != nil {
+		s.logger.Warn("Removing expired signing keys from Redis failed", zap.Error(err))
+	}
+
+	return sk, nil
+}
+
+// removeExpiredSigningKeys removes the keys which can not have signed a still
+// valid assertion from Redis.
+func (s *service) removeExpiredSigningKeys() error {
+	values, err := s.redis.HGetAll(mfaSigningKeyStorage).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	retention := s.signingKeyRetention()
+	var expired []string
+	for id, data := range values {
+		sk := &signingKey{}
+		if err := json.Unmarshal([]byte(data), sk); err != nil {
+			return err
+		}
+		if signingKeyAge(sk) > retention {
+			expired = append(expired, id)
+		}
+	}
+
+	if len(expired) == 0 {
+		return nil
+	}
+
+	return s.redis.HDel(mfaSigningKeyStorage, expired...).Err()
+}
+
+func (s *service) signingKeyRetention() time.Duration {
+	options := s.opts()
+	return options.SigningKeyRotation + signingKeyPublishDelay(options.SigningKeyRotation) + options.AssertionTTL
+}
+
+// signingKeys returns the keys ordered by creation time without the ones
+// which can not have signed a still valid assertion, those are removed from
+// Redis on rotation. The keys are cached for signingKeyCacheTTL, a key is
+// parsed once.
+func (s *service) signingKeys() ([]*signingKey, error) {
+	s.keys.mu.Lock()
+	defer s.keys.mu.Unlock()
+
+	if time.Since(s.keys.loadedAt) < signingKeyCacheTTL {
+		return s.keys.keys, nil
+	}
+
+	values, err := s.redis.HGetAll(mfaSigningKeyStorage).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	retention := s.signingKeyRetention()
+	parsed := map[string]*signingKey{}
+	var keys []*signingKey
+	for id, data := range values {
+		sk, ok := s.keys.parsed[id]
+		if !ok {
+			if sk, err = s.parseSigningKey(data); err != nil {
+				return nil, err
+			}
+		}
+
+		if signingKeyAge(sk) > retention {
+			continue
+		}
+
+		parsed[id] = sk
+		keys = append(keys, sk)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt != keys[j].CreatedAt {
+			return keys[i].CreatedAt < keys[j].CreatedAt
+		}
+		return keys[i].ID < keys[j].ID
+	})
+
+	s.keys.parsed, s.keys.keys, s.keys.loadedAt = parsed, keys, time.Now()
+
+	return keys, nil
+}
+
+// reloadSigningKeys returns the keys loaded from Redis regardless of the cache.
+func (s *service) reloadSigningKeys() ([]*signingKey, error) {
+	s.keys.mu.Lock()
+	s.keys.loadedAt = time.Time{}
+	s.keys.mu.Unlock()
+
+	return s.signingKeys()
+}
+
+func (s *service) parseSigningKey(data string) (*signingKey, error) {
+	sk := &signingKey{}
+	if err := json.Unmarshal([]byte(data), sk); err != nil {
+		return nil, err
+	}
+
+	privateKey := []byte(sk.PrivateKey)
+	if sk.Encrypted {
+		secret := s.opts().SigningKeySecret
+		if len(secret) == 0 {
+			return nil, errors.New("signing key is encrypted and no signing key secret is set")
+		}
+
+		var err error
+		if privateKey, err = openSigningKey(secret, sk.ID, privateKey); err != nil {
+			return nil, err
+		}
+	}
+
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+
+	var err error
+	if sk.key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+		return nil, err
+	}
+
+	return sk, nil
+}
+
+// sealSigningKey encrypts the PEM with AES-GCM under the SHA-256 of the
+// secret, the key id is authenticated with it. The nonce is prepended and the
+// result base64 encoded.
+func sealSigningKey(secret []byte, id string, privateKey []byte) ([]byte, error) {
+	key := sha256.Sum256(secret)
+	aead, err := newArchiveAEAD(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := aead.Seal(nonce, nonce, privateKey, []byte(id))
+
+	return []byte(base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+func openSigningKey(secret []byte, id string, data []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	key := sha256.Sum256(secret)
+	aead, err := newArchiveAEAD(key[:])
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("signing key is too short")
+	}
+
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+}
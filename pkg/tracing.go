@@ -0,0 +1,196 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpcMetadata "google.golang.org/grpc/metadata"
+	"strings"
+)
+
+const (
+	tracerName = "github.com/ProtocolONE/mfa-service/pkg"
+
+	// handlerSpanPrefix names handler spans like gRPC methods, for example MfaService/Create.
+	handlerSpanPrefix = "MfaService/"
+
+	attributeProviderID = attribute.Key("mfa.provider_id")
+	attributeError      = attribute.Key("mfa.error")
+)
+
+// TraceHandlerWrapper starts a span for every call of the micro server, the
+// trace context of the caller is taken from the go-micro metadata.
+func TraceHandlerWrapper() server.HandlerWrapper {
+	return func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			md, _ := metadata.FromContext(ctx)
+			ctx = otel.GetTextMapPropagator().Extract(ctx, microCarrier(md))
+
+			op := req.Method()
+			if i := strings.LastIndex(op, "."); i >= 0 {
+				op = op[i+1:]
+			}
+
+			ctx, span := startHandlerSpan(ctx, op, req.Body())
+			err := fn(ctx, req, rsp)
+			endHandlerSpan(span, rsp, err)
+
+			return err
+		}
+	}
+}
+
+// TraceUnaryInterceptor starts a span for every MfaService call of the gRPC
+// server, the trace context of the caller is taken from the gRPC metadata.
+func TraceUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, grpcServicePath) {
+			return handler(ctx, req)
+		}
+
+		md, _ := grpcMetadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, grpcCarrier(md))
+
+		ctx, span := startHandlerSpan(ctx, strings.TrimPrefix(info.FullMethod, grpcServicePath), req)
+		res, err := handler(ctx, req)
+		endHandlerSpan(span, res, err)
+
+		return res, err
+	}
+}
+
+func startHandlerSpan(ctx context.Context, op string, req interface{}) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, handlerSpanPrefix+op, trace.WithSpanKind(trace.SpanKindServer))
+	if r, ok := req.(interface{ GetProviderID() string }); ok && r.GetProviderID() != "" {
+		span.SetAttributes(attributeProviderID.String(r.GetProviderID()))
+	}
+
+	return ctx, span
+}
+
+// endHandlerSpan marks the span failed when the call failed, errors returned
+// in the response are only recorded as an attribute.
+func endHandlerSpan(span trace.Span, res interface{}, err error) {
+	if e, ok := res.(interface{ GetError() *proto.Error }); ok && e.GetError() != nil {
+		span.SetAttributes(attributeError.String(e.GetError().Message))
+	}
+	endSpan(span, err)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startSpan starts a child span of the context of the service.
+func (s *service) startSpan(name string, attrs ...attribute.KeyValue) trace.Span {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return span
+}
+
+// traceStorage returns a copy of the client starting a span for every command
+// and pipeline. Missing keys are not reported as errors.
+func traceStorage(ctx context.Context, client *redis.Client) *redis.Client {
+	c := client.WithContext(ctx)
+	tracer := otel.Tracer(tracerName)
+
+	c.WrapProcess(func(process func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			_, span := tracer.Start(ctx, "redis."+cmd.Name(), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+				semconv.DBSystemRedis,
+				semconv.DBOperation(cmd.Name()),
+			))
+			err := process(cmd)
+			if err == redis.Nil {
+				err = nil
+			}
+			endSpan(span, err)
+
+			return err
+		}
+	})
+	c.WrapProcessPipeline(func(process func(cmds []redis.Cmder) error) func(cmds []redis.Cmder) error {
+		return func(cmds []redis.Cmder) error {
+			_, span := tracer.Start(ctx, "redis.pipeline", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+				semconv.DBSystemRedis,
+				semconv.DBOperation("pipeline"),
+				attribute.Int("db.redis.commands", len(cmds)),
+			))
+			err := process(cmds)
+			if err == redis.Nil {
+				err = nil
+			}
+			endSpan(span, err)
+
+			return err
+		}
+	})
+
+	return c
+}
+
+// microCarrier reads the trace context from go-micro metadata, the transports
+// may change the case of the keys.
+type microCarrier metadata.Metadata
+
+func (c microCarrier) Get(key string) string {
+	if v, ok := c[key]; ok {
+		return v
+	}
+	for k, v := range c {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+func (c microCarrier) Set(key string, value string) {
+	c[key] = value
+}
+
+func (c microCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// grpcCarrier reads the trace context from gRPC metadata.
+type grpcCarrier grpcMetadata.MD
+
+func (c grpcCarrier) Get(key string) string {
+	if v := grpcMetadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c grpcCarrier) Set(key string, value string) {
+	grpcMetadata.MD(c).Set(key, value)
+}
+
+func (c grpcCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,108 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpcMetadata "google.golang.org/grpc/metadata"
+)
+
+const (
+	testTraceID    = "4bf92f3577b34da6a3ce929d0"
+	testParentSpan = "00f067aa0ba902b7"
+)
+
+var spanRecorder = tracetest.NewSpanRecorder()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// traceparent returns a sampled trace context header of a new trace.
+func (suite *ServiceTestSuite) traceparent() (string, string) {
+	traceId := testTraceID + suite.ProviderID
+	return "00-" + traceId + "-" + testParentSpan + "-01", traceId
+}
+
+func endedSpans(traceId string) map[string]sdktrace.ReadOnlySpan {
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spanRecorder.Ended() {
+		if span.SpanContext().TraceID().String() == traceId {
+			spans[span.Name()] = span
+		}
+	}
+	return spans
+}
+
+func (suite *ServiceTestSuite) TestTraceHandlerWrapperToContinueTraceFromMetadata() {
+	header, traceId := suite.traceparent()
+
+	fn := TraceHandlerWrapper()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return suite.service.Create(ctx, req.Body().(*proto.MfaCreateDataRequest), rsp.(*proto.MfaCreateDataResponse))
+	})
+	ctx := metadata.NewContext(context.TODO(), metadata.Metadata{"Traceparent": header})
+	req := &testRequest{method: "MfaService.Create", body: &proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, AppName: "test", UserID: suite.userID}}
+	err := fn(ctx, req, &proto.MfaCreateDataResponse{})
+	assert.NoError(suite.T(), err)
+
+	spans := endedSpans(traceId)
+	handler, ok := spans["MfaService/Create"]
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), testParentSpan, handler.Parent().SpanID().String())
+	assert.Equal(suite.T(), trace.SpanKindServer, handler.SpanKind())
+	assert.Contains(suite.T(), handler.Attributes(), attributeProviderID.String(suite.ProviderID))
+
+	qr, ok := spans["qr.generate"]
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), handler.SpanContext().SpanID(), qr.Parent().SpanID())
+
+	storage := 0
+	for name, span := range spans {
+		if span.Parent().SpanID() == handler.SpanContext().SpanID() && span.SpanKind() == trace.SpanKindClient {
+			assert.Regexp(suite.T(), "^redis\\.", name)
+			storage++
+		}
+	}
+	assert.NotZero(suite.T(), storage)
+}
+
+func (suite *ServiceTestSuite) TestTraceUnaryInterceptorToRecordErrors() {
+	header, traceId := suite.traceparent()
+
+	interceptor := TraceUnaryInterceptor()
+	ctx := grpcMetadata.NewIncomingContext(context.TODO(), grpcMetadata.Pairs("traceparent", header))
+	_, err := interceptor(ctx, &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID}, &grpc.UnaryServerInfo{FullMethod: grpcServicePath + "Check"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+	assert.Error(suite.T(), err)
+
+	span, ok := endedSpans(traceId)["MfaService/Check"]
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), testParentSpan, span.Parent().SpanID().String())
+	assert.Equal(suite.T(), codes.Error, span.Status().Code)
+}
+
+func (suite *ServiceTestSuite) TestTraceStorageToIgnoreMissingKeys() {
+	header, traceId := suite.traceparent()
+	ctx := otel.GetTextMapPropagator().Extract(context.TODO(), propagation.MapCarrier{"traceparent": header})
+
+	s := suite.service.withContext(ctx)
+	assert.Equal(suite.T(), redis.Nil, s.redis.Get("mfa_missing_"+suite.ProviderID).Err())
+	assert.Equal(suite.T(), suite.redis, suite.service.withContext(context.TODO()).redis)
+
+	span, ok := endedSpans(traceId)["redis.get"]
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), codes.Unset, span.Status().Code)
+}
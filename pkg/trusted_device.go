@@ -0,0 +1,390 @@
+package mfa
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	mfaTrustedDeviceStoragePattern = "mfa_trusted_%s_%s"
+	trustedDeviceIDSize            = 16
+
+	ErrorTrustedDeviceInvalid   = "Trusted device token invalid"
+	ErrorTrustedDeviceExpired   = "Trusted device token expired"
+	ErrorTrustedDeviceNotExists = "Trusted device not exists"
+)
+
+// trustedDevice is a remembered browser of the user. The token handed to the
+// client is signed, the stored record makes it revocable.
+type trustedDevice struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	ExpiresAt   int64  `json:"expires_at"`
+	LastUsedAt  int64  `json:"last_used_at"`
+}
+
+func (s *service) ValidateTrustedDevice(ctx context.Context, req *proto.MfaValidateTrustedDeviceDataRequest, res *proto.MfaValidateTrustedDeviceDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateValidateTrustedDeviceRequest(req); err != nil {
+		s.logger.Error("Validate trusted device request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	id, expiresAt, ok := s.parseTrustedDeviceToken(req.UserID, req.ProviderID, req.Token)
+	if !ok {
+		res.Error = &proto.Error{Message: ErrorTrustedDeviceInvalid}
+		return nil
+	}
+
+	if time.Now().Unix() >= expiresAt {
+		res.Error = &proto.Error{Message: ErrorTrustedDeviceExpired}
+		return nil
+	}
+
+	key := s.GetTrustedDeviceStorageKey(req.UserID, req.ProviderID)
+	err := s.redis.Watch(func(tx *redis.Tx) error {
+		data, err := tx.HGet(key, id).Bytes()
+		if err != nil {
+			return err
+		}
+
+		td := &trustedDevice{}
+		if err = json.Unmarshal(data, td); err != nil {
+			return err
+		}
+
+		if time.Now().Unix() >= td.ExpiresAt {
+			res.Error = &proto.Error{Message: ErrorTrustedDeviceExpired}
+			return nil
+		}
+
+		if td.Fingerprint != "" && subtle.ConstantTimeCompare([]byte(td.Fingerprint), []byte(sha256Hex(req.Fingerprint))) != 1 {
+			res.Error = &proto.Error{Message: ErrorTrustedDeviceInvalid}
+			return nil
+		}
+
+		td.LastUsedAt = time.Now().Unix()
+		if data, err = json.Marshal(td); err != nil {
+			return err
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HSet(key, id, data)
+			return nil
+		})
+		return err
+	}, key)
+
+	if err == redis.Nil {
+		res.Error = &proto.Error{Message: ErrorTrustedDeviceInvalid}
+		return nil
+	}
+
+	if err != nil {
+		s.logger.Error("Validating trusted device failed with error", zap.Error(err))
+
+		return err
+	}
+
+	res.Result = res.Error == nil
+
+	return nil
+}
+
+func (s *service) ListTrustedDevices(ctx context.Context, req *proto.MfaListTrustedDevicesDataRequest, res *proto.MfaListTrustedDevicesDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		s.logger.Error("Validate list trusted devices request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	key := s.GetTrustedDeviceStorageKey(req.UserID, req.ProviderID)
+	values, err := s.redis.HGetAll(key).Result()
+	if err != nil {
+		s.logger.Error("Getting trusted devices from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	var expired []string
+	now := time.Now().Unix()
+	for id, data := range values {
+		td := &trustedDevice{}
+		if err := json.Unmarshal([]byte(data), td); err != nil {
+			s.logger.Error("Unmarshal trusted device failed with error", zap.Error(err))
+
+			return err
+		}
+
+		if now >= td.ExpiresAt {
+			expired = append(expired, id)
+			continue
+		}
+
+		res.Devices = append(res.Devices, &proto.TrustedDevice{
+			ID:               td.ID,
+			Name:             td.Name,
+			FingerprintBound: td.Fingerprint != "",
+			CreatedAt:        td.CreatedAt,
+			ExpiresAt:        td.ExpiresAt,
+			LastUsedAt:       td.LastUsedAt,
+		})
+	}
+
+	sort.Slice(res.Devices, func(i, j int) bool {
+		return res.Devices[i].CreatedAt < res.Devices[j].CreatedAt
+	})
+
+	if len(expired) > 0 {
+		if err = s.redis.HDel(key, expired...).Err(); err != nil {
+			s.logger.Warn("Removing expired trusted devices from Redis failed", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func (s *service) RevokeTrustedDevice(ctx context.Context, req *proto.MfaRevokeTrustedDeviceDataRequest, res *proto.MfaRevokeTrustedDeviceDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateRevokeTrustedDeviceRequest(req); err != nil {
+		s.logger.Error("Validate revoke trusted device request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if req.All {
+		if err := s.revokeTrustedDevices(req.UserID, req.ProviderID, "all"); err != nil {
+			s.logger.Error("Removing trusted devices from Redis failed with error", zap.Error(err))
+
+			return err
+		}
+
+		res.Result = true
+		return nil
+	}
+
+	key := s.GetTrustedDeviceStorageKey(req.UserID, req.ProviderID)
+
+	removed, err := s.redis.HDel(key, req.ID).Result()
+	if err != nil {
+		s.logger.Error("Removing trusted device from Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	if removed == 0 {
+		res.Error = &proto.Error{Message: ErrorTrustedDeviceNotExists}
+		return nil
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditTrustedDeviceRevoked,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		DeviceID:   req.ID,
+	})
+
+	res.Result = true
+
+	return nil
+}
+
+// rememberDevice issues a remember-device token after a successful check if the caller asked for it.
+func (s *service) rememberDevice(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse, config *proto.ProviderConfig) error {
+	if !res.Result || !req.RememberDevice {
+		return nil
+	}
+
+	if len(s.opts().TrustedDeviceSecret) == 0 {
+		s.logger.Warn("Remember device requested but trusted device secret is not configured")
+
+		return nil
+	}
+
+	id := make([]byte, trustedDeviceIDSize)
+	if _, err := rand.Read(id); err != nil {
+		s.logger.Error("Generate trusted device id failed with error", zap.Error(err))
+
+		return err
+	}
+
+	now := time.Now()
+	ttl := s.trustedDeviceTTL(config)
+	td := &trustedDevice{
+		ID:         hex.EncodeToString(id),
+		Name:       req.TrustedDeviceName,
+		CreatedAt:  now.Unix(),
+		ExpiresAt:  now.Add(ttl).Unix(),
+		LastUsedAt: now.Unix(),
+	}
+	if req.Fingerprint != "" {
+		td.Fingerprint = sha256Hex(req.Fingerprint)
+	}
+
+	data, err := json.Marshal(td)
+	if err != nil {
+		s.logger.Error("Marshal trusted device failed with error", zap.Error(err))
+
+		return err
+	}
+
+	// Every device expires on its own, the key lives as long as the latest one.
+	key := s.GetTrustedDeviceStorageKey(req.UserID, req.ProviderID)
+	err = s.redis.Watch(func(tx *redis.Tx) error {
+		values, err := tx.HGetAll(key).Result()
+		if err != nil {
+			return err
+		}
+
+		expiresAt := td.ExpiresAt
+		var expired []string
+		for id, value := range values {
+			other := &trustedDevice{}
+			if err := json.Unmarshal([]byte(value), other); err != nil || now.Unix() >= other.ExpiresAt {
+				expired = append(expired, id)
+				continue
+			}
+			if other.ExpiresAt > expiresAt {
+				expiresAt = other.ExpiresAt
+			}
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			if len(expired) > 0 {
+				pipe.HDel(key, expired...)
+			}
+			pipe.HSet(key, td.ID, data)
+			pipe.ExpireAt(key, time.Unix(expiresAt, 0))
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		s.logger.Error("Add trusted device to Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	res.TrustedDeviceToken = s.signTrustedDeviceToken(req.UserID, req.ProviderID, td.ID, td.ExpiresAt)
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditTrustedDeviceIssued,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		DeviceID:   td.ID,
+		ExpiresAt:  td.ExpiresAt,
+	})
+
+	return nil
+}
+
+// revokeTrustedDevices removes all remembered devices of the user for the provider.
+func (s *service) revokeTrustedDevices(userId string, providerId string, reason string) error {
+	removed, err := s.redis.Del(s.GetTrustedDeviceStorageKey(userId, providerId)).Result()
+	if err != nil {
+		return err
+	}
+
+	if removed > 0 {
+		s.audit(&proto.AuditEvent{
+			Type:       AuditTrustedDeviceRevoked,
+			UserID:     userId,
+			ProviderID: providerId,
+			Reason:     reason,
+		})
+	}
+
+	return nil
+}
+
+// trustedDeviceTTL returns the token lifetime of the provider, the service
+// default when the provider does not set one.
+func (s *service) trustedDeviceTTL(config *proto.ProviderConfig) time.Duration {
+	if config.TrustedDeviceTTL > 0 {
+		return time.Duration(config.TrustedDeviceTTL) * time.Second
+	}
+	return s.opts().TrustedDeviceTTL
+}
+
+// signTrustedDeviceToken returns a token in the "<id>.<expires at>.<signature>" form,
+// the signature binds it to the user and the provider.
+func (s *service) signTrustedDeviceToken(userId string, providerId string, id string, expiresAt int64) string {
+	payload := id + "." + strconv.FormatInt(expiresAt, 10)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.trustedDeviceSignature(userId, providerId, payload))
+}
+
+func (s *service) parseTrustedDeviceToken(userId string, providerId string, token string) (string, int64, bool) {
+	if len(s.opts().TrustedDeviceSecret) == 0 {
+		return "", 0, false
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", 0, false
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, s.trustedDeviceSignature(userId, providerId, parts[0]+"."+parts[1])) {
+		return "", 0, false
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return parts[0], expiresAt, true
+}
+
+func (s *service) trustedDeviceSignature(userId string, providerId string, payload string) []byte {
+	mac := hmac.New(sha256.New, s.opts().TrustedDeviceSecret)
+	mac.Write([]byte(userId + "\n" + providerId + "\n" + payload))
+
+	return mac.Sum(nil)
+}
+
+func (s *service) validateValidateTrustedDeviceRequest(req *proto.MfaValidateTrustedDeviceDataRequest) error {
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		return err
+	}
+	if req.Token == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "Token")
+	}
+	return nil
+}
+
+func (s *service) validateRevokeTrustedDeviceRequest(req *proto.MfaRevokeTrustedDeviceDataRequest) error {
+	if err := s.validateUserProvider(req.UserID, req.ProviderID); err != nil {
+		return err
+	}
+	if req.ID == "" && !req.All {
+		return newRequestError(ErrorRequestPropertyRequired, "ID")
+	}
+	return nil
+}
+
+func (s *service) GetTrustedDeviceStorageKey(userId string, providerId string) string {
+	return fmt.Sprintf(mfaTrustedDeviceStoragePattern, userId, providerId)
+}
@@ -0,0 +1,180 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"regexp"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestCheckToNotIssueTrustedDeviceWithoutSecret() {
+	res := suite.checkRememberDevice("")
+
+	assert.True(suite.T(), res.Result)
+	assert.Empty(suite.T(), res.TrustedDeviceToken)
+}
+
+func (suite *ServiceTestSuite) TestValidateTrustedDeviceToReturnTrue() {
+	suite.service = NewService(suite.redis, zap.L(), TrustedDeviceSecret([]byte("secret")))
+	token := suite.checkRememberDevice("fingerprint").TrustedDeviceToken
+	assert.NotEmpty(suite.T(), token)
+
+	res := &proto.MfaValidateTrustedDeviceDataResponse{}
+	req := &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Token: token, Fingerprint: "fingerprint"}
+	err := suite.service.ValidateTrustedDevice(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+	assert.Nil(suite.T(), res.Error)
+}
+
+func (suite *ServiceTestSuite) TestValidateTrustedDeviceToReturnFalseWithOtherFingerprint() {
+	suite.service = NewService(suite.redis, zap.L(), TrustedDeviceSecret([]byte("secret")))
+	token := suite.checkRememberDevice("fingerprint").TrustedDeviceToken
+
+	res := &proto.MfaValidateTrustedDeviceDataResponse{}
+	req := &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Token: token, Fingerprint: "other"}
+	err := suite.service.ValidateTrustedDevice(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorTrustedDeviceInvalid, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestValidateTrustedDeviceToReturnFalseForOtherUser() {
+	suite.service = NewService(suite.redis, zap.L(), TrustedDeviceSecret([]byte("secret")))
+	token := suite.checkRememberDevice("").TrustedDeviceToken
+
+	res := &proto.MfaValidateTrustedDeviceDataResponse{}
+	req := &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID + "0", Token: token}
+	err := suite.service.ValidateTrustedDevice(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorTrustedDeviceInvalid, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestValidateTrustedDeviceToReturnFalseWhenExpired() {
+	suite.service = NewService(
+		suite.redis,
+		zap.L(),
+		TrustedDeviceSecret([]byte("secret")),
+		TrustedDeviceTTL(-time.Second),
+	)
+	token := suite.checkRememberDevice("").TrustedDeviceToken
+
+	res := &proto.MfaValidateTrustedDeviceDataResponse{}
+	req := &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Token: token}
+	err := suite.service.ValidateTrustedDevice(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorTrustedDeviceExpired, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestRememberDeviceToApplyProviderTrustedDeviceTTL() {
+	suite.service = NewService(suite.redis, zap.L(), TrustedDeviceSecret([]byte("secret")), TrustedDeviceTTL(time.Hour))
+	suite.setProviderConfig(&proto.ProviderConfig{TrustedDeviceTTL: 60})
+	suite.checkRememberDevice("")
+
+	ttl, err := suite.redis.TTL(suite.service.GetTrustedDeviceStorageKey(suite.userID, suite.ProviderID)).Result()
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), ttl > 0 && ttl <= time.Minute, "ttl %s", ttl)
+}
+
+func (suite *ServiceTestSuite) TestRevokeTrustedDeviceToInvalidateToken() {
+	suite.service = NewService(suite.redis, zap.L(), TrustedDeviceSecret([]byte("secret")))
+	token1 := suite.checkRememberDevice("").TrustedDeviceToken
+	token2 := suite.checkRememberDevice("").TrustedDeviceToken
+
+	list := &proto.MfaListTrustedDevicesDataResponse{}
+	err := suite.service.ListTrustedDevices(context.TODO(), &proto.MfaListTrustedDevicesDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, list)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(list.Devices))
+
+	res := &proto.MfaRevokeTrustedDeviceDataResponse{}
+	req := &proto.MfaRevokeTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, ID: list.Devices[0].ID}
+	err = suite.service.RevokeTrustedDevice(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+
+	valid1 := &proto.MfaValidateTrustedDeviceDataResponse{}
+	_ = suite.service.ValidateTrustedDevice(context.TODO(), &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Token: token1}, valid1)
+	valid2 := &proto.MfaValidateTrustedDeviceDataResponse{}
+	_ = suite.service.ValidateTrustedDevice(context.TODO(), &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Token: token2}, valid2)
+	assert.NotEqual(suite.T(), valid1.Result, valid2.Result)
+
+	res = &proto.MfaRevokeTrustedDeviceDataResponse{}
+	req = &proto.MfaRevokeTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, All: true}
+	err = suite.service.RevokeTrustedDevice(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+
+	list = &proto.MfaListTrustedDevicesDataResponse{}
+	_ = suite.service.ListTrustedDevices(context.TODO(), &proto.MfaListTrustedDevicesDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, list)
+	assert.Empty(suite.T(), list.Devices)
+}
+
+func (suite *ServiceTestSuite) TestRevokeTrustedDeviceToReturnErrorRequestData() {
+	req := &proto.MfaRevokeTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}
+	err := suite.service.RevokeTrustedDevice(context.TODO(), req, &proto.MfaRevokeTrustedDeviceDataResponse{})
+
+	assert.Regexp(suite.T(), regexp.MustCompile("is required field"), err)
+}
+
+func (suite *ServiceTestSuite) checkRememberDevice(fingerprint string) *proto.MfaCheckDataResponse {
+	res1 := suite.createDevice("")
+	code, _ := totp.GenerateCode(res1.SecretKey, time.Now())
+
+	res := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{
+		ProviderID:        suite.ProviderID,
+		UserID:            suite.userID,
+		Code:              code,
+		RememberDevice:    true,
+		Fingerprint:       fingerprint,
+		TrustedDeviceName: "laptop",
+	}
+	err := suite.service.Check(context.TODO(), req, res)
+	assert.NoError(suite.T(), err)
+
+	return res
+}
+
+func (suite *ServiceTestSuite) TestRememberDeviceToKeepOlderDevicesUntilTheirExpiry() {
+	suite.service = NewService(suite.redis, zap.L(), TrustedDeviceSecret([]byte("secret")), TrustedDeviceTTL(time.Hour))
+	token := suite.checkRememberDevice("").TrustedDeviceToken
+
+	suite.service.Reload(TrustedDeviceSecret([]byte("secret")), TrustedDeviceTTL(time.Minute))
+	suite.checkRememberDevice("")
+
+	ttl, err := suite.redis.TTL(suite.service.GetTrustedDeviceStorageKey(suite.userID, suite.ProviderID)).Result()
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), ttl > 59*time.Minute)
+
+	res := &proto.MfaValidateTrustedDeviceDataResponse{}
+	req := &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Token: token}
+	assert.NoError(suite.T(), suite.service.ValidateTrustedDevice(context.TODO(), req, res))
+	assert.True(suite.T(), res.Result)
+}
+
+func (suite *ServiceTestSuite) TestRemoveDeviceToRevokeTrustedDevicesWithTheLastEnrollment() {
+	suite.service = NewService(suite.redis, zap.L(), TrustedDeviceSecret([]byte("secret")))
+	token := suite.checkRememberDevice("").TrustedDeviceToken
+
+	devices := &proto.MfaListDevicesDataResponse{}
+	assert.NoError(suite.T(), suite.service.ListDevices(context.TODO(), &proto.MfaListDevicesDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, devices))
+	for _, d := range devices.Devices {
+		res := &proto.MfaRemoveDeviceDataResponse{}
+		assert.NoError(suite.T(), suite.service.RemoveDevice(context.TODO(), &proto.MfaRemoveDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, DeviceID: d.ID}, res))
+		assert.True(suite.T(), res.Result)
+	}
+
+	res := &proto.MfaValidateTrustedDeviceDataResponse{}
+	req := &proto.MfaValidateTrustedDeviceDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Token: token}
+	assert.NoError(suite.T(), suite.service.ValidateTrustedDevice(context.TODO(), req, res))
+	assert.False(suite.T(), res.Result)
+}
@@ -0,0 +1,394 @@
+package mfa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// mfaUserProvidersStoragePattern is the index of the providers a user has
+	// any storage for, it is added to before the storage is written.
+	mfaUserProvidersStoragePattern = "mfa_user_providers_%s"
+	// mfaUserProvidersIndexBuilt is set once the index covers the storage written
+	// before it existed.
+	mfaUserProvidersIndexBuilt = "mfa_user_index_built"
+
+	userDeletedEvent = "user_deleted"
+	userMergedEvent  = "user_merged"
+	userScanSize     = 100
+)
+
+// userStoragePatterns are the storage keys holding enrollments of a user for a provider.
+var userStoragePatterns = []string{
+	mfaDeviceStoragePattern,
+	mfaRecoveryStoragePattern,
+	mfaYubiKeyStoragePattern,
+	mfaTrustedDeviceStoragePattern,
+	mfaFailureStoragePattern,
+	mfaLockoutStoragePattern,
+	mfaBypassStoragePattern,
+	mfaAccountRecoveryStoragePattern,
+}
+
+// HandleUserDeleted removes all enrollments of the deleted user. Handling the
+// same event again does nothing.
+func (s *service) HandleUserDeleted(ctx context.Context, msg *proto.UserDeleted) error {
+	if msg.UserID == "" {
+		s.logger.Warn("Skipping user deleted event without user id")
+
+		return nil
+	}
+
+	providers, err := s.userProviders(msg.UserID)
+	if err != nil {
+		s.logger.Error("Getting providers of deleted user failed with error", zap.Error(err), zap.String("userId", msg.UserID))
+		userLifecycleEvents.WithLabelValues(userDeletedEvent, metricResultError).Inc()
+
+		return err
+	}
+
+	for _, providerId := range providers {
+		keys := s.userStorageKeys(msg.UserID, providerId)
+		if err := s.redis.Del(keys...).Err(); err != nil {
+			s.logger.Error(
+				"Removing enrollment of deleted user failed with error",
+				zap.Error(err),
+				zap.String("userId", msg.UserID),
+				zap.String("providerId", providerId),
+			)
+			userLifecycleEvents.WithLabelValues(userDeletedEvent, metricResultError).Inc()
+
+			return err
+		}
+
+		s.logger.Info("Enrollment of deleted user removed", zap.String("userId", msg.UserID), zap.String("providerId", providerId))
+		userLifecycleEnrollments.WithLabelValues(userDeletedEvent).Inc()
+
+		s.audit(&proto.AuditEvent{
+			Type:       AuditEnrollmentRemoved,
+			UserID:     msg.UserID,
+			ProviderID: providerId,
+			Reason:     "user deleted",
+		})
+	}
+
+	if err := s.redis.Del(s.GetSecretStorageKey(msg.UserID), s.GetUserProvidersStorageKey(msg.UserID)).Err(); err != nil {
+		s.logger.Error("Removing secrets of deleted user failed with error", zap.Error(err), zap.String("userId", msg.UserID))
+		userLifecycleEvents.WithLabelValues(userDeletedEvent, metricResultError).Inc()
+
+		return err
+	}
+
+	userLifecycleEvents.WithLabelValues(userDeletedEvent, metricResultSuccess).Inc()
+
+	return nil
+}
+
+// HandleUserMerged moves enrollments of the source user to the target user.
+// Devices of the source whose id the target uses are moved with a new id, a
+// YubiKey enrolled by both is kept as the target enrolled it. Recovery codes
+// of both users stay valid and trusted devices of the source are dropped as
+// their tokens are bound to the source user. Handling the same event again does nothing.
+func (s *service) HandleUserMerged(ctx context.Context, msg *proto.UserMerged) error {
+	if msg.SourceUserID == "" || msg.TargetUserID == "" || msg.SourceUserID == msg.TargetUserID {
+		s.logger.Warn(
+			"Skipping invalid user merged event",
+			zap.String("sourceUserId", msg.SourceUserID),
+			zap.String("targetUserId", msg.TargetUserID),
+		)
+
+		return nil
+	}
+
+	providers, err := s.userProviders(msg.SourceUserID)
+	if err != nil {
+		s.logger.Error("Getting providers of merged user failed with error", zap.Error(err), zap.String("userId", msg.SourceUserID))
+		userLifecycleEvents.WithLabelValues(userMergedEvent, metricResultError).Inc()
+
+		return err
+	}
+
+	for _, providerId := range providers {
+		yubiKeys, err := s.mergeEnrollment(msg.SourceUserID, msg.TargetUserID, providerId)
+		if err != nil {
+			s.logger.Error(
+				"Moving enrollment of merged user failed with error",
+				zap.Error(err),
+				zap.String("sourceUserId", msg.SourceUserID),
+				zap.String("targetUserId", msg.TargetUserID),
+				zap.String("providerId", providerId),
+			)
+			userLifecycleEvents.WithLabelValues(userMergedEvent, metricResultError).Inc()
+
+			return err
+		}
+
+		s.logger.Info(
+			"Enrollment of merged user moved",
+			zap.String("sourceUserId", msg.SourceUserID),
+			zap.String("targetUserId", msg.TargetUserID),
+			zap.String("providerId", providerId),
+		)
+		userLifecycleEnrollments.WithLabelValues(userMergedEvent).Inc()
+
+		s.audit(&proto.AuditEvent{
+			Type:       AuditEnrollmentRemoved,
+			UserID:     msg.SourceUserID,
+			ProviderID: providerId,
+			Reason:     "user merged into " + msg.TargetUserID,
+		})
+		reason := "user merged from " + msg.SourceUserID
+		if len(yubiKeys) > 0 {
+			reason += ", yubikeys enrolled by both kept: " + strings.Join(yubiKeys, ",")
+		}
+		s.audit(&proto.AuditEvent{
+			Type:       AuditEnrollmentCreated,
+			UserID:     msg.TargetUserID,
+			ProviderID: providerId,
+			Reason:     reason,
+		})
+	}
+
+	userLifecycleEvents.WithLabelValues(userMergedEvent, metricResultSuccess).Inc()
+
+	return nil
+}
+
+// mergeEnrollment moves the enrollment of the source user for the provider to
+// the target user in one transaction, which fails when either is changed
+// meanwhile. It returns the YubiKeys enrolled by both users.
+func (s *service) mergeEnrollment(sourceId string, targetId string, providerId string) ([]string, error) {
+	// Moves legacy secrets of both users to the device storage.
+	for _, userId := range []string{sourceId, targetId} {
+		if _, err := s.loadDevices(userId, providerId); err != nil {
+			return nil, err
+		}
+	}
+
+	sourceDevices := s.GetDeviceStorageKey(sourceId, providerId)
+	sourceYubiKeys := s.GetYubiKeyStorageKey(sourceId, providerId)
+	targetDevices := s.GetDeviceStorageKey(targetId, providerId)
+	targetYubiKeys := s.GetYubiKeyStorageKey(targetId, providerId)
+
+	var collisions []string
+	var renamed map[string]string
+	err := s.redis.Watch(func(tx *redis.Tx) error {
+		collisions, renamed = nil, map[string]string{}
+
+		devices, err := tx.HGetAll(sourceDevices).Result()
+		if err != nil {
+			return err
+		}
+		yubiKeys, err := tx.HGetAll(sourceYubiKeys).Result()
+		if err != nil {
+			return err
+		}
+		deviceIds, err := tx.HKeys(targetDevices).Result()
+		if err != nil {
+			return err
+		}
+		yubiKeyIds, err := tx.HKeys(targetYubiKeys).Result()
+		if err != nil {
+			return err
+		}
+
+		moved := map[string]interface{}{}
+		for id, data := range devices {
+			if !containsString(deviceIds, id) {
+				moved[id] = data
+				continue
+			}
+
+			d := &device{}
+			if err := json.Unmarshal([]byte(data), d); err != nil {
+				return err
+			}
+			if d.ID, err = newDeviceID(); err != nil {
+				return err
+			}
+			b, err := json.Marshal(d)
+			if err != nil {
+				return err
+			}
+			moved[d.ID] = string(b)
+			renamed[id] = d.ID
+		}
+
+		movedYubiKeys := map[string]interface{}{}
+		for id, data := range yubiKeys {
+			if containsString(yubiKeyIds, id) {
+				collisions = append(collisions, id)
+				continue
+			}
+			movedYubiKeys[id] = data
+		}
+		sort.Strings(collisions)
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.SAdd(s.GetUserProvidersStorageKey(targetId), providerId)
+			if len(moved) > 0 {
+				pipe.HMSet(targetDevices, moved)
+			}
+			if len(movedYubiKeys) > 0 {
+				pipe.HMSet(targetYubiKeys, movedYubiKeys)
+			}
+			pipe.SUnionStore(
+				s.GetRecoveryStorageKey(targetId, providerId),
+				s.GetRecoveryStorageKey(targetId, providerId),
+				s.GetRecoveryStorageKey(sourceId, providerId),
+			)
+			pipe.Del(s.userStorageKeys(sourceId, providerId)...)
+			pipe.SRem(s.GetUserProvidersStorageKey(sourceId), providerId)
+			return nil
+		})
+		return err
+	}, sourceDevices, sourceYubiKeys, targetDevices, targetYubiKeys, s.GetRecoveryStorageKey(sourceId, providerId))
+	if err != nil {
+		return nil, err
+	}
+
+	for id, newId := range renamed {
+		s.logger.Info(
+			"Device of merged user moved with a new id",
+			zap.String("sourceUserId", sourceId),
+			zap.String("targetUserId", targetId),
+			zap.String("providerId", providerId),
+			zap.String("deviceId", id),
+			zap.String("newDeviceId", newId),
+		)
+	}
+	if len(collisions) > 0 {
+		s.logger.Warn(
+			"YubiKeys enrolled by both merged users kept as the target enrolled them",
+			zap.String("sourceUserId", sourceId),
+			zap.String("targetUserId", targetId),
+			zap.String("providerId", providerId),
+			zap.Strings("yubiKeys", collisions),
+		)
+	}
+
+	return collisions, nil
+}
+
+// userProviders returns the providers the user has any storage for. Until the
+// index is built the storage keys are scanned as well.
+func (s *service) userProviders(userId string) ([]string, error) {
+	found := map[string]bool{}
+
+	indexed, err := s.redis.SMembers(s.GetUserProvidersStorageKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := s.redis.HKeys(s.GetSecretStorageKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, providerId := range append(indexed, legacy...) {
+		found[providerId] = true
+	}
+
+	built, err := s.redis.Exists(mfaUserProvidersIndexBuilt).Result()
+	if err != nil {
+		return nil, err
+	}
+	if built == 0 {
+		if err = s.scanUserProviders(userId, found); err != nil {
+			return nil, err
+		}
+	}
+
+	providers := make([]string, 0, len(found))
+	for providerId := range found {
+		providers = append(providers, providerId)
+	}
+	sort.Strings(providers)
+
+	return providers, nil
+}
+
+// scanUserProviders adds the providers of the storage keys of the user to
+// found. Storage keys join ids with "_", so provider ids containing it are
+// not recognized.
+func (s *service) scanUserProviders(userId string, found map[string]bool) error {
+	for _, pattern := range userStoragePatterns {
+		prefix := fmt.Sprintf(pattern, userId, "")
+		match := fmt.Sprintf(pattern, escapeGlob(userId), "*")
+
+		err := s.scanKeys(match, func(key string) error {
+			providerId := strings.TrimPrefix(key, prefix)
+			// The key may belong to another user whose id starts with this one followed by "_".
+			if strings.Contains(providerId, "_") {
+				s.logger.Warn("Skipping ambiguous storage key", zap.String("userId", userId), zap.String("providerId", providerId))
+				return nil
+			}
+			found[providerId] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IndexUserProviders adds the storage written before the provider index of
+// the users existed to the index. It scans the keyspace once, later calls do
+// nothing. Storage keys are split at the last "_", so provider ids containing
+// it are not recognized.
+func (s *service) IndexUserProviders(ctx context.Context) error {
+	built, err := s.redis.Exists(mfaUserProvidersIndexBuilt).Result()
+	if err != nil || built > 0 {
+		return err
+	}
+
+	for _, pattern := range userStoragePatterns {
+		prefix := pattern[:strings.Index(pattern, "%s")]
+
+		err := s.scanKeys(fmt.Sprintf(pattern, "*", "*"), func(key string) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			rest := strings.TrimPrefix(key, prefix)
+			if i := strings.LastIndex(rest, "_"); i > 0 {
+				return s.redis.SAdd(s.GetUserProvidersStorageKey(rest[:i]), rest[i+1:]).Err()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = s.redis.Set(mfaUserProvidersIndexBuilt, time.Now().Unix(), 0).Err(); err != nil {
+		return err
+	}
+
+	s.logger.Info("Provider index of the users built")
+
+	return nil
+}
+
+func (s *service) GetUserProvidersStorageKey(userId string) string {
+	return fmt.Sprintf(mfaUserProvidersStoragePattern, userId)
+}
+
+func (s *service) userStorageKeys(userId string, providerId string) []string {
+	keys := make([]string, 0, len(userStoragePatterns))
+	for _, pattern := range userStoragePatterns {
+		keys = append(keys, fmt.Sprintf(pattern, userId, providerId))
+	}
+	return keys
+}
+
+func escapeGlob(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,153 @@
+package mfa
+
+import (
+	"context"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"time"
+)
+
+func (suite *ServiceTestSuite) TestHandleUserDeletedToRemoveEnrollments() {
+	otherProviderID := suite.ProviderID + "0"
+	defer suite.redis.Del(suite.service.userStorageKeys(suite.userID, otherProviderID)...)
+
+	suite.createDevice("")
+	suite.addYubiKey()
+	_ = suite.service.Create(
+		context.TODO(),
+		&proto.MfaCreateDataRequest{ProviderID: otherProviderID, AppName: "test", UserID: suite.userID},
+		&proto.MfaCreateDataResponse{},
+	)
+	suite.redis.HSet(suite.service.GetSecretStorageKey(suite.userID), "legacy", "secret")
+
+	err := suite.service.HandleUserDeleted(context.TODO(), &proto.UserDeleted{UserID: suite.userID})
+	assert.NoError(suite.T(), err)
+
+	keys := append(suite.service.userStorageKeys(suite.userID, suite.ProviderID), suite.service.userStorageKeys(suite.userID, otherProviderID)...)
+	keys = append(keys, suite.service.GetSecretStorageKey(suite.userID))
+	exists, _ := suite.redis.Exists(keys...).Result()
+	assert.Equal(suite.T(), int64(0), exists)
+
+	err = suite.service.HandleUserDeleted(context.TODO(), &proto.UserDeleted{UserID: suite.userID})
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *ServiceTestSuite) TestHandleUserDeletedToKeepOtherUsers() {
+	otherUserID := suite.userID + "_1"
+	defer suite.redis.Del(suite.service.userStorageKeys(otherUserID, suite.ProviderID)...)
+
+	_ = suite.service.Create(
+		context.TODO(),
+		&proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, AppName: "test", UserID: otherUserID},
+		&proto.MfaCreateDataResponse{},
+	)
+
+	err := suite.service.HandleUserDeleted(context.TODO(), &proto.UserDeleted{UserID: suite.userID})
+	assert.NoError(suite.T(), err)
+
+	exists, _ := suite.redis.Exists(suite.service.GetDeviceStorageKey(otherUserID, suite.ProviderID)).Result()
+	assert.Equal(suite.T(), int64(1), exists)
+}
+
+func (suite *ServiceTestSuite) TestHandleUserMergedToMoveEnrollments() {
+	sourceUserID := suite.userID + "0"
+	defer suite.redis.Del(suite.service.userStorageKeys(sourceUserID, suite.ProviderID)...)
+
+	target := suite.createDevice("target")
+	source := &proto.MfaCreateDataResponse{}
+	_ = suite.service.Create(
+		context.TODO(),
+		&proto.MfaCreateDataRequest{ProviderID: suite.ProviderID, AppName: "test", UserID: sourceUserID, DeviceName: "source"},
+		source,
+	)
+
+	msg := &proto.UserMerged{SourceUserID: sourceUserID, TargetUserID: suite.userID}
+	assert.NoError(suite.T(), suite.service.HandleUserMerged(context.TODO(), msg))
+	assert.NoError(suite.T(), suite.service.HandleUserMerged(context.TODO(), msg))
+
+	list := &proto.MfaListDevicesDataResponse{}
+	_ = suite.service.ListDevices(context.TODO(), &proto.MfaListDevicesDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID}, list)
+	assert.Equal(suite.T(), 2, len(list.Devices))
+
+	code, _ := totp.GenerateCode(source.SecretKey, time.Now())
+	res := &proto.MfaCheckDataResponse{}
+	_ = suite.service.Check(context.TODO(), &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}, res)
+	assert.True(suite.T(), res.Result)
+	assert.Equal(suite.T(), source.DeviceID, res.DeviceID)
+
+	codes, _ := suite.redis.SCard(suite.service.GetRecoveryStorageKey(suite.userID, suite.ProviderID)).Result()
+	assert.Equal(suite.T(), int64(len(target.RecoveryCode)+len(source.RecoveryCode)), codes)
+
+	exists, _ := suite.redis.Exists(suite.service.userStorageKeys(sourceUserID, suite.ProviderID)...).Result()
+	assert.Equal(suite.T(), int64(0), exists)
+}
+
+func (suite *ServiceTestSuite) TestHandleUserMergedToMoveCollidingDevicesWithNewId() {
+	sourceUserID := suite.userID + "0"
+	defer suite.redis.Del(suite.service.GetSecretStorageKey(sourceUserID), suite.service.GetUserProvidersStorageKey(sourceUserID))
+	defer suite.redis.Del(suite.service.userStorageKeys(sourceUserID, suite.ProviderID)...)
+
+	targetSecret, sourceSecret := "JBSWY3DPEHPK3PXP", "KRSXG5CTMVRXEZLU"
+	suite.redis.HSet(suite.service.GetSecretStorageKey(suite.userID), suite.ProviderID, targetSecret)
+	suite.redis.HSet(suite.service.GetSecretStorageKey(sourceUserID), suite.ProviderID, sourceSecret)
+
+	msg := &proto.UserMerged{SourceUserID: sourceUserID, TargetUserID: suite.userID}
+	assert.NoError(suite.T(), suite.service.HandleUserMerged(context.TODO(), msg))
+
+	devices, err := suite.service.loadDevices(suite.userID, suite.ProviderID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(devices))
+
+	secrets := map[string]string{}
+	for _, d := range devices {
+		secrets[d.Secret] = d.ID
+	}
+	assert.Equal(suite.T(), legacyDeviceID, secrets[targetSecret])
+	assert.NotEmpty(suite.T(), secrets[sourceSecret])
+	assert.NotEqual(suite.T(), legacyDeviceID, secrets[sourceSecret])
+}
+
+func (suite *ServiceTestSuite) TestHandleUserMergedToKeepYubiKeyOfTarget() {
+	sourceUserID := suite.userID + "0"
+	defer suite.redis.Del(suite.service.GetUserProvidersStorageKey(sourceUserID))
+	defer suite.redis.Del(suite.service.userStorageKeys(sourceUserID, suite.ProviderID)...)
+
+	suite.addYubiKey()
+	target, _ := suite.redis.HGet(suite.service.GetYubiKeyStorageKey(suite.userID, suite.ProviderID), testYubiKeyPublicID).Result()
+	suite.redis.HSet(suite.service.GetYubiKeyStorageKey(sourceUserID, suite.ProviderID), testYubiKeyPublicID, `{"private_id":"source"}`)
+	suite.redis.SAdd(suite.service.GetUserProvidersStorageKey(sourceUserID), suite.ProviderID)
+
+	msg := &proto.UserMerged{SourceUserID: sourceUserID, TargetUserID: suite.userID}
+	assert.NoError(suite.T(), suite.service.HandleUserMerged(context.TODO(), msg))
+
+	yubiKeys, _ := suite.redis.HGetAll(suite.service.GetYubiKeyStorageKey(suite.userID, suite.ProviderID)).Result()
+	assert.Equal(suite.T(), map[string]string{testYubiKeyPublicID: target}, yubiKeys)
+
+	exists, _ := suite.redis.Exists(suite.service.GetYubiKeyStorageKey(sourceUserID, suite.ProviderID), suite.service.GetUserProvidersStorageKey(sourceUserID)).Result()
+	assert.Equal(suite.T(), int64(0), exists)
+}
+
+func (suite *ServiceTestSuite) TestIndexUserProvidersToIndexStorageWrittenBefore() {
+	otherProviderID := suite.ProviderID + "0"
+	defer suite.redis.Del(mfaUserProvidersIndexBuilt)
+	defer suite.redis.Del(suite.service.userStorageKeys(suite.userID, otherProviderID)...)
+
+	suite.createDevice("")
+	// Written by a version of the service without the index.
+	suite.redis.Set(suite.service.GetBypassStorageKey(suite.userID, otherProviderID), "{}", time.Minute)
+
+	assert.NoError(suite.T(), suite.service.IndexUserProviders(context.TODO()))
+
+	providers, err := suite.redis.SMembers(suite.service.GetUserProvidersStorageKey(suite.userID)).Result()
+	assert.NoError(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []string{suite.ProviderID, otherProviderID}, providers)
+
+	err = suite.service.HandleUserDeleted(context.TODO(), &proto.UserDeleted{UserID: suite.userID})
+	assert.NoError(suite.T(), err)
+
+	keys := append(suite.service.userStorageKeys(suite.userID, suite.ProviderID), suite.service.userStorageKeys(suite.userID, otherProviderID)...)
+	keys = append(keys, suite.service.GetUserProvidersStorageKey(suite.userID))
+	exists, _ := suite.redis.Exists(keys...).Result()
+	assert.Equal(suite.T(), int64(0), exists)
+}
@@ -0,0 +1,315 @@
+package mfa
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+	"strings"
+)
+
+const (
+	mfaYubiKeyStoragePattern = "mfa_yubikey_%s_%s"
+
+	yubiKeyModhexAlphabet = "cbdefghijklnrtuv"
+	yubiKeyOtpLength      = 44
+	yubiKeyPublicIDLength = 12
+	yubiKeyPrivateIDSize  = 6
+	yubiKeyAesKeySize     = 16
+	yubiKeyCrcResidue     = 0xf0b8
+
+	ErrorYubiKeyNotExists      = "YubiKey not exists"
+	ErrorYubiKeyReplayed       = "YubiKey OTP already used"
+	ErrorRequestPropertyFormat = "%s has invalid format"
+)
+
+// yubiKey is the stored state of a registered YubiKey. Counters hold the
+// last accepted usage and session counters and are used for replay detection.
+type yubiKey struct {
+	PrivateID      string `json:"private_id"`
+	AesKey         string `json:"aes_key"`
+	UsageCounter   uint16 `json:"usage_counter"`
+	SessionCounter uint8  `json:"session_counter"`
+}
+
+// yubiKeyToken is the decrypted payload of a Yubico OTP.
+type yubiKeyToken struct {
+	PrivateID      []byte
+	UsageCounter   uint16
+	Timestamp      uint32
+	SessionCounter uint8
+	Random         uint16
+}
+
+func (s *service) AddYubiKey(ctx context.Context, req *proto.MfaAddYubiKeyDataRequest, res *proto.MfaAddYubiKeyDataResponse) error {
+	s = s.withContext(ctx)
+
+	if err := s.validateAddYubiKeyRequest(req); err != nil {
+		s.logger.Error("Validate add yubikey request failed with error", zap.Error(err))
+
+		return err
+	}
+
+	config, err := s.providerConfig(req.ProviderID)
+	if err != nil {
+		return err
+	}
+	if !factorAllowed(config, MethodYubiKey) {
+		res.Error = &proto.Error{
+			Message: ErrorFactorNotAllowed,
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(&yubiKey{
+		PrivateID: strings.ToLower(req.PrivateID),
+		AesKey:    strings.ToLower(req.AesKey),
+	})
+	if err != nil {
+		s.logger.Error("Marshal yubikey failed with error", zap.Error(err))
+
+		return err
+	}
+
+	_, err = s.redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(s.GetUserProvidersStorageKey(req.UserID), req.ProviderID)
+		pipe.HSet(s.GetYubiKeyStorageKey(req.UserID, req.ProviderID), req.PublicID, data)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Add yubikey to Redis failed with error", zap.Error(err))
+
+		return err
+	}
+
+	s.audit(&proto.AuditEvent{
+		Type:       AuditEnrollmentCreated,
+		UserID:     req.UserID,
+		ProviderID: req.ProviderID,
+		Method:     MethodYubiKey,
+		DeviceID:   req.PublicID,
+	})
+
+	res.Result = true
+
+	return nil
+}
+
+func (s *service) checkYubiKey(req *proto.MfaCheckDataRequest, res *proto.MfaCheckDataResponse) error {
+	key := s.GetYubiKeyStorageKey(req.UserID, req.ProviderID)
+	publicID := req.Code[:yubiKeyPublicIDLength]
+
+	err := s.redis.Watch(func(tx *redis.Tx) error {
+		data, err := tx.HGet(key, publicID).Bytes()
+		if err != nil {
+			return err
+		}
+
+		yk := &yubiKey{}
+		if err = json.Unmarshal(data, yk); err != nil {
+			return err
+		}
+
+		token, err := yk.decrypt(req.Code)
+		if err != nil {
+			res.Error = &proto.Error{Message: ErrorCodeInvalid}
+			return nil
+		}
+
+		if !yk.accepts(token) {
+			res.Error = &proto.Error{Message: ErrorYubiKeyReplayed}
+			return nil
+		}
+
+		yk.UsageCounter = token.UsageCounter
+		yk.SessionCounter = token.SessionCounter
+		if data, err = json.Marshal(yk); err != nil {
+			return err
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HSet(key, publicID, data)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		res.Result = true
+		res.DeviceID = publicID
+
+		return nil
+	}, key)
+
+	if err == redis.Nil {
+		s.logger.Warn(
+			"YubiKey is not registered",
+			zap.String("userId", req.UserID),
+			zap.String("providerId", req.ProviderID),
+			zap.String("publicId", publicID),
+		)
+
+		res.Error = &proto.Error{
+			Message: ErrorYubiKeyNotExists,
+		}
+		return err
+	}
+
+	if err != nil {
+		s.logger.Error("Validating YubiKey OTP failed with error", zap.Error(err))
+
+		res.Result = false
+		return err
+	}
+
+	if res.Error != nil {
+		s.logger.Warn(
+			"YubiKey OTP rejected",
+			zap.String("reason", res.Error.Message),
+			zap.String("userId", req.UserID),
+			zap.String("providerId", req.ProviderID),
+			zap.String("publicId", publicID),
+		)
+	}
+
+	return nil
+}
+
+// decrypt decodes the modhex OTP, decrypts it with the stored AES key and
+// verifies the checksum and the private identity of the token.
+func (yk *yubiKey) decrypt(otp string) (*yubiKeyToken, error) {
+	key, err := hex.DecodeString(yk.AesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateID, err := hex.DecodeString(yk.PrivateID)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := decodeModhex(otp[yubiKeyPublicIDLength:])
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plain := make([]byte, aes.BlockSize)
+	block.Decrypt(plain, encrypted)
+
+	if crc16(plain) != yubiKeyCrcResidue {
+		return nil, errors.New("yubikey otp checksum mismatch")
+	}
+
+	token := &yubiKeyToken{
+		PrivateID:      plain[0:6],
+		UsageCounter:   binary.LittleEndian.Uint16(plain[6:8]),
+		Timestamp:      uint32(plain[8]) | uint32(plain[9])<<8 | uint32(plain[10])<<16,
+		SessionCounter: plain[11],
+		Random:         binary.LittleEndian.Uint16(plain[12:14]),
+	}
+
+	if subtle.ConstantTimeCompare(token.PrivateID, privateID) != 1 {
+		return nil, errors.New("yubikey otp private id mismatch")
+	}
+
+	return token, nil
+}
+
+// accepts reports whether the token counters are strictly greater than the
+// last accepted ones, which rejects replayed OTPs.
+func (yk *yubiKey) accepts(token *yubiKeyToken) bool {
+	if token.UsageCounter != yk.UsageCounter {
+		return token.UsageCounter > yk.UsageCounter
+	}
+	return token.SessionCounter > yk.SessionCounter
+}
+
+func (s *service) validateAddYubiKeyRequest(req *proto.MfaAddYubiKeyDataRequest) error {
+	if req.ProviderID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "ProviderID")
+	}
+	if req.UserID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "UserID")
+	}
+	if req.PublicID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "PublicID")
+	}
+	if req.PrivateID == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "PrivateID")
+	}
+	if req.AesKey == "" {
+		return newRequestError(ErrorRequestPropertyRequired, "AesKey")
+	}
+	if len(req.PublicID) != yubiKeyPublicIDLength || !isModhex(req.PublicID) {
+		return newRequestError(ErrorRequestPropertyFormat, "PublicID")
+	}
+	if b, err := hex.DecodeString(req.PrivateID); err != nil || len(b) != yubiKeyPrivateIDSize {
+		return newRequestError(ErrorRequestPropertyFormat, "PrivateID")
+	}
+	if b, err := hex.DecodeString(req.AesKey); err != nil || len(b) != yubiKeyAesKeySize {
+		return newRequestError(ErrorRequestPropertyFormat, "AesKey")
+	}
+	return nil
+}
+
+func (s *service) GetYubiKeyStorageKey(userId string, providerId string) string {
+	return fmt.Sprintf(mfaYubiKeyStoragePattern, userId, providerId)
+}
+
+func isYubiKeyOTP(code string) bool {
+	return len(code) == yubiKeyOtpLength && isModhex(code)
+}
+
+func isModhex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune(yubiKeyModhexAlphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeModhex(s string) ([]byte, error) {
+	if len(s)%2 != 0 {
+		return nil, errors.New("modhex string has odd length")
+	}
+
+	out := make([]byte, len(s)/2)
+	for i := 0; i < len(out); i++ {
+		hi := strings.IndexByte(yubiKeyModhexAlphabet, s[2*i])
+		lo := strings.IndexByte(yubiKeyModhexAlphabet, s[2*i+1])
+		if hi < 0 || lo < 0 {
+			return nil, errors.New("invalid modhex character")
+		}
+		out[i] = byte(hi<<4 | lo)
+	}
+	return out, nil
+}
+
+// crc16 is the ISO 13239 checksum used by Yubico OTP tokens.
+func crc16(data []byte) uint16 {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			n := crc & 1
+			crc >>= 1
+			if n != 0 {
+				crc ^= 0x8408
+			}
+		}
+	}
+	return crc
+}
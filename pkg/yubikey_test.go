@@ -0,0 +1,146 @@
+package mfa
+
+import (
+	"context"
+	"crypto/aes"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/ProtocolONE/mfa-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strings"
+)
+
+const (
+	testYubiKeyPublicID  = "vvccccfiluij"
+	testYubiKeyPrivateID = "8792ebfe26cc"
+	testYubiKeyAesKey    = "ecde18dbe76fbd0c33330f1c354871db"
+)
+
+func (suite *ServiceTestSuite) TestAddYubiKeyToReturnErrorRequestData() {
+	reqs := []proto.MfaAddYubiKeyDataRequest{
+		{ProviderID: "", UserID: "1", PublicID: testYubiKeyPublicID, PrivateID: testYubiKeyPrivateID, AesKey: testYubiKeyAesKey},
+		{ProviderID: "1", UserID: "", PublicID: testYubiKeyPublicID, PrivateID: testYubiKeyPrivateID, AesKey: testYubiKeyAesKey},
+		{ProviderID: "1", UserID: "1", PublicID: "", PrivateID: testYubiKeyPrivateID, AesKey: testYubiKeyAesKey},
+		{ProviderID: "1", UserID: "1", PublicID: testYubiKeyPublicID, PrivateID: "", AesKey: testYubiKeyAesKey},
+		{ProviderID: "1", UserID: "1", PublicID: testYubiKeyPublicID, PrivateID: testYubiKeyPrivateID, AesKey: ""},
+	}
+	for _, req := range reqs {
+		err := suite.service.AddYubiKey(context.TODO(), &req, &proto.MfaAddYubiKeyDataResponse{})
+		assert.Regexp(suite.T(), regexp.MustCompile("is required field"), err)
+	}
+}
+
+func (suite *ServiceTestSuite) TestAddYubiKeyToReturnErrorInvalidFormat() {
+	reqs := []proto.MfaAddYubiKeyDataRequest{
+		{ProviderID: "1", UserID: "1", PublicID: "abcdefabcdef", PrivateID: testYubiKeyPrivateID, AesKey: testYubiKeyAesKey},
+		{ProviderID: "1", UserID: "1", PublicID: testYubiKeyPublicID, PrivateID: "8792eb", AesKey: testYubiKeyAesKey},
+		{ProviderID: "1", UserID: "1", PublicID: testYubiKeyPublicID, PrivateID: testYubiKeyPrivateID, AesKey: "zz"},
+	}
+	for _, req := range reqs {
+		err := suite.service.AddYubiKey(context.TODO(), &req, &proto.MfaAddYubiKeyDataResponse{})
+		assert.Regexp(suite.T(), regexp.MustCompile("has invalid format"), err)
+	}
+}
+
+func (suite *ServiceTestSuite) TestCheckToReturnTrueWithYubiKey() {
+	suite.addYubiKey()
+
+	res := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: generateYubiKeyOTP(1, 0)}
+	err := suite.service.Check(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.Result)
+	assert.Nil(suite.T(), res.Error)
+}
+
+func (suite *ServiceTestSuite) TestCheckToReturnFalseWithReplayedYubiKey() {
+	suite.addYubiKey()
+	code := generateYubiKeyOTP(2, 5)
+
+	res1 := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: code}
+	_ = suite.service.Check(context.TODO(), req, res1)
+	assert.True(suite.T(), res1.Result)
+
+	res2 := &proto.MfaCheckDataResponse{}
+	err := suite.service.Check(context.TODO(), req, res2)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res2.Result)
+	assert.Equal(suite.T(), ErrorYubiKeyReplayed, res2.Error.Message)
+
+	res3 := &proto.MfaCheckDataResponse{}
+	req.Code = generateYubiKeyOTP(2, 4)
+	_ = suite.service.Check(context.TODO(), req, res3)
+	assert.False(suite.T(), res3.Result)
+
+	res4 := &proto.MfaCheckDataResponse{}
+	req.Code = generateYubiKeyOTP(3, 0)
+	_ = suite.service.Check(context.TODO(), req, res4)
+	assert.True(suite.T(), res4.Result)
+}
+
+func (suite *ServiceTestSuite) TestCheckToReturnFalseWithForeignYubiKey() {
+	suite.addYubiKey()
+
+	code := []byte(generateYubiKeyOTP(1, 0))
+	code[20] = yubiKeyModhexAlphabet[(strings.IndexByte(yubiKeyModhexAlphabet, code[20])+1)%16]
+
+	res := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: string(code)}
+	err := suite.service.Check(context.TODO(), req, res)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorCodeInvalid, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) TestCheckToReturnFalseWithoutYubiKey() {
+	res := &proto.MfaCheckDataResponse{}
+	req := &proto.MfaCheckDataRequest{ProviderID: suite.ProviderID, UserID: suite.userID, Code: generateYubiKeyOTP(1, 0)}
+	err := suite.service.Check(context.TODO(), req, res)
+
+	assert.Error(suite.T(), err)
+	assert.False(suite.T(), res.Result)
+	assert.Equal(suite.T(), ErrorYubiKeyNotExists, res.Error.Message)
+}
+
+func (suite *ServiceTestSuite) addYubiKey() {
+	req := &proto.MfaAddYubiKeyDataRequest{
+		ProviderID: suite.ProviderID,
+		UserID:     suite.userID,
+		PublicID:   testYubiKeyPublicID,
+		PrivateID:  testYubiKeyPrivateID,
+		AesKey:     testYubiKeyAesKey,
+	}
+	err := suite.service.AddYubiKey(context.TODO(), req, &proto.MfaAddYubiKeyDataResponse{})
+	assert.NoError(suite.T(), err)
+}
+
+func generateYubiKeyOTP(usage uint16, session uint8) string {
+	plain := make([]byte, aes.BlockSize)
+	privateID, _ := hex.DecodeString(testYubiKeyPrivateID)
+	copy(plain, privateID)
+	binary.LittleEndian.PutUint16(plain[6:8], usage)
+	plain[8], plain[9], plain[10] = 0x01, 0x02, 0x03
+	plain[11] = session
+	binary.LittleEndian.PutUint16(plain[12:14], 0xbeef)
+	binary.LittleEndian.PutUint16(plain[14:16], ^crc16(plain[:14]))
+
+	key, _ := hex.DecodeString(testYubiKeyAesKey)
+	block, _ := aes.NewCipher(key)
+	encrypted := make([]byte, aes.BlockSize)
+	block.Encrypt(encrypted, plain)
+
+	return testYubiKeyPublicID + encodeModhex(encrypted)
+}
+
+func encodeModhex(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		sb.WriteByte(yubiKeyModhexAlphabet[c>>4])
+		sb.WriteByte(yubiKeyModhexAlphabet[c&0x0f])
+	}
+	return sb.String()
+}